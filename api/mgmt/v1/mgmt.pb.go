@@ -25,7 +25,10 @@ package mgmtv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -92,6 +95,196 @@ func (SignerType) EnumDescriptor() ([]byte, []int) {
 	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{0}
 }
 
+type KeyUsage int32
+
+const (
+	KeyUsage_KEY_USAGE_UNSPECIFIED        KeyUsage = 0
+	KeyUsage_KEY_USAGE_DIGITAL_SIGNATURE  KeyUsage = 1
+	KeyUsage_KEY_USAGE_CONTENT_COMMITMENT KeyUsage = 2
+	// dropped for non-RSA keys
+	KeyUsage_KEY_USAGE_KEY_ENCIPHERMENT  KeyUsage = 3
+	KeyUsage_KEY_USAGE_DATA_ENCIPHERMENT KeyUsage = 4
+	KeyUsage_KEY_USAGE_KEY_AGREEMENT     KeyUsage = 5
+	KeyUsage_KEY_USAGE_CERT_SIGN         KeyUsage = 6
+	KeyUsage_KEY_USAGE_CRL_SIGN          KeyUsage = 7
+)
+
+// Enum value maps for KeyUsage.
+var (
+	KeyUsage_name = map[int32]string{
+		0: "KEY_USAGE_UNSPECIFIED",
+		1: "KEY_USAGE_DIGITAL_SIGNATURE",
+		2: "KEY_USAGE_CONTENT_COMMITMENT",
+		3: "KEY_USAGE_KEY_ENCIPHERMENT",
+		4: "KEY_USAGE_DATA_ENCIPHERMENT",
+		5: "KEY_USAGE_KEY_AGREEMENT",
+		6: "KEY_USAGE_CERT_SIGN",
+		7: "KEY_USAGE_CRL_SIGN",
+	}
+	KeyUsage_value = map[string]int32{
+		"KEY_USAGE_UNSPECIFIED":        0,
+		"KEY_USAGE_DIGITAL_SIGNATURE":  1,
+		"KEY_USAGE_CONTENT_COMMITMENT": 2,
+		"KEY_USAGE_KEY_ENCIPHERMENT":   3,
+		"KEY_USAGE_DATA_ENCIPHERMENT":  4,
+		"KEY_USAGE_KEY_AGREEMENT":      5,
+		"KEY_USAGE_CERT_SIGN":          6,
+		"KEY_USAGE_CRL_SIGN":           7,
+	}
+)
+
+func (x KeyUsage) Enum() *KeyUsage {
+	p := new(KeyUsage)
+	*p = x
+	return p
+}
+
+func (x KeyUsage) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (KeyUsage) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_mgmt_v1_mgmt_proto_enumTypes[1].Descriptor()
+}
+
+func (KeyUsage) Type() protoreflect.EnumType {
+	return &file_api_mgmt_v1_mgmt_proto_enumTypes[1]
+}
+
+func (x KeyUsage) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use KeyUsage.Descriptor instead.
+func (KeyUsage) EnumDescriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{1}
+}
+
+type ExtKeyUsage int32
+
+const (
+	ExtKeyUsage_EXT_KEY_USAGE_UNSPECIFIED      ExtKeyUsage = 0
+	ExtKeyUsage_EXT_KEY_USAGE_SERVER_AUTH      ExtKeyUsage = 1
+	ExtKeyUsage_EXT_KEY_USAGE_CLIENT_AUTH      ExtKeyUsage = 2
+	ExtKeyUsage_EXT_KEY_USAGE_CODE_SIGNING     ExtKeyUsage = 3
+	ExtKeyUsage_EXT_KEY_USAGE_EMAIL_PROTECTION ExtKeyUsage = 4
+	ExtKeyUsage_EXT_KEY_USAGE_TIME_STAMPING    ExtKeyUsage = 5
+	ExtKeyUsage_EXT_KEY_USAGE_OCSP_SIGNING     ExtKeyUsage = 6
+)
+
+// Enum value maps for ExtKeyUsage.
+var (
+	ExtKeyUsage_name = map[int32]string{
+		0: "EXT_KEY_USAGE_UNSPECIFIED",
+		1: "EXT_KEY_USAGE_SERVER_AUTH",
+		2: "EXT_KEY_USAGE_CLIENT_AUTH",
+		3: "EXT_KEY_USAGE_CODE_SIGNING",
+		4: "EXT_KEY_USAGE_EMAIL_PROTECTION",
+		5: "EXT_KEY_USAGE_TIME_STAMPING",
+		6: "EXT_KEY_USAGE_OCSP_SIGNING",
+	}
+	ExtKeyUsage_value = map[string]int32{
+		"EXT_KEY_USAGE_UNSPECIFIED":      0,
+		"EXT_KEY_USAGE_SERVER_AUTH":      1,
+		"EXT_KEY_USAGE_CLIENT_AUTH":      2,
+		"EXT_KEY_USAGE_CODE_SIGNING":     3,
+		"EXT_KEY_USAGE_EMAIL_PROTECTION": 4,
+		"EXT_KEY_USAGE_TIME_STAMPING":    5,
+		"EXT_KEY_USAGE_OCSP_SIGNING":     6,
+	}
+)
+
+func (x ExtKeyUsage) Enum() *ExtKeyUsage {
+	p := new(ExtKeyUsage)
+	*p = x
+	return p
+}
+
+func (x ExtKeyUsage) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExtKeyUsage) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_mgmt_v1_mgmt_proto_enumTypes[2].Descriptor()
+}
+
+func (ExtKeyUsage) Type() protoreflect.EnumType {
+	return &file_api_mgmt_v1_mgmt_proto_enumTypes[2]
+}
+
+func (x ExtKeyUsage) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExtKeyUsage.Descriptor instead.
+func (ExtKeyUsage) EnumDescriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{2}
+}
+
+type SubjectField int32
+
+const (
+	SubjectField_SUBJECT_FIELD_UNSPECIFIED         SubjectField = 0
+	SubjectField_SUBJECT_FIELD_COMMON_NAME         SubjectField = 1
+	SubjectField_SUBJECT_FIELD_ORGANIZATION        SubjectField = 2
+	SubjectField_SUBJECT_FIELD_ORGANIZATIONAL_UNIT SubjectField = 3
+	SubjectField_SUBJECT_FIELD_COUNTRY             SubjectField = 4
+	SubjectField_SUBJECT_FIELD_PROVINCE            SubjectField = 5
+	SubjectField_SUBJECT_FIELD_LOCALITY            SubjectField = 6
+	SubjectField_SUBJECT_FIELD_SERIAL_NUMBER       SubjectField = 7
+)
+
+// Enum value maps for SubjectField.
+var (
+	SubjectField_name = map[int32]string{
+		0: "SUBJECT_FIELD_UNSPECIFIED",
+		1: "SUBJECT_FIELD_COMMON_NAME",
+		2: "SUBJECT_FIELD_ORGANIZATION",
+		3: "SUBJECT_FIELD_ORGANIZATIONAL_UNIT",
+		4: "SUBJECT_FIELD_COUNTRY",
+		5: "SUBJECT_FIELD_PROVINCE",
+		6: "SUBJECT_FIELD_LOCALITY",
+		7: "SUBJECT_FIELD_SERIAL_NUMBER",
+	}
+	SubjectField_value = map[string]int32{
+		"SUBJECT_FIELD_UNSPECIFIED":         0,
+		"SUBJECT_FIELD_COMMON_NAME":         1,
+		"SUBJECT_FIELD_ORGANIZATION":        2,
+		"SUBJECT_FIELD_ORGANIZATIONAL_UNIT": 3,
+		"SUBJECT_FIELD_COUNTRY":             4,
+		"SUBJECT_FIELD_PROVINCE":            5,
+		"SUBJECT_FIELD_LOCALITY":            6,
+		"SUBJECT_FIELD_SERIAL_NUMBER":       7,
+	}
+)
+
+func (x SubjectField) Enum() *SubjectField {
+	p := new(SubjectField)
+	*p = x
+	return p
+}
+
+func (x SubjectField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SubjectField) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_mgmt_v1_mgmt_proto_enumTypes[3].Descriptor()
+}
+
+func (SubjectField) Type() protoreflect.EnumType {
+	return &file_api_mgmt_v1_mgmt_proto_enumTypes[3]
+}
+
+func (x SubjectField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SubjectField.Descriptor instead.
+func (SubjectField) EnumDescriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{3}
+}
+
 type PrivateKeyType int32
 
 const (
@@ -128,11 +321,11 @@ func (x PrivateKeyType) String() string {
 }
 
 func (PrivateKeyType) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_mgmt_v1_mgmt_proto_enumTypes[1].Descriptor()
+	return file_api_mgmt_v1_mgmt_proto_enumTypes[4].Descriptor()
 }
 
 func (PrivateKeyType) Type() protoreflect.EnumType {
-	return &file_api_mgmt_v1_mgmt_proto_enumTypes[1]
+	return &file_api_mgmt_v1_mgmt_proto_enumTypes[4]
 }
 
 func (x PrivateKeyType) Number() protoreflect.EnumNumber {
@@ -141,7 +334,7 @@ func (x PrivateKeyType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PrivateKeyType.Descriptor instead.
 func (PrivateKeyType) EnumDescriptor() ([]byte, []int) {
-	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{1}
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{4}
 }
 
 type RemoteType int32
@@ -179,11 +372,11 @@ func (x RemoteType) String() string {
 }
 
 func (RemoteType) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_mgmt_v1_mgmt_proto_enumTypes[2].Descriptor()
+	return file_api_mgmt_v1_mgmt_proto_enumTypes[5].Descriptor()
 }
 
 func (RemoteType) Type() protoreflect.EnumType {
-	return &file_api_mgmt_v1_mgmt_proto_enumTypes[2]
+	return &file_api_mgmt_v1_mgmt_proto_enumTypes[5]
 }
 
 func (x RemoteType) Number() protoreflect.EnumNumber {
@@ -192,7 +385,135 @@ func (x RemoteType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use RemoteType.Descriptor instead.
 func (RemoteType) EnumDescriptor() ([]byte, []int) {
-	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{2}
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{5}
+}
+
+// RFC 5280 CRLReason, values match the reasonCode extension
+type RevocationReason int32
+
+const (
+	RevocationReason_REVOCATION_REASON_UNSPECIFIED            RevocationReason = 0
+	RevocationReason_REVOCATION_REASON_KEY_COMPROMISE         RevocationReason = 1
+	RevocationReason_REVOCATION_REASON_CA_COMPROMISE          RevocationReason = 2
+	RevocationReason_REVOCATION_REASON_AFFILIATION_CHANGED    RevocationReason = 3
+	RevocationReason_REVOCATION_REASON_SUPERSEDED             RevocationReason = 4
+	RevocationReason_REVOCATION_REASON_CESSATION_OF_OPERATION RevocationReason = 5
+	RevocationReason_REVOCATION_REASON_CERTIFICATE_HOLD       RevocationReason = 6
+	RevocationReason_REVOCATION_REASON_PRIVILEGE_WITHDRAWN    RevocationReason = 9
+	RevocationReason_REVOCATION_REASON_AA_COMPROMISE          RevocationReason = 10
+)
+
+// Enum value maps for RevocationReason.
+var (
+	RevocationReason_name = map[int32]string{
+		0:  "REVOCATION_REASON_UNSPECIFIED",
+		1:  "REVOCATION_REASON_KEY_COMPROMISE",
+		2:  "REVOCATION_REASON_CA_COMPROMISE",
+		3:  "REVOCATION_REASON_AFFILIATION_CHANGED",
+		4:  "REVOCATION_REASON_SUPERSEDED",
+		5:  "REVOCATION_REASON_CESSATION_OF_OPERATION",
+		6:  "REVOCATION_REASON_CERTIFICATE_HOLD",
+		9:  "REVOCATION_REASON_PRIVILEGE_WITHDRAWN",
+		10: "REVOCATION_REASON_AA_COMPROMISE",
+	}
+	RevocationReason_value = map[string]int32{
+		"REVOCATION_REASON_UNSPECIFIED":            0,
+		"REVOCATION_REASON_KEY_COMPROMISE":         1,
+		"REVOCATION_REASON_CA_COMPROMISE":          2,
+		"REVOCATION_REASON_AFFILIATION_CHANGED":    3,
+		"REVOCATION_REASON_SUPERSEDED":             4,
+		"REVOCATION_REASON_CESSATION_OF_OPERATION": 5,
+		"REVOCATION_REASON_CERTIFICATE_HOLD":       6,
+		"REVOCATION_REASON_PRIVILEGE_WITHDRAWN":    9,
+		"REVOCATION_REASON_AA_COMPROMISE":          10,
+	}
+)
+
+func (x RevocationReason) Enum() *RevocationReason {
+	p := new(RevocationReason)
+	*p = x
+	return p
+}
+
+func (x RevocationReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RevocationReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_mgmt_v1_mgmt_proto_enumTypes[6].Descriptor()
+}
+
+func (RevocationReason) Type() protoreflect.EnumType {
+	return &file_api_mgmt_v1_mgmt_proto_enumTypes[6]
+}
+
+func (x RevocationReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RevocationReason.Descriptor instead.
+func (RevocationReason) EnumDescriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{6}
+}
+
+// Role grants a set of RPCs to a caller.
+type Role int32
+
+const (
+	Role_ROLE_UNSPECIFIED Role = 0
+	// every RPC, including managing role bindings
+	Role_ROLE_ADMIN Role = 1
+	// manage signers and revoke certificates, plus everything an auditor can do
+	Role_ROLE_OPERATOR Role = 2
+	// issue certificates and read trust bundles and CRLs
+	Role_ROLE_ISSUER Role = 3
+	// read-only access to signers, certificates and role bindings
+	Role_ROLE_AUDITOR Role = 4
+)
+
+// Enum value maps for Role.
+var (
+	Role_name = map[int32]string{
+		0: "ROLE_UNSPECIFIED",
+		1: "ROLE_ADMIN",
+		2: "ROLE_OPERATOR",
+		3: "ROLE_ISSUER",
+		4: "ROLE_AUDITOR",
+	}
+	Role_value = map[string]int32{
+		"ROLE_UNSPECIFIED": 0,
+		"ROLE_ADMIN":       1,
+		"ROLE_OPERATOR":    2,
+		"ROLE_ISSUER":      3,
+		"ROLE_AUDITOR":     4,
+	}
+)
+
+func (x Role) Enum() *Role {
+	p := new(Role)
+	*p = x
+	return p
+}
+
+func (x Role) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Role) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_mgmt_v1_mgmt_proto_enumTypes[7].Descriptor()
+}
+
+func (Role) Type() protoreflect.EnumType {
+	return &file_api_mgmt_v1_mgmt_proto_enumTypes[7]
+}
+
+func (x Role) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Role.Descriptor instead.
+func (Role) EnumDescriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{7}
 }
 
 type Signer struct {
@@ -210,6 +531,39 @@ type Signer struct {
 	//	*Signer_Hsm
 	//	*Signer_Remote
 	SignerConfig isSigner_SignerConfig `protobuf_oneof:"signer_config"`
+	// allow this signer to issue intermediate CA certificates, e.g. to downstream Northfoot instances
+	AllowCaIssuance *bool `protobuf:"varint,9,opt,name=allow_ca_issuance,json=allowCaIssuance,proto3,oneof" json:"allow_ca_issuance,omitempty"`
+	// sign OCSP responses with a delegated responder certificate instead of the CA key.
+	// Always enabled for Ed25519 CAs.
+	OcspDelegatedResponder *bool `protobuf:"varint,10,opt,name=ocsp_delegated_responder,json=ocspDelegatedResponder,proto3,oneof" json:"ocsp_delegated_responder,omitempty"`
+	// enables SCEP enrollment for this signer, with clients presenting this challenge password.
+	// Never returned by GetSigner or ListSigners.
+	ScepChallengePassword *string `protobuf:"bytes,11,opt,name=scep_challenge_password,json=scepChallengePassword,proto3,oneof" json:"scep_challenge_password,omitempty"`
+	// restricts what this signer will issue, regardless of enrollment protocol
+	Policy *IssuancePolicy `protobuf:"bytes,12,opt,name=policy,proto3,oneof" json:"policy,omitempty"`
+	// profiles callers may select in addition to the built-in server, client, code-signing, email,
+	// ocsp-signing and intermediate-ca profiles. A profile with the name of a built-in one replaces it.
+	Profiles []*Profile `protobuf:"bytes,13,rep,name=profiles,proto3" json:"profiles,omitempty"`
+	// profile used when a request does not name one. If unset, leaves are issued for both server and
+	// client authentication.
+	DefaultProfile *string `protobuf:"bytes,14,opt,name=default_profile,json=defaultProfile,proto3,oneof" json:"default_profile,omitempty"`
+	// incremented by every update, set by the server
+	Version *int64 `protobuf:"varint,15,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	// replaces the signer's CA with a new one, on a schedule or with RotateSigner
+	Rotation *RotationConfig `protobuf:"bytes,16,opt,name=rotation,proto3,oneof" json:"rotation,omitempty"`
+	// store the CA key in the datastore, encrypted with the server's key encryption key, so the CA
+	// survives restarts. Keys are only held in memory unless this is set. Supported by in memory, file
+	// and remote Northfoot signers.
+	PersistCaKey *bool `protobuf:"varint,17,opt,name=persist_ca_key,json=persistCaKey,proto3,oneof" json:"persist_ca_key,omitempty"`
+	// enables ACME enrollment for this signer. ACME clients are not subject to role bindings, only to
+	// the ACME challenges and the signer's policy.
+	AllowAcme *bool `protobuf:"varint,18,opt,name=allow_acme,json=allowAcme,proto3,oneof" json:"allow_acme,omitempty"`
+	// enables EST enrollment for this signer. EST clients are not subject to role bindings, only to
+	// EST authentication and the signer's policy.
+	AllowEst *bool `protobuf:"varint,19,opt,name=allow_est,json=allowEst,proto3,oneof" json:"allow_est,omitempty"`
+	// accept certificates this signer issues as TLS client certificates for the API. Only SPIFFE IDs in
+	// the trust domain of the signer's CA authenticate the caller, so ca.trust_domain must be set.
+	TrustForClientAuth *bool `protobuf:"varint,20,opt,name=trust_for_client_auth,json=trustForClientAuth,proto3,oneof" json:"trust_for_client_auth,omitempty"`
 }
 
 func (x *Signer) Reset() {
@@ -307,6 +661,90 @@ func (x *Signer) GetRemote() *SignerRemoteConfig {
 	return nil
 }
 
+func (x *Signer) GetAllowCaIssuance() bool {
+	if x != nil && x.AllowCaIssuance != nil {
+		return *x.AllowCaIssuance
+	}
+	return false
+}
+
+func (x *Signer) GetOcspDelegatedResponder() bool {
+	if x != nil && x.OcspDelegatedResponder != nil {
+		return *x.OcspDelegatedResponder
+	}
+	return false
+}
+
+func (x *Signer) GetScepChallengePassword() string {
+	if x != nil && x.ScepChallengePassword != nil {
+		return *x.ScepChallengePassword
+	}
+	return ""
+}
+
+func (x *Signer) GetPolicy() *IssuancePolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+func (x *Signer) GetProfiles() []*Profile {
+	if x != nil {
+		return x.Profiles
+	}
+	return nil
+}
+
+func (x *Signer) GetDefaultProfile() string {
+	if x != nil && x.DefaultProfile != nil {
+		return *x.DefaultProfile
+	}
+	return ""
+}
+
+func (x *Signer) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+func (x *Signer) GetRotation() *RotationConfig {
+	if x != nil {
+		return x.Rotation
+	}
+	return nil
+}
+
+func (x *Signer) GetPersistCaKey() bool {
+	if x != nil && x.PersistCaKey != nil {
+		return *x.PersistCaKey
+	}
+	return false
+}
+
+func (x *Signer) GetAllowAcme() bool {
+	if x != nil && x.AllowAcme != nil {
+		return *x.AllowAcme
+	}
+	return false
+}
+
+func (x *Signer) GetAllowEst() bool {
+	if x != nil && x.AllowEst != nil {
+		return *x.AllowEst
+	}
+	return false
+}
+
+func (x *Signer) GetTrustForClientAuth() bool {
+	if x != nil && x.TrustForClientAuth != nil {
+		return *x.TrustForClientAuth
+	}
+	return false
+}
+
 type isSigner_SignerConfig interface {
 	isSigner_SignerConfig()
 }
@@ -335,18 +773,24 @@ func (*Signer_Hsm) isSigner_SignerConfig() {}
 
 func (*Signer_Remote) isSigner_SignerConfig() {}
 
-type SignerInMemConfig struct {
+// RotationConfig applies to signers that generate their CA: in memory, file and remote Northfoot signers.
+type RotationConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// key type to generate in memory
-	Key     PrivateKeyType `protobuf:"varint,1,opt,name=key,proto3,enum=api.mgmt.v1.PrivateKeyType" json:"key,omitempty"`
-	KeySize *int64         `protobuf:"varint,2,opt,name=key_size,json=keySize,proto3,oneof" json:"key_size,omitempty"`
+	// rotate automatically once the current CA is this old, never if unset
+	Interval *durationpb.Duration `protobuf:"bytes,1,opt,name=interval,proto3,oneof" json:"interval,omitempty"`
+	// how long the previous CA stays in the trust bundle after a rotation (default 24 hours). It should
+	// cover the lifetime of the leaves the previous CA issued.
+	Overlap *durationpb.Duration `protobuf:"bytes,2,opt,name=overlap,proto3,oneof" json:"overlap,omitempty"`
+	// also issue the new CA from the previous one, so clients that only trust the previous CA can
+	// verify new leaves during the overlap. Only in memory signers, whose CA is a root, cross-sign.
+	CrossSign bool `protobuf:"varint,3,opt,name=cross_sign,json=crossSign,proto3" json:"cross_sign,omitempty"`
 }
 
-func (x *SignerInMemConfig) Reset() {
-	*x = SignerInMemConfig{}
+func (x *RotationConfig) Reset() {
+	*x = RotationConfig{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -354,13 +798,13 @@ func (x *SignerInMemConfig) Reset() {
 	}
 }
 
-func (x *SignerInMemConfig) String() string {
+func (x *RotationConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SignerInMemConfig) ProtoMessage() {}
+func (*RotationConfig) ProtoMessage() {}
 
-func (x *SignerInMemConfig) ProtoReflect() protoreflect.Message {
+func (x *RotationConfig) ProtoReflect() protoreflect.Message {
 	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -372,36 +816,47 @@ func (x *SignerInMemConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SignerInMemConfig.ProtoReflect.Descriptor instead.
-func (*SignerInMemConfig) Descriptor() ([]byte, []int) {
+// Deprecated: Use RotationConfig.ProtoReflect.Descriptor instead.
+func (*RotationConfig) Descriptor() ([]byte, []int) {
 	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *SignerInMemConfig) GetKey() PrivateKeyType {
+func (x *RotationConfig) GetInterval() *durationpb.Duration {
 	if x != nil {
-		return x.Key
+		return x.Interval
 	}
-	return PrivateKeyType_PRIVATE_KEY_TYPE_UNSPECIFIED
+	return nil
 }
 
-func (x *SignerInMemConfig) GetKeySize() int64 {
-	if x != nil && x.KeySize != nil {
-		return *x.KeySize
+func (x *RotationConfig) GetOverlap() *durationpb.Duration {
+	if x != nil {
+		return x.Overlap
 	}
-	return 0
+	return nil
 }
 
-type SignerFileConfig struct {
+func (x *RotationConfig) GetCrossSign() bool {
+	if x != nil {
+		return x.CrossSign
+	}
+	return false
+}
+
+// Extension is an X.509 extension copied verbatim into issued certificates.
+type Extension struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	TlsCertFilePath string `protobuf:"bytes,1,opt,name=tls_cert_file_path,json=tlsCertFilePath,proto3" json:"tls_cert_file_path,omitempty"`
-	TlsKeyFilePath  string `protobuf:"bytes,2,opt,name=tls_key_file_path,json=tlsKeyFilePath,proto3" json:"tls_key_file_path,omitempty"`
+	// dotted OID, outside the 2.5.29 arc whose extensions are set from the other fields
+	Oid      string `protobuf:"bytes,1,opt,name=oid,proto3" json:"oid,omitempty"`
+	Critical bool   `protobuf:"varint,2,opt,name=critical,proto3" json:"critical,omitempty"`
+	// DER encoded extension value
+	Value []byte `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
 }
 
-func (x *SignerFileConfig) Reset() {
-	*x = SignerFileConfig{}
+func (x *Extension) Reset() {
+	*x = Extension{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -409,13 +864,13 @@ func (x *SignerFileConfig) Reset() {
 	}
 }
 
-func (x *SignerFileConfig) String() string {
+func (x *Extension) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SignerFileConfig) ProtoMessage() {}
+func (*Extension) ProtoMessage() {}
 
-func (x *SignerFileConfig) ProtoReflect() protoreflect.Message {
+func (x *Extension) ProtoReflect() protoreflect.Message {
 	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -427,38 +882,52 @@ func (x *SignerFileConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SignerFileConfig.ProtoReflect.Descriptor instead.
-func (*SignerFileConfig) Descriptor() ([]byte, []int) {
+// Deprecated: Use Extension.ProtoReflect.Descriptor instead.
+func (*Extension) Descriptor() ([]byte, []int) {
 	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *SignerFileConfig) GetTlsCertFilePath() string {
+func (x *Extension) GetOid() string {
 	if x != nil {
-		return x.TlsCertFilePath
+		return x.Oid
 	}
 	return ""
 }
 
-func (x *SignerFileConfig) GetTlsKeyFilePath() string {
+func (x *Extension) GetCritical() bool {
 	if x != nil {
-		return x.TlsKeyFilePath
+		return x.Critical
 	}
-	return ""
+	return false
 }
 
-type SignerHSMConfig struct {
+func (x *Extension) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+// Profile is a named set of extensions for the certificates a signer issues.
+type Profile struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	HsmLibraryPath string  `protobuf:"bytes,1,opt,name=hsm_library_path,json=hsmLibraryPath,proto3" json:"hsm_library_path,omitempty"`
-	HsmTokenSerial *string `protobuf:"bytes,2,opt,name=hsm_token_serial,json=hsmTokenSerial,proto3,oneof" json:"hsm_token_serial,omitempty"`
-	HsmTokenLabel  *string `protobuf:"bytes,3,opt,name=hsm_token_label,json=hsmTokenLabel,proto3,oneof" json:"hsm_token_label,omitempty"`
-	HsmTokenPin    *string `protobuf:"bytes,4,opt,name=hsm_token_pin,json=hsmTokenPin,proto3,oneof" json:"hsm_token_pin,omitempty"`
+	Name         string        `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	KeyUsages    []KeyUsage    `protobuf:"varint,2,rep,packed,name=key_usages,json=keyUsages,proto3,enum=api.mgmt.v1.KeyUsage" json:"key_usages,omitempty"`
+	ExtKeyUsages []ExtKeyUsage `protobuf:"varint,3,rep,packed,name=ext_key_usages,json=extKeyUsages,proto3,enum=api.mgmt.v1.ExtKeyUsage" json:"ext_key_usages,omitempty"`
+	// extended key usages in dotted OID form, for usages without an ExtKeyUsage value
+	ExtKeyUsageOids []string `protobuf:"bytes,4,rep,name=ext_key_usage_oids,json=extKeyUsageOids,proto3" json:"ext_key_usage_oids,omitempty"`
+	// issue intermediate CAs, which requires allow_ca_issuance on the signer
+	Ca bool `protobuf:"varint,5,opt,name=ca,proto3" json:"ca,omitempty"`
+	// path length constraint of intermediate CAs, unconstrained if unset
+	MaxPathLen *int32       `protobuf:"varint,6,opt,name=max_path_len,json=maxPathLen,proto3,oneof" json:"max_path_len,omitempty"`
+	Extensions []*Extension `protobuf:"bytes,7,rep,name=extensions,proto3" json:"extensions,omitempty"`
 }
 
-func (x *SignerHSMConfig) Reset() {
-	*x = SignerHSMConfig{}
+func (x *Profile) Reset() {
+	*x = Profile{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -466,13 +935,13 @@ func (x *SignerHSMConfig) Reset() {
 	}
 }
 
-func (x *SignerHSMConfig) String() string {
+func (x *Profile) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SignerHSMConfig) ProtoMessage() {}
+func (*Profile) ProtoMessage() {}
 
-func (x *SignerHSMConfig) ProtoReflect() protoreflect.Message {
+func (x *Profile) ProtoReflect() protoreflect.Message {
 	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -484,49 +953,94 @@ func (x *SignerHSMConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SignerHSMConfig.ProtoReflect.Descriptor instead.
-func (*SignerHSMConfig) Descriptor() ([]byte, []int) {
+// Deprecated: Use Profile.ProtoReflect.Descriptor instead.
+func (*Profile) Descriptor() ([]byte, []int) {
 	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *SignerHSMConfig) GetHsmLibraryPath() string {
+func (x *Profile) GetName() string {
 	if x != nil {
-		return x.HsmLibraryPath
+		return x.Name
 	}
 	return ""
 }
 
-func (x *SignerHSMConfig) GetHsmTokenSerial() string {
-	if x != nil && x.HsmTokenSerial != nil {
-		return *x.HsmTokenSerial
+func (x *Profile) GetKeyUsages() []KeyUsage {
+	if x != nil {
+		return x.KeyUsages
 	}
-	return ""
+	return nil
 }
 
-func (x *SignerHSMConfig) GetHsmTokenLabel() string {
-	if x != nil && x.HsmTokenLabel != nil {
-		return *x.HsmTokenLabel
+func (x *Profile) GetExtKeyUsages() []ExtKeyUsage {
+	if x != nil {
+		return x.ExtKeyUsages
 	}
-	return ""
+	return nil
 }
 
-func (x *SignerHSMConfig) GetHsmTokenPin() string {
-	if x != nil && x.HsmTokenPin != nil {
-		return *x.HsmTokenPin
+func (x *Profile) GetExtKeyUsageOids() []string {
+	if x != nil {
+		return x.ExtKeyUsageOids
 	}
-	return ""
+	return nil
 }
 
-type RemoteNorthfootConfig struct {
+func (x *Profile) GetCa() bool {
+	if x != nil {
+		return x.Ca
+	}
+	return false
+}
+
+func (x *Profile) GetMaxPathLen() int32 {
+	if x != nil && x.MaxPathLen != nil {
+		return *x.MaxPathLen
+	}
+	return 0
+}
+
+func (x *Profile) GetExtensions() []*Extension {
+	if x != nil {
+		return x.Extensions
+	}
+	return nil
+}
+
+// IssuancePolicy restricts the certificates a signer issues. Denied patterns take precedence over
+// allowed ones. Once any allowed pattern is set, SANs of a type with no allowed patterns are refused.
+type IssuancePolicy struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Endpoint string `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	// DNS names, exact or a "*." prefix matching any name beneath the domain, e.g. *.edge.example.com
+	AllowedDnsNames []string `protobuf:"bytes,1,rep,name=allowed_dns_names,json=allowedDnsNames,proto3" json:"allowed_dns_names,omitempty"`
+	DeniedDnsNames  []string `protobuf:"bytes,2,rep,name=denied_dns_names,json=deniedDnsNames,proto3" json:"denied_dns_names,omitempty"`
+	// IP address ranges in CIDR notation, e.g. 10.0.0.0/8
+	AllowedIpRanges []string `protobuf:"bytes,3,rep,name=allowed_ip_ranges,json=allowedIpRanges,proto3" json:"allowed_ip_ranges,omitempty"`
+	DeniedIpRanges  []string `protobuf:"bytes,4,rep,name=denied_ip_ranges,json=deniedIpRanges,proto3" json:"denied_ip_ranges,omitempty"`
+	// URI SAN globs where * matches within a path segment, e.g. spiffe://edge/ns/*/sa/*
+	AllowedUris []string `protobuf:"bytes,5,rep,name=allowed_uris,json=allowedUris,proto3" json:"allowed_uris,omitempty"`
+	DeniedUris  []string `protobuf:"bytes,6,rep,name=denied_uris,json=deniedUris,proto3" json:"denied_uris,omitempty"`
+	// email address domains, matched like DNS names
+	AllowedEmailDomains []string `protobuf:"bytes,7,rep,name=allowed_email_domains,json=allowedEmailDomains,proto3" json:"allowed_email_domains,omitempty"`
+	DeniedEmailDomains  []string `protobuf:"bytes,8,rep,name=denied_email_domains,json=deniedEmailDomains,proto3" json:"denied_email_domains,omitempty"`
+	// subject fields not covered by a rule are copied from the CSR unchecked
+	SubjectRules []*SubjectRule `protobuf:"bytes,9,rep,name=subject_rules,json=subjectRules,proto3" json:"subject_rules,omitempty"`
+	// longest lifetime a caller may request
+	MaxLifetime *durationpb.Duration `protobuf:"bytes,10,opt,name=max_lifetime,json=maxLifetime,proto3,oneof" json:"max_lifetime,omitempty"`
+	// lifetime used when the caller does not request one
+	DefaultLifetime *durationpb.Duration `protobuf:"bytes,11,opt,name=default_lifetime,json=defaultLifetime,proto3,oneof" json:"default_lifetime,omitempty"`
+	// ties the names a caller may request to its authenticated identity. When set, callers must match
+	// one of the rules, and the first rule they match applies on top of the patterns above.
+	IdentityRules []*IdentityRule `protobuf:"bytes,12,rep,name=identity_rules,json=identityRules,proto3" json:"identity_rules,omitempty"`
+	// custom rules that must all evaluate to true, checked after the rules above
+	CelRules []*CELRule `protobuf:"bytes,13,rep,name=cel_rules,json=celRules,proto3" json:"cel_rules,omitempty"`
 }
 
-func (x *RemoteNorthfootConfig) Reset() {
-	*x = RemoteNorthfootConfig{}
+func (x *IssuancePolicy) Reset() {
+	*x = IssuancePolicy{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -534,13 +1048,13 @@ func (x *RemoteNorthfootConfig) Reset() {
 	}
 }
 
-func (x *RemoteNorthfootConfig) String() string {
+func (x *IssuancePolicy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoteNorthfootConfig) ProtoMessage() {}
+func (*IssuancePolicy) ProtoMessage() {}
 
-func (x *RemoteNorthfootConfig) ProtoReflect() protoreflect.Message {
+func (x *IssuancePolicy) ProtoReflect() protoreflect.Message {
 	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -552,29 +1066,119 @@ func (x *RemoteNorthfootConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoteNorthfootConfig.ProtoReflect.Descriptor instead.
-func (*RemoteNorthfootConfig) Descriptor() ([]byte, []int) {
+// Deprecated: Use IssuancePolicy.ProtoReflect.Descriptor instead.
+func (*IssuancePolicy) Descriptor() ([]byte, []int) {
 	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *RemoteNorthfootConfig) GetEndpoint() string {
+func (x *IssuancePolicy) GetAllowedDnsNames() []string {
 	if x != nil {
-		return x.Endpoint
+		return x.AllowedDnsNames
 	}
-	return ""
+	return nil
 }
 
-type RemoteVerbatimHttpsConfig struct {
+func (x *IssuancePolicy) GetDeniedDnsNames() []string {
+	if x != nil {
+		return x.DeniedDnsNames
+	}
+	return nil
+}
+
+func (x *IssuancePolicy) GetAllowedIpRanges() []string {
+	if x != nil {
+		return x.AllowedIpRanges
+	}
+	return nil
+}
+
+func (x *IssuancePolicy) GetDeniedIpRanges() []string {
+	if x != nil {
+		return x.DeniedIpRanges
+	}
+	return nil
+}
+
+func (x *IssuancePolicy) GetAllowedUris() []string {
+	if x != nil {
+		return x.AllowedUris
+	}
+	return nil
+}
+
+func (x *IssuancePolicy) GetDeniedUris() []string {
+	if x != nil {
+		return x.DeniedUris
+	}
+	return nil
+}
+
+func (x *IssuancePolicy) GetAllowedEmailDomains() []string {
+	if x != nil {
+		return x.AllowedEmailDomains
+	}
+	return nil
+}
+
+func (x *IssuancePolicy) GetDeniedEmailDomains() []string {
+	if x != nil {
+		return x.DeniedEmailDomains
+	}
+	return nil
+}
+
+func (x *IssuancePolicy) GetSubjectRules() []*SubjectRule {
+	if x != nil {
+		return x.SubjectRules
+	}
+	return nil
+}
+
+func (x *IssuancePolicy) GetMaxLifetime() *durationpb.Duration {
+	if x != nil {
+		return x.MaxLifetime
+	}
+	return nil
+}
+
+func (x *IssuancePolicy) GetDefaultLifetime() *durationpb.Duration {
+	if x != nil {
+		return x.DefaultLifetime
+	}
+	return nil
+}
+
+func (x *IssuancePolicy) GetIdentityRules() []*IdentityRule {
+	if x != nil {
+		return x.IdentityRules
+	}
+	return nil
+}
+
+func (x *IssuancePolicy) GetCelRules() []*CELRule {
+	if x != nil {
+		return x.CelRules
+	}
+	return nil
+}
+
+// CELRule is a CEL expression evaluated against each request. Expressions may use subject (a map of
+// field name, such as common_name, to values), dns_names, ip_addresses, email_addresses, uris,
+// public_key_algorithm, public_key_bits, requester (the authenticated identity), metadata (a map with
+// the protocol, signer_id and profile of the request), duration and ca, e.g.
+// dns_names.all(n, n.endsWith(".svc")) && duration <= duration("24h")
+type CELRule struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	CertUrl string `protobuf:"bytes,1,opt,name=cert_url,json=certUrl,proto3" json:"cert_url,omitempty"`
-	KeyUrl  string `protobuf:"bytes,2,opt,name=key_url,json=keyUrl,proto3" json:"key_url,omitempty"`
+	// identifies the rule in errors and logs
+	Name       string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Expression string `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
 }
 
-func (x *RemoteVerbatimHttpsConfig) Reset() {
-	*x = RemoteVerbatimHttpsConfig{}
+func (x *CELRule) Reset() {
+	*x = CELRule{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -582,13 +1186,13 @@ func (x *RemoteVerbatimHttpsConfig) Reset() {
 	}
 }
 
-func (x *RemoteVerbatimHttpsConfig) String() string {
+func (x *CELRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoteVerbatimHttpsConfig) ProtoMessage() {}
+func (*CELRule) ProtoMessage() {}
 
-func (x *RemoteVerbatimHttpsConfig) ProtoReflect() protoreflect.Message {
+func (x *CELRule) ProtoReflect() protoreflect.Message {
 	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -600,39 +1204,46 @@ func (x *RemoteVerbatimHttpsConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoteVerbatimHttpsConfig.ProtoReflect.Descriptor instead.
-func (*RemoteVerbatimHttpsConfig) Descriptor() ([]byte, []int) {
+// Deprecated: Use CELRule.ProtoReflect.Descriptor instead.
+func (*CELRule) Descriptor() ([]byte, []int) {
 	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *RemoteVerbatimHttpsConfig) GetCertUrl() string {
+func (x *CELRule) GetName() string {
 	if x != nil {
-		return x.CertUrl
+		return x.Name
 	}
 	return ""
 }
 
-func (x *RemoteVerbatimHttpsConfig) GetKeyUrl() string {
+func (x *CELRule) GetExpression() string {
 	if x != nil {
-		return x.KeyUrl
+		return x.Expression
 	}
 	return ""
 }
 
-type SignerRemoteConfig struct {
+// IdentityRule lets callers matching an identity template request names derived from it. Variables such
+// as {ns} match within a single path segment of the identity. SAN types without templates are refused,
+// apart from IP addresses, and a common name must match one of the common name or DNS name templates.
+type IdentityRule struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RemoteType RemoteType `protobuf:"varint,1,opt,name=remote_type,json=remoteType,proto3,enum=api.mgmt.v1.RemoteType" json:"remote_type,omitempty"`
-	// Types that are assignable to RemoteConfig:
-	//	*SignerRemoteConfig_Northfoot
-	//	*SignerRemoteConfig_VerbatimHttps
-	RemoteConfig isSignerRemoteConfig_RemoteConfig `protobuf_oneof:"remote_config"`
+	// e.g. spiffe://edge/ns/{ns}/sa/{sa}
+	Identity string `protobuf:"bytes,1,opt,name=identity,proto3" json:"identity,omitempty"`
+	// e.g. {sa}.{ns}.svc. Variables containing dots are never substituted into DNS names, common names
+	// or email addresses.
+	DnsNames []string `protobuf:"bytes,2,rep,name=dns_names,json=dnsNames,proto3" json:"dns_names,omitempty"`
+	// e.g. spiffe://edge/ns/{ns}/sa/{sa}
+	Uris           []string `protobuf:"bytes,3,rep,name=uris,proto3" json:"uris,omitempty"`
+	EmailAddresses []string `protobuf:"bytes,4,rep,name=email_addresses,json=emailAddresses,proto3" json:"email_addresses,omitempty"`
+	CommonNames    []string `protobuf:"bytes,5,rep,name=common_names,json=commonNames,proto3" json:"common_names,omitempty"`
 }
 
-func (x *SignerRemoteConfig) Reset() {
-	*x = SignerRemoteConfig{}
+func (x *IdentityRule) Reset() {
+	*x = IdentityRule{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -640,13 +1251,13 @@ func (x *SignerRemoteConfig) Reset() {
 	}
 }
 
-func (x *SignerRemoteConfig) String() string {
+func (x *IdentityRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SignerRemoteConfig) ProtoMessage() {}
+func (*IdentityRule) ProtoMessage() {}
 
-func (x *SignerRemoteConfig) ProtoReflect() protoreflect.Message {
+func (x *IdentityRule) ProtoReflect() protoreflect.Message {
 	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -658,65 +1269,60 @@ func (x *SignerRemoteConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SignerRemoteConfig.ProtoReflect.Descriptor instead.
-func (*SignerRemoteConfig) Descriptor() ([]byte, []int) {
+// Deprecated: Use IdentityRule.ProtoReflect.Descriptor instead.
+func (*IdentityRule) Descriptor() ([]byte, []int) {
 	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *SignerRemoteConfig) GetRemoteType() RemoteType {
+func (x *IdentityRule) GetIdentity() string {
 	if x != nil {
-		return x.RemoteType
+		return x.Identity
 	}
-	return RemoteType_REMOTE_TYPE_UNSPECIFIED
+	return ""
 }
 
-func (m *SignerRemoteConfig) GetRemoteConfig() isSignerRemoteConfig_RemoteConfig {
-	if m != nil {
-		return m.RemoteConfig
+func (x *IdentityRule) GetDnsNames() []string {
+	if x != nil {
+		return x.DnsNames
 	}
 	return nil
 }
 
-func (x *SignerRemoteConfig) GetNorthfoot() *RemoteNorthfootConfig {
-	if x, ok := x.GetRemoteConfig().(*SignerRemoteConfig_Northfoot); ok {
-		return x.Northfoot
+func (x *IdentityRule) GetUris() []string {
+	if x != nil {
+		return x.Uris
 	}
 	return nil
 }
 
-func (x *SignerRemoteConfig) GetVerbatimHttps() *RemoteVerbatimHttpsConfig {
-	if x, ok := x.GetRemoteConfig().(*SignerRemoteConfig_VerbatimHttps); ok {
-		return x.VerbatimHttps
+func (x *IdentityRule) GetEmailAddresses() []string {
+	if x != nil {
+		return x.EmailAddresses
 	}
 	return nil
 }
 
-type isSignerRemoteConfig_RemoteConfig interface {
-	isSignerRemoteConfig_RemoteConfig()
-}
-
-type SignerRemoteConfig_Northfoot struct {
-	Northfoot *RemoteNorthfootConfig `protobuf:"bytes,2,opt,name=northfoot,proto3,oneof"`
-}
-
-type SignerRemoteConfig_VerbatimHttps struct {
-	VerbatimHttps *RemoteVerbatimHttpsConfig `protobuf:"bytes,3,opt,name=verbatim_https,json=verbatimHttps,proto3,oneof"`
+func (x *IdentityRule) GetCommonNames() []string {
+	if x != nil {
+		return x.CommonNames
+	}
+	return nil
 }
 
-func (*SignerRemoteConfig_Northfoot) isSignerRemoteConfig_RemoteConfig() {}
-
-func (*SignerRemoteConfig_VerbatimHttps) isSignerRemoteConfig_RemoteConfig() {}
-
-type GetSignerRequest struct {
+type SubjectRule struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Field SubjectField `protobuf:"varint,1,opt,name=field,proto3,enum=api.mgmt.v1.SubjectField" json:"field,omitempty"`
+	// globs every value of the field must match. If empty, the field must be absent.
+	AllowedValues []string `protobuf:"bytes,2,rep,name=allowed_values,json=allowedValues,proto3" json:"allowed_values,omitempty"`
+	// the field must be present in the CSR
+	Required bool `protobuf:"varint,3,opt,name=required,proto3" json:"required,omitempty"`
 }
 
-func (x *GetSignerRequest) Reset() {
-	*x = GetSignerRequest{}
+func (x *SubjectRule) Reset() {
+	*x = SubjectRule{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -724,13 +1330,13 @@ func (x *GetSignerRequest) Reset() {
 	}
 }
 
-func (x *GetSignerRequest) String() string {
+func (x *SubjectRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSignerRequest) ProtoMessage() {}
+func (*SubjectRule) ProtoMessage() {}
 
-func (x *GetSignerRequest) ProtoReflect() protoreflect.Message {
+func (x *SubjectRule) ProtoReflect() protoreflect.Message {
 	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -742,28 +1348,48 @@ func (x *GetSignerRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSignerRequest.ProtoReflect.Descriptor instead.
-func (*GetSignerRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SubjectRule.ProtoReflect.Descriptor instead.
+func (*SubjectRule) Descriptor() ([]byte, []int) {
 	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *GetSignerRequest) GetId() int64 {
+func (x *SubjectRule) GetField() SubjectField {
 	if x != nil {
-		return x.Id
+		return x.Field
 	}
-	return 0
+	return SubjectField_SUBJECT_FIELD_UNSPECIFIED
 }
 
-type GetSignerResponse struct {
+func (x *SubjectRule) GetAllowedValues() []string {
+	if x != nil {
+		return x.AllowedValues
+	}
+	return nil
+}
+
+func (x *SubjectRule) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+type SignerInMemConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Signer *Signer `protobuf:"bytes,1,opt,name=signer,proto3" json:"signer,omitempty"`
+	// key type to generate in memory
+	Key PrivateKeyType `protobuf:"varint,1,opt,name=key,proto3,enum=api.mgmt.v1.PrivateKeyType" json:"key,omitempty"`
+	// RSA modulus size in bits (default 2048) or EC curve size: 256, 384 or 521 (default 256).
+	// Ignored for Ed25519.
+	KeySize *int64 `protobuf:"varint,2,opt,name=key_size,json=keySize,proto3,oneof" json:"key_size,omitempty"`
+	// the self-signed CA certificate to generate
+	Ca *CAConfig `protobuf:"bytes,3,opt,name=ca,proto3,oneof" json:"ca,omitempty"`
 }
 
-func (x *GetSignerResponse) Reset() {
-	*x = GetSignerResponse{}
+func (x *SignerInMemConfig) Reset() {
+	*x = SignerInMemConfig{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -771,13 +1397,13 @@ func (x *GetSignerResponse) Reset() {
 	}
 }
 
-func (x *GetSignerResponse) String() string {
+func (x *SignerInMemConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSignerResponse) ProtoMessage() {}
+func (*SignerInMemConfig) ProtoMessage() {}
 
-func (x *GetSignerResponse) ProtoReflect() protoreflect.Message {
+func (x *SignerInMemConfig) ProtoReflect() protoreflect.Message {
 	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -789,28 +1415,47 @@ func (x *GetSignerResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSignerResponse.ProtoReflect.Descriptor instead.
-func (*GetSignerResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SignerInMemConfig.ProtoReflect.Descriptor instead.
+func (*SignerInMemConfig) Descriptor() ([]byte, []int) {
 	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *GetSignerResponse) GetSigner() *Signer {
+func (x *SignerInMemConfig) GetKey() PrivateKeyType {
 	if x != nil {
-		return x.Signer
+		return x.Key
+	}
+	return PrivateKeyType_PRIVATE_KEY_TYPE_UNSPECIFIED
+}
+
+func (x *SignerInMemConfig) GetKeySize() int64 {
+	if x != nil && x.KeySize != nil {
+		return *x.KeySize
+	}
+	return 0
+}
+
+func (x *SignerInMemConfig) GetCa() *CAConfig {
+	if x != nil {
+		return x.Ca
 	}
 	return nil
 }
 
-type SignerList struct {
+type CASubject struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Signers []*Signer `protobuf:"bytes,1,rep,name=signers,proto3" json:"signers,omitempty"`
+	CommonName         string   `protobuf:"bytes,1,opt,name=common_name,json=commonName,proto3" json:"common_name,omitempty"`
+	Organization       []string `protobuf:"bytes,2,rep,name=organization,proto3" json:"organization,omitempty"`
+	OrganizationalUnit []string `protobuf:"bytes,3,rep,name=organizational_unit,json=organizationalUnit,proto3" json:"organizational_unit,omitempty"`
+	Country            []string `protobuf:"bytes,4,rep,name=country,proto3" json:"country,omitempty"`
+	Province           []string `protobuf:"bytes,5,rep,name=province,proto3" json:"province,omitempty"`
+	Locality           []string `protobuf:"bytes,6,rep,name=locality,proto3" json:"locality,omitempty"`
 }
 
-func (x *SignerList) Reset() {
-	*x = SignerList{}
+func (x *CASubject) Reset() {
+	*x = CASubject{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -818,13 +1463,13 @@ func (x *SignerList) Reset() {
 	}
 }
 
-func (x *SignerList) String() string {
+func (x *CASubject) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SignerList) ProtoMessage() {}
+func (*CASubject) ProtoMessage() {}
 
-func (x *SignerList) ProtoReflect() protoreflect.Message {
+func (x *CASubject) ProtoReflect() protoreflect.Message {
 	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -836,28 +1481,77 @@ func (x *SignerList) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SignerList.ProtoReflect.Descriptor instead.
-func (*SignerList) Descriptor() ([]byte, []int) {
+// Deprecated: Use CASubject.ProtoReflect.Descriptor instead.
+func (*CASubject) Descriptor() ([]byte, []int) {
 	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *SignerList) GetSigners() []*Signer {
+func (x *CASubject) GetCommonName() string {
 	if x != nil {
-		return x.Signers
+		return x.CommonName
+	}
+	return ""
+}
+
+func (x *CASubject) GetOrganization() []string {
+	if x != nil {
+		return x.Organization
 	}
 	return nil
 }
 
-type ListSignersResponse struct {
+func (x *CASubject) GetOrganizationalUnit() []string {
+	if x != nil {
+		return x.OrganizationalUnit
+	}
+	return nil
+}
+
+func (x *CASubject) GetCountry() []string {
+	if x != nil {
+		return x.Country
+	}
+	return nil
+}
+
+func (x *CASubject) GetProvince() []string {
+	if x != nil {
+		return x.Province
+	}
+	return nil
+}
+
+func (x *CASubject) GetLocality() []string {
+	if x != nil {
+		return x.Locality
+	}
+	return nil
+}
+
+// CAConfig describes the CA certificate a signer generates.
+type CAConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Signers *SignerList `protobuf:"bytes,1,opt,name=signers,proto3" json:"signers,omitempty"`
+	// default O=Northfoot with a common name describing the CA
+	Subject *CASubject `protobuf:"bytes,1,opt,name=subject,proto3,oneof" json:"subject,omitempty"`
+	// default 10 years for self-signed CAs and 1 year for intermediates, never beyond the parent's validity
+	Validity *durationpb.Duration `protobuf:"bytes,2,opt,name=validity,proto3,oneof" json:"validity,omitempty"`
+	// default unconstrained for self-signed CAs and 0 for intermediates
+	MaxPathLen *int32 `protobuf:"varint,3,opt,name=max_path_len,json=maxPathLen,proto3,oneof" json:"max_path_len,omitempty"`
+	// adds the SPIFFE ID of the trust domain, e.g. spiffe://edge, as a URI SAN
+	TrustDomain *string `protobuf:"bytes,4,opt,name=trust_domain,json=trustDomain,proto3,oneof" json:"trust_domain,omitempty"`
+	// CA certificates have no DNS or IP SANs or extended key usages unless they are set here
+	DnsNames     []string      `protobuf:"bytes,5,rep,name=dns_names,json=dnsNames,proto3" json:"dns_names,omitempty"`
+	IpAddresses  []string      `protobuf:"bytes,6,rep,name=ip_addresses,json=ipAddresses,proto3" json:"ip_addresses,omitempty"`
+	ExtKeyUsages []ExtKeyUsage `protobuf:"varint,7,rep,packed,name=ext_key_usages,json=extKeyUsages,proto3,enum=api.mgmt.v1.ExtKeyUsage" json:"ext_key_usages,omitempty"`
+	// restricts the names the CA, and any CA below it, can issue for
+	NameConstraints *NameConstraints `protobuf:"bytes,8,opt,name=name_constraints,json=nameConstraints,proto3,oneof" json:"name_constraints,omitempty"`
 }
 
-func (x *ListSignersResponse) Reset() {
-	*x = ListSignersResponse{}
+func (x *CAConfig) Reset() {
+	*x = CAConfig{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -865,13 +1559,13 @@ func (x *ListSignersResponse) Reset() {
 	}
 }
 
-func (x *ListSignersResponse) String() string {
+func (x *CAConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSignersResponse) ProtoMessage() {}
+func (*CAConfig) ProtoMessage() {}
 
-func (x *ListSignersResponse) ProtoReflect() protoreflect.Message {
+func (x *CAConfig) ProtoReflect() protoreflect.Message {
 	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -883,28 +1577,88 @@ func (x *ListSignersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSignersResponse.ProtoReflect.Descriptor instead.
-func (*ListSignersResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CAConfig.ProtoReflect.Descriptor instead.
+func (*CAConfig) Descriptor() ([]byte, []int) {
 	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *ListSignersResponse) GetSigners() *SignerList {
+func (x *CAConfig) GetSubject() *CASubject {
 	if x != nil {
-		return x.Signers
+		return x.Subject
 	}
 	return nil
 }
 
-type CreateSignerRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Signer *Signer `protobuf:"bytes,1,opt,name=signer,proto3" json:"signer,omitempty"`
+func (x *CAConfig) GetValidity() *durationpb.Duration {
+	if x != nil {
+		return x.Validity
+	}
+	return nil
 }
 
-func (x *CreateSignerRequest) Reset() {
-	*x = CreateSignerRequest{}
+func (x *CAConfig) GetMaxPathLen() int32 {
+	if x != nil && x.MaxPathLen != nil {
+		return *x.MaxPathLen
+	}
+	return 0
+}
+
+func (x *CAConfig) GetTrustDomain() string {
+	if x != nil && x.TrustDomain != nil {
+		return *x.TrustDomain
+	}
+	return ""
+}
+
+func (x *CAConfig) GetDnsNames() []string {
+	if x != nil {
+		return x.DnsNames
+	}
+	return nil
+}
+
+func (x *CAConfig) GetIpAddresses() []string {
+	if x != nil {
+		return x.IpAddresses
+	}
+	return nil
+}
+
+func (x *CAConfig) GetExtKeyUsages() []ExtKeyUsage {
+	if x != nil {
+		return x.ExtKeyUsages
+	}
+	return nil
+}
+
+func (x *CAConfig) GetNameConstraints() *NameConstraints {
+	if x != nil {
+		return x.NameConstraints
+	}
+	return nil
+}
+
+// NameConstraints are encoded as a critical extension of the CA certificate and
+// also enforced by the signer before issuing. Once a permitted list of a name type is
+// set, every name of that type must match it.
+type NameConstraints struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// e.g. example.com for the domain and its subdomains, or .example.com for subdomains only
+	PermittedDnsDomains []string `protobuf:"bytes,1,rep,name=permitted_dns_domains,json=permittedDnsDomains,proto3" json:"permitted_dns_domains,omitempty"`
+	ExcludedDnsDomains  []string `protobuf:"bytes,2,rep,name=excluded_dns_domains,json=excludedDnsDomains,proto3" json:"excluded_dns_domains,omitempty"`
+	// CIDR notation, e.g. 10.1.0.0/16
+	PermittedIpRanges []string `protobuf:"bytes,3,rep,name=permitted_ip_ranges,json=permittedIpRanges,proto3" json:"permitted_ip_ranges,omitempty"`
+	ExcludedIpRanges  []string `protobuf:"bytes,4,rep,name=excluded_ip_ranges,json=excludedIpRanges,proto3" json:"excluded_ip_ranges,omitempty"`
+	// matched against the host of URI SANs like DNS domains, e.g. the trust domain of a SPIFFE ID
+	PermittedUriDomains []string `protobuf:"bytes,5,rep,name=permitted_uri_domains,json=permittedUriDomains,proto3" json:"permitted_uri_domains,omitempty"`
+	ExcludedUriDomains  []string `protobuf:"bytes,6,rep,name=excluded_uri_domains,json=excludedUriDomains,proto3" json:"excluded_uri_domains,omitempty"`
+}
+
+func (x *NameConstraints) Reset() {
+	*x = NameConstraints{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -912,13 +1666,13 @@ func (x *CreateSignerRequest) Reset() {
 	}
 }
 
-func (x *CreateSignerRequest) String() string {
+func (x *NameConstraints) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateSignerRequest) ProtoMessage() {}
+func (*NameConstraints) ProtoMessage() {}
 
-func (x *CreateSignerRequest) ProtoReflect() protoreflect.Message {
+func (x *NameConstraints) ProtoReflect() protoreflect.Message {
 	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -930,28 +1684,72 @@ func (x *CreateSignerRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateSignerRequest.ProtoReflect.Descriptor instead.
-func (*CreateSignerRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use NameConstraints.ProtoReflect.Descriptor instead.
+func (*NameConstraints) Descriptor() ([]byte, []int) {
 	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *CreateSignerRequest) GetSigner() *Signer {
+func (x *NameConstraints) GetPermittedDnsDomains() []string {
 	if x != nil {
-		return x.Signer
+		return x.PermittedDnsDomains
 	}
 	return nil
 }
 
-type DeleteSignerRequest struct {
+func (x *NameConstraints) GetExcludedDnsDomains() []string {
+	if x != nil {
+		return x.ExcludedDnsDomains
+	}
+	return nil
+}
+
+func (x *NameConstraints) GetPermittedIpRanges() []string {
+	if x != nil {
+		return x.PermittedIpRanges
+	}
+	return nil
+}
+
+func (x *NameConstraints) GetExcludedIpRanges() []string {
+	if x != nil {
+		return x.ExcludedIpRanges
+	}
+	return nil
+}
+
+func (x *NameConstraints) GetPermittedUriDomains() []string {
+	if x != nil {
+		return x.PermittedUriDomains
+	}
+	return nil
+}
+
+func (x *NameConstraints) GetExcludedUriDomains() []string {
+	if x != nil {
+		return x.ExcludedUriDomains
+	}
+	return nil
+}
+
+type SignerFileConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// PEM encoded parent CA certificate, optionally followed by its chain
+	TlsCertFilePath string `protobuf:"bytes,1,opt,name=tls_cert_file_path,json=tlsCertFilePath,proto3" json:"tls_cert_file_path,omitempty"`
+	// PEM encoded parent CA private key (PKCS#1, PKCS#8 or SEC1)
+	TlsKeyFilePath string `protobuf:"bytes,2,opt,name=tls_key_file_path,json=tlsKeyFilePath,proto3" json:"tls_key_file_path,omitempty"`
+	// key type of the intermediate CA to generate in memory
+	Key PrivateKeyType `protobuf:"varint,3,opt,name=key,proto3,enum=api.mgmt.v1.PrivateKeyType" json:"key,omitempty"`
+	// see SignerInMemConfig.key_size
+	KeySize *int64 `protobuf:"varint,4,opt,name=key_size,json=keySize,proto3,oneof" json:"key_size,omitempty"`
+	// the intermediate CA certificate to generate
+	Ca *CAConfig `protobuf:"bytes,5,opt,name=ca,proto3,oneof" json:"ca,omitempty"`
 }
 
-func (x *DeleteSignerRequest) Reset() {
-	*x = DeleteSignerRequest{}
+func (x *SignerFileConfig) Reset() {
+	*x = SignerFileConfig{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -959,263 +1757,2618 @@ func (x *DeleteSignerRequest) Reset() {
 	}
 }
 
-func (x *DeleteSignerRequest) String() string {
+func (x *SignerFileConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteSignerRequest) ProtoMessage() {}
+func (*SignerFileConfig) ProtoMessage() {}
 
-func (x *DeleteSignerRequest) ProtoReflect() protoreflect.Message {
+func (x *SignerFileConfig) ProtoReflect() protoreflect.Message {
 	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use DeleteSignerRequest.ProtoReflect.Descriptor instead.
-func (*DeleteSignerRequest) Descriptor() ([]byte, []int) {
-	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{12}
-}
-
-func (x *DeleteSignerRequest) GetId() int64 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-var File_api_mgmt_v1_mgmt_proto protoreflect.FileDescriptor
-
-var file_api_mgmt_v1_mgmt_proto_rawDesc = []byte{
-	0x0a, 0x16, 0x61, 0x70, 0x69, 0x2f, 0x6d, 0x67, 0x6d, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x67,
-	0x6d, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67,
-	0x6d, 0x74, 0x2e, 0x76, 0x31, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x22, 0x96, 0x03, 0x0a, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x13, 0x0a,
-	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x02, 0x69, 0x64, 0x88,
-	0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x48, 0x02, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x64,
-	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x48, 0x03, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x88,
-	0x01, 0x01, 0x12, 0x2b, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53,
-	0x69, 0x67, 0x6e, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
-	0x37, 0x0a, 0x06, 0x69, 0x6e, 0x5f, 0x6d, 0x65, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69,
-	0x67, 0x6e, 0x65, 0x72, 0x49, 0x6e, 0x4d, 0x65, 0x6d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48,
-	0x00, 0x52, 0x05, 0x69, 0x6e, 0x4d, 0x65, 0x6d, 0x12, 0x33, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d,
-	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x46, 0x69, 0x6c, 0x65, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x00, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x30, 0x0a,
-	0x03, 0x68, 0x73, 0x6d, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x70, 0x69,
-	0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x48,
-	0x53, 0x4d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x00, 0x52, 0x03, 0x68, 0x73, 0x6d, 0x12,
-	0x39, 0x0a, 0x06, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69,
-	0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x48, 0x00, 0x52, 0x06, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x73, 0x69,
-	0x67, 0x6e, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x42, 0x05, 0x0a, 0x03, 0x5f,
-	0x69, 0x64, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f,
-	0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x6f, 0x0a, 0x11, 0x53,
-	0x69, 0x67, 0x6e, 0x65, 0x72, 0x49, 0x6e, 0x4d, 0x65, 0x6d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x12, 0x2d, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e,
-	0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x69, 0x76,
-	0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
-	0x1e, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x03, 0x48, 0x00, 0x52, 0x07, 0x6b, 0x65, 0x79, 0x53, 0x69, 0x7a, 0x65, 0x88, 0x01, 0x01, 0x42,
-	0x0b, 0x0a, 0x09, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x6a, 0x0a, 0x10,
-	0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x12, 0x2b, 0x0a, 0x12, 0x74, 0x6c, 0x73, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x6c,
-	0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x74, 0x6c,
-	0x73, 0x43, 0x65, 0x72, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x29, 0x0a,
-	0x11, 0x74, 0x6c, 0x73, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61,
-	0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x74, 0x6c, 0x73, 0x4b, 0x65, 0x79,
-	0x46, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x22, 0xfb, 0x01, 0x0a, 0x0f, 0x53, 0x69, 0x67,
-	0x6e, 0x65, 0x72, 0x48, 0x53, 0x4d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x28, 0x0a, 0x10,
-	0x68, 0x73, 0x6d, 0x5f, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x74, 0x68,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x68, 0x73, 0x6d, 0x4c, 0x69, 0x62, 0x72, 0x61,
-	0x72, 0x79, 0x50, 0x61, 0x74, 0x68, 0x12, 0x2d, 0x0a, 0x10, 0x68, 0x73, 0x6d, 0x5f, 0x74, 0x6f,
-	0x6b, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x48, 0x00, 0x52, 0x0e, 0x68, 0x73, 0x6d, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x53, 0x65, 0x72, 0x69,
-	0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0f, 0x68, 0x73, 0x6d, 0x5f, 0x74, 0x6f, 0x6b,
-	0x65, 0x6e, 0x5f, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01,
-	0x52, 0x0d, 0x68, 0x73, 0x6d, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x88,
-	0x01, 0x01, 0x12, 0x27, 0x0a, 0x0d, 0x68, 0x73, 0x6d, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f,
-	0x70, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0b, 0x68, 0x73, 0x6d,
-	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x50, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x13, 0x0a, 0x11, 0x5f,
-	0x68, 0x73, 0x6d, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c,
-	0x42, 0x12, 0x0a, 0x10, 0x5f, 0x68, 0x73, 0x6d, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x6c,
-	0x61, 0x62, 0x65, 0x6c, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x68, 0x73, 0x6d, 0x5f, 0x74, 0x6f, 0x6b,
-	0x65, 0x6e, 0x5f, 0x70, 0x69, 0x6e, 0x22, 0x33, 0x0a, 0x15, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65,
-	0x4e, 0x6f, 0x72, 0x74, 0x68, 0x66, 0x6f, 0x6f, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
-	0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x22, 0x4f, 0x0a, 0x19, 0x52,
-	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x56, 0x65, 0x72, 0x62, 0x61, 0x74, 0x69, 0x6d, 0x48, 0x74, 0x74,
-	0x70, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x65, 0x72, 0x74,
-	0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x65, 0x72, 0x74,
-	0x55, 0x72, 0x6c, 0x12, 0x17, 0x0a, 0x07, 0x6b, 0x65, 0x79, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6b, 0x65, 0x79, 0x55, 0x72, 0x6c, 0x22, 0xf4, 0x01, 0x0a,
-	0x12, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x12, 0x38, 0x0a, 0x0b, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x74, 0x79,
-	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d,
-	0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x54, 0x79, 0x70,
-	0x65, 0x52, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x42, 0x0a,
-	0x09, 0x6e, 0x6f, 0x72, 0x74, 0x68, 0x66, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x22, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52,
-	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4e, 0x6f, 0x72, 0x74, 0x68, 0x66, 0x6f, 0x6f, 0x74, 0x43, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x48, 0x00, 0x52, 0x09, 0x6e, 0x6f, 0x72, 0x74, 0x68, 0x66, 0x6f, 0x6f,
-	0x74, 0x12, 0x4f, 0x0a, 0x0e, 0x76, 0x65, 0x72, 0x62, 0x61, 0x74, 0x69, 0x6d, 0x5f, 0x68, 0x74,
-	0x74, 0x70, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x61, 0x70, 0x69, 0x2e,
-	0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x56, 0x65,
-	0x72, 0x62, 0x61, 0x74, 0x69, 0x6d, 0x48, 0x74, 0x74, 0x70, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x48, 0x00, 0x52, 0x0d, 0x76, 0x65, 0x72, 0x62, 0x61, 0x74, 0x69, 0x6d, 0x48, 0x74, 0x74,
-	0x70, 0x73, 0x42, 0x0f, 0x0a, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x63, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x22, 0x22, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x40, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x53, 0x69,
-	0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x06,
-	0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61,
-	0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65,
-	0x72, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x22, 0x3b, 0x0a, 0x0a, 0x53, 0x69, 0x67,
-	0x6e, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x07, 0x73, 0x69, 0x67, 0x6e, 0x65,
-	0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d,
-	0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x07, 0x73,
-	0x69, 0x67, 0x6e, 0x65, 0x72, 0x73, 0x22, 0x48, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x69,
-	0x67, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a,
-	0x07, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
-	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67,
-	0x6e, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x07, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x73,
-	0x22, 0x42, 0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65,
-	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67,
-	0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x06, 0x73, 0x69,
-	0x67, 0x6e, 0x65, 0x72, 0x22, 0x25, 0x0a, 0x13, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x69,
-	0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x2a, 0x83, 0x01, 0x0a, 0x0a,
-	0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x53, 0x49,
-	0x47, 0x4e, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
-	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x49, 0x47, 0x4e, 0x45,
-	0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x49, 0x4e, 0x4d, 0x45, 0x4d, 0x10, 0x01, 0x12, 0x14,
-	0x0a, 0x10, 0x53, 0x49, 0x47, 0x4e, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x46, 0x49,
-	0x4c, 0x45, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x49, 0x47, 0x4e, 0x45, 0x52, 0x5f, 0x54,
-	0x59, 0x50, 0x45, 0x5f, 0x48, 0x53, 0x4d, 0x10, 0x03, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x49, 0x47,
-	0x4e, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x45, 0x4d, 0x4f, 0x54, 0x45, 0x10,
-	0x04, 0x2a, 0x83, 0x01, 0x0a, 0x0e, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79,
-	0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x1c, 0x50, 0x52, 0x49, 0x56, 0x41, 0x54, 0x45, 0x5f,
-	0x4b, 0x45, 0x59, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
-	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x52, 0x49, 0x56, 0x41, 0x54,
-	0x45, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x53, 0x41, 0x10, 0x01,
-	0x12, 0x17, 0x0a, 0x13, 0x50, 0x52, 0x49, 0x56, 0x41, 0x54, 0x45, 0x5f, 0x4b, 0x45, 0x59, 0x5f,
-	0x54, 0x59, 0x50, 0x45, 0x5f, 0x45, 0x43, 0x10, 0x02, 0x12, 0x1c, 0x0a, 0x18, 0x50, 0x52, 0x49,
-	0x56, 0x41, 0x54, 0x45, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x45, 0x44,
-	0x32, 0x35, 0x35, 0x31, 0x39, 0x10, 0x03, 0x2a, 0x64, 0x0a, 0x0a, 0x52, 0x65, 0x6d, 0x6f, 0x74,
-	0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x52, 0x45, 0x4d, 0x4f, 0x54, 0x45, 0x5f,
-	0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
-	0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x52, 0x45, 0x4d, 0x4f, 0x54, 0x45, 0x5f, 0x54, 0x59, 0x50,
-	0x45, 0x5f, 0x4e, 0x4f, 0x52, 0x54, 0x48, 0x46, 0x4f, 0x4f, 0x54, 0x10, 0x01, 0x12, 0x1e, 0x0a,
-	0x1a, 0x52, 0x45, 0x4d, 0x4f, 0x54, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x56, 0x45, 0x52,
-	0x42, 0x41, 0x54, 0x49, 0x4d, 0x5f, 0x48, 0x54, 0x54, 0x50, 0x53, 0x10, 0x02, 0x32, 0xbc, 0x02,
-	0x0a, 0x11, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x4a, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72,
-	0x12, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47,
-	0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x1e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
-	0x74, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x47, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x73, 0x12, 0x16,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x20, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d,
-	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x73,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x20, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d,
-	0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x69, 0x67,
-	0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
-	0x74, 0x79, 0x12, 0x48, 0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x69, 0x67, 0x6e,
-	0x65, 0x72, 0x12, 0x20, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31,
-	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x42, 0x31, 0x5a, 0x2f,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x61, 0x6b, 0x65, 0x78,
-	0x6b, 0x73, 0x2f, 0x6e, 0x6f, 0x72, 0x74, 0x68, 0x66, 0x6f, 0x6f, 0x74, 0x2f, 0x61, 0x70, 0x69,
-	0x2f, 0x6d, 0x67, 0x6d, 0x74, 0x2f, 0x76, 0x31, 0x3b, 0x6d, 0x67, 0x6d, 0x74, 0x76, 0x31, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
-
-var (
-	file_api_mgmt_v1_mgmt_proto_rawDescOnce sync.Once
-	file_api_mgmt_v1_mgmt_proto_rawDescData = file_api_mgmt_v1_mgmt_proto_rawDesc
-)
-
-func file_api_mgmt_v1_mgmt_proto_rawDescGZIP() []byte {
-	file_api_mgmt_v1_mgmt_proto_rawDescOnce.Do(func() {
-		file_api_mgmt_v1_mgmt_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_mgmt_v1_mgmt_proto_rawDescData)
-	})
-	return file_api_mgmt_v1_mgmt_proto_rawDescData
-}
-
-var file_api_mgmt_v1_mgmt_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_api_mgmt_v1_mgmt_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
-var file_api_mgmt_v1_mgmt_proto_goTypes = []interface{}{
-	(SignerType)(0),                   // 0: api.mgmt.v1.SignerType
-	(PrivateKeyType)(0),               // 1: api.mgmt.v1.PrivateKeyType
-	(RemoteType)(0),                   // 2: api.mgmt.v1.RemoteType
-	(*Signer)(nil),                    // 3: api.mgmt.v1.Signer
-	(*SignerInMemConfig)(nil),         // 4: api.mgmt.v1.SignerInMemConfig
-	(*SignerFileConfig)(nil),          // 5: api.mgmt.v1.SignerFileConfig
-	(*SignerHSMConfig)(nil),           // 6: api.mgmt.v1.SignerHSMConfig
-	(*RemoteNorthfootConfig)(nil),     // 7: api.mgmt.v1.RemoteNorthfootConfig
-	(*RemoteVerbatimHttpsConfig)(nil), // 8: api.mgmt.v1.RemoteVerbatimHttpsConfig
-	(*SignerRemoteConfig)(nil),        // 9: api.mgmt.v1.SignerRemoteConfig
-	(*GetSignerRequest)(nil),          // 10: api.mgmt.v1.GetSignerRequest
-	(*GetSignerResponse)(nil),         // 11: api.mgmt.v1.GetSignerResponse
-	(*SignerList)(nil),                // 12: api.mgmt.v1.SignerList
-	(*ListSignersResponse)(nil),       // 13: api.mgmt.v1.ListSignersResponse
-	(*CreateSignerRequest)(nil),       // 14: api.mgmt.v1.CreateSignerRequest
-	(*DeleteSignerRequest)(nil),       // 15: api.mgmt.v1.DeleteSignerRequest
-	(*emptypb.Empty)(nil),             // 16: google.protobuf.Empty
-}
-var file_api_mgmt_v1_mgmt_proto_depIdxs = []int32{
-	0,  // 0: api.mgmt.v1.Signer.type:type_name -> api.mgmt.v1.SignerType
-	4,  // 1: api.mgmt.v1.Signer.in_mem:type_name -> api.mgmt.v1.SignerInMemConfig
-	5,  // 2: api.mgmt.v1.Signer.file:type_name -> api.mgmt.v1.SignerFileConfig
-	6,  // 3: api.mgmt.v1.Signer.hsm:type_name -> api.mgmt.v1.SignerHSMConfig
-	9,  // 4: api.mgmt.v1.Signer.remote:type_name -> api.mgmt.v1.SignerRemoteConfig
-	1,  // 5: api.mgmt.v1.SignerInMemConfig.key:type_name -> api.mgmt.v1.PrivateKeyType
-	2,  // 6: api.mgmt.v1.SignerRemoteConfig.remote_type:type_name -> api.mgmt.v1.RemoteType
-	7,  // 7: api.mgmt.v1.SignerRemoteConfig.northfoot:type_name -> api.mgmt.v1.RemoteNorthfootConfig
-	8,  // 8: api.mgmt.v1.SignerRemoteConfig.verbatim_https:type_name -> api.mgmt.v1.RemoteVerbatimHttpsConfig
-	3,  // 9: api.mgmt.v1.GetSignerResponse.signer:type_name -> api.mgmt.v1.Signer
-	3,  // 10: api.mgmt.v1.SignerList.signers:type_name -> api.mgmt.v1.Signer
-	12, // 11: api.mgmt.v1.ListSignersResponse.signers:type_name -> api.mgmt.v1.SignerList
-	3,  // 12: api.mgmt.v1.CreateSignerRequest.signer:type_name -> api.mgmt.v1.Signer
-	10, // 13: api.mgmt.v1.ManagementService.GetSigner:input_type -> api.mgmt.v1.GetSignerRequest
-	16, // 14: api.mgmt.v1.ManagementService.ListSigners:input_type -> google.protobuf.Empty
-	14, // 15: api.mgmt.v1.ManagementService.CreateSigner:input_type -> api.mgmt.v1.CreateSignerRequest
-	15, // 16: api.mgmt.v1.ManagementService.DeleteSigner:input_type -> api.mgmt.v1.DeleteSignerRequest
-	11, // 17: api.mgmt.v1.ManagementService.GetSigner:output_type -> api.mgmt.v1.GetSignerResponse
-	13, // 18: api.mgmt.v1.ManagementService.ListSigners:output_type -> api.mgmt.v1.ListSignersResponse
-	16, // 19: api.mgmt.v1.ManagementService.CreateSigner:output_type -> google.protobuf.Empty
-	16, // 20: api.mgmt.v1.ManagementService.DeleteSigner:output_type -> google.protobuf.Empty
-	17, // [17:21] is the sub-list for method output_type
-	13, // [13:17] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
-}
-
-func init() { file_api_mgmt_v1_mgmt_proto_init() }
-func file_api_mgmt_v1_mgmt_proto_init() {
-	if File_api_mgmt_v1_mgmt_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_api_mgmt_v1_mgmt_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Signer); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignerFileConfig.ProtoReflect.Descriptor instead.
+func (*SignerFileConfig) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SignerFileConfig) GetTlsCertFilePath() string {
+	if x != nil {
+		return x.TlsCertFilePath
+	}
+	return ""
+}
+
+func (x *SignerFileConfig) GetTlsKeyFilePath() string {
+	if x != nil {
+		return x.TlsKeyFilePath
+	}
+	return ""
+}
+
+func (x *SignerFileConfig) GetKey() PrivateKeyType {
+	if x != nil {
+		return x.Key
+	}
+	return PrivateKeyType_PRIVATE_KEY_TYPE_UNSPECIFIED
+}
+
+func (x *SignerFileConfig) GetKeySize() int64 {
+	if x != nil && x.KeySize != nil {
+		return *x.KeySize
+	}
+	return 0
+}
+
+func (x *SignerFileConfig) GetCa() *CAConfig {
+	if x != nil {
+		return x.Ca
+	}
+	return nil
+}
+
+type SignerHSMConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// path to the PKCS#11 module
+	HsmLibraryPath string `protobuf:"bytes,1,opt,name=hsm_library_path,json=hsmLibraryPath,proto3" json:"hsm_library_path,omitempty"`
+	// exactly one of serial or label selects the token
+	HsmTokenSerial *string `protobuf:"bytes,2,opt,name=hsm_token_serial,json=hsmTokenSerial,proto3,oneof" json:"hsm_token_serial,omitempty"`
+	HsmTokenLabel  *string `protobuf:"bytes,3,opt,name=hsm_token_label,json=hsmTokenLabel,proto3,oneof" json:"hsm_token_label,omitempty"`
+	// never returned by the management API
+	HsmTokenPin *string `protobuf:"bytes,4,opt,name=hsm_token_pin,json=hsmTokenPin,proto3,oneof" json:"hsm_token_pin,omitempty"`
+	// label of the CA key pair and certificate on the token (default "northfoot-ca")
+	HsmKeyLabel *string `protobuf:"bytes,5,opt,name=hsm_key_label,json=hsmKeyLabel,proto3,oneof" json:"hsm_key_label,omitempty"`
+	// key type to create on the token if no key pair with hsm_key_label exists
+	Key PrivateKeyType `protobuf:"varint,6,opt,name=key,proto3,enum=api.mgmt.v1.PrivateKeyType" json:"key,omitempty"`
+	// see SignerInMemConfig.key_size
+	KeySize *int64 `protobuf:"varint,7,opt,name=key_size,json=keySize,proto3,oneof" json:"key_size,omitempty"`
+	// the self-signed CA certificate to create if there is none on the token
+	Ca *CAConfig `protobuf:"bytes,8,opt,name=ca,proto3,oneof" json:"ca,omitempty"`
+}
+
+func (x *SignerHSMConfig) Reset() {
+	*x = SignerHSMConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignerHSMConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignerHSMConfig) ProtoMessage() {}
+
+func (x *SignerHSMConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignerHSMConfig.ProtoReflect.Descriptor instead.
+func (*SignerHSMConfig) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SignerHSMConfig) GetHsmLibraryPath() string {
+	if x != nil {
+		return x.HsmLibraryPath
+	}
+	return ""
+}
+
+func (x *SignerHSMConfig) GetHsmTokenSerial() string {
+	if x != nil && x.HsmTokenSerial != nil {
+		return *x.HsmTokenSerial
+	}
+	return ""
+}
+
+func (x *SignerHSMConfig) GetHsmTokenLabel() string {
+	if x != nil && x.HsmTokenLabel != nil {
+		return *x.HsmTokenLabel
+	}
+	return ""
+}
+
+func (x *SignerHSMConfig) GetHsmTokenPin() string {
+	if x != nil && x.HsmTokenPin != nil {
+		return *x.HsmTokenPin
+	}
+	return ""
+}
+
+func (x *SignerHSMConfig) GetHsmKeyLabel() string {
+	if x != nil && x.HsmKeyLabel != nil {
+		return *x.HsmKeyLabel
+	}
+	return ""
+}
+
+func (x *SignerHSMConfig) GetKey() PrivateKeyType {
+	if x != nil {
+		return x.Key
+	}
+	return PrivateKeyType_PRIVATE_KEY_TYPE_UNSPECIFIED
+}
+
+func (x *SignerHSMConfig) GetKeySize() int64 {
+	if x != nil && x.KeySize != nil {
+		return *x.KeySize
+	}
+	return 0
+}
+
+func (x *SignerHSMConfig) GetCa() *CAConfig {
+	if x != nil {
+		return x.Ca
+	}
+	return nil
+}
+
+type RemoteNorthfootConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// base URL of the upstream Northfoot, e.g. https://northfoot.example.com
+	Endpoint string `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	// signer on the upstream instance that issues the intermediate CA
+	SignerId int64 `protobuf:"varint,2,opt,name=signer_id,json=signerId,proto3" json:"signer_id,omitempty"`
+	// sent as the Authorization header to the upstream, never returned by the management API. Requires
+	// an https endpoint.
+	Token *string `protobuf:"bytes,3,opt,name=token,proto3,oneof" json:"token,omitempty"`
+	// key type of the intermediate CA to generate in memory
+	Key PrivateKeyType `protobuf:"varint,4,opt,name=key,proto3,enum=api.mgmt.v1.PrivateKeyType" json:"key,omitempty"`
+	// see SignerInMemConfig.key_size
+	KeySize *int64 `protobuf:"varint,5,opt,name=key_size,json=keySize,proto3,oneof" json:"key_size,omitempty"`
+	// requested lifetime of the intermediate CA (default 1 year)
+	Duration *durationpb.Duration `protobuf:"bytes,6,opt,name=duration,proto3,oneof" json:"duration,omitempty"`
+	// subject, trust domain, SANs and name constraints to request for the intermediate CA. Its validity
+	// replaces duration, while the path length and extended key usages are decided by the upstream.
+	Ca *CAConfig `protobuf:"bytes,7,opt,name=ca,proto3,oneof" json:"ca,omitempty"`
+	// PEM bundle of CAs trusted to serve endpoint (default system roots)
+	CaFilePath *string `protobuf:"bytes,8,opt,name=ca_file_path,json=caFilePath,proto3,oneof" json:"ca_file_path,omitempty"`
+	// client certificate and key presented to the upstream, if it requires client auth
+	ClientCertFilePath *string `protobuf:"bytes,9,opt,name=client_cert_file_path,json=clientCertFilePath,proto3,oneof" json:"client_cert_file_path,omitempty"`
+	ClientKeyFilePath  *string `protobuf:"bytes,10,opt,name=client_key_file_path,json=clientKeyFilePath,proto3,oneof" json:"client_key_file_path,omitempty"`
+}
+
+func (x *RemoteNorthfootConfig) Reset() {
+	*x = RemoteNorthfootConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoteNorthfootConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoteNorthfootConfig) ProtoMessage() {}
+
+func (x *RemoteNorthfootConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoteNorthfootConfig.ProtoReflect.Descriptor instead.
+func (*RemoteNorthfootConfig) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RemoteNorthfootConfig) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *RemoteNorthfootConfig) GetSignerId() int64 {
+	if x != nil {
+		return x.SignerId
+	}
+	return 0
+}
+
+func (x *RemoteNorthfootConfig) GetToken() string {
+	if x != nil && x.Token != nil {
+		return *x.Token
+	}
+	return ""
+}
+
+func (x *RemoteNorthfootConfig) GetKey() PrivateKeyType {
+	if x != nil {
+		return x.Key
+	}
+	return PrivateKeyType_PRIVATE_KEY_TYPE_UNSPECIFIED
+}
+
+func (x *RemoteNorthfootConfig) GetKeySize() int64 {
+	if x != nil && x.KeySize != nil {
+		return *x.KeySize
+	}
+	return 0
+}
+
+func (x *RemoteNorthfootConfig) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *RemoteNorthfootConfig) GetCa() *CAConfig {
+	if x != nil {
+		return x.Ca
+	}
+	return nil
+}
+
+func (x *RemoteNorthfootConfig) GetCaFilePath() string {
+	if x != nil && x.CaFilePath != nil {
+		return *x.CaFilePath
+	}
+	return ""
+}
+
+func (x *RemoteNorthfootConfig) GetClientCertFilePath() string {
+	if x != nil && x.ClientCertFilePath != nil {
+		return *x.ClientCertFilePath
+	}
+	return ""
+}
+
+func (x *RemoteNorthfootConfig) GetClientKeyFilePath() string {
+	if x != nil && x.ClientKeyFilePath != nil {
+		return *x.ClientKeyFilePath
+	}
+	return ""
+}
+
+type RemoteVerbatimHttpsConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// PEM encoded CA certificate, optionally followed by its chain
+	CertUrl string `protobuf:"bytes,1,opt,name=cert_url,json=certUrl,proto3" json:"cert_url,omitempty"`
+	// PEM encoded CA private key (PKCS#1, PKCS#8 or SEC1)
+	KeyUrl string `protobuf:"bytes,2,opt,name=key_url,json=keyUrl,proto3" json:"key_url,omitempty"`
+	// PEM bundle of CAs trusted to serve cert_url and key_url (default system roots)
+	CaFilePath *string `protobuf:"bytes,3,opt,name=ca_file_path,json=caFilePath,proto3,oneof" json:"ca_file_path,omitempty"`
+	// client certificate and key presented to the server, if it requires client auth
+	ClientCertFilePath *string `protobuf:"bytes,4,opt,name=client_cert_file_path,json=clientCertFilePath,proto3,oneof" json:"client_cert_file_path,omitempty"`
+	ClientKeyFilePath  *string `protobuf:"bytes,5,opt,name=client_key_file_path,json=clientKeyFilePath,proto3,oneof" json:"client_key_file_path,omitempty"`
+	// how often to re-fetch the cert and key to pick up rotations (default 1 hour)
+	RefreshInterval *durationpb.Duration `protobuf:"bytes,6,opt,name=refresh_interval,json=refreshInterval,proto3,oneof" json:"refresh_interval,omitempty"`
+}
+
+func (x *RemoteVerbatimHttpsConfig) Reset() {
+	*x = RemoteVerbatimHttpsConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoteVerbatimHttpsConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoteVerbatimHttpsConfig) ProtoMessage() {}
+
+func (x *RemoteVerbatimHttpsConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoteVerbatimHttpsConfig.ProtoReflect.Descriptor instead.
+func (*RemoteVerbatimHttpsConfig) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RemoteVerbatimHttpsConfig) GetCertUrl() string {
+	if x != nil {
+		return x.CertUrl
+	}
+	return ""
+}
+
+func (x *RemoteVerbatimHttpsConfig) GetKeyUrl() string {
+	if x != nil {
+		return x.KeyUrl
+	}
+	return ""
+}
+
+func (x *RemoteVerbatimHttpsConfig) GetCaFilePath() string {
+	if x != nil && x.CaFilePath != nil {
+		return *x.CaFilePath
+	}
+	return ""
+}
+
+func (x *RemoteVerbatimHttpsConfig) GetClientCertFilePath() string {
+	if x != nil && x.ClientCertFilePath != nil {
+		return *x.ClientCertFilePath
+	}
+	return ""
+}
+
+func (x *RemoteVerbatimHttpsConfig) GetClientKeyFilePath() string {
+	if x != nil && x.ClientKeyFilePath != nil {
+		return *x.ClientKeyFilePath
+	}
+	return ""
+}
+
+func (x *RemoteVerbatimHttpsConfig) GetRefreshInterval() *durationpb.Duration {
+	if x != nil {
+		return x.RefreshInterval
+	}
+	return nil
+}
+
+type SignerRemoteConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RemoteType RemoteType `protobuf:"varint,1,opt,name=remote_type,json=remoteType,proto3,enum=api.mgmt.v1.RemoteType" json:"remote_type,omitempty"`
+	// Types that are assignable to RemoteConfig:
+	//	*SignerRemoteConfig_Northfoot
+	//	*SignerRemoteConfig_VerbatimHttps
+	RemoteConfig isSignerRemoteConfig_RemoteConfig `protobuf_oneof:"remote_config"`
+}
+
+func (x *SignerRemoteConfig) Reset() {
+	*x = SignerRemoteConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignerRemoteConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignerRemoteConfig) ProtoMessage() {}
+
+func (x *SignerRemoteConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignerRemoteConfig.ProtoReflect.Descriptor instead.
+func (*SignerRemoteConfig) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SignerRemoteConfig) GetRemoteType() RemoteType {
+	if x != nil {
+		return x.RemoteType
+	}
+	return RemoteType_REMOTE_TYPE_UNSPECIFIED
+}
+
+func (m *SignerRemoteConfig) GetRemoteConfig() isSignerRemoteConfig_RemoteConfig {
+	if m != nil {
+		return m.RemoteConfig
+	}
+	return nil
+}
+
+func (x *SignerRemoteConfig) GetNorthfoot() *RemoteNorthfootConfig {
+	if x, ok := x.GetRemoteConfig().(*SignerRemoteConfig_Northfoot); ok {
+		return x.Northfoot
+	}
+	return nil
+}
+
+func (x *SignerRemoteConfig) GetVerbatimHttps() *RemoteVerbatimHttpsConfig {
+	if x, ok := x.GetRemoteConfig().(*SignerRemoteConfig_VerbatimHttps); ok {
+		return x.VerbatimHttps
+	}
+	return nil
+}
+
+type isSignerRemoteConfig_RemoteConfig interface {
+	isSignerRemoteConfig_RemoteConfig()
+}
+
+type SignerRemoteConfig_Northfoot struct {
+	Northfoot *RemoteNorthfootConfig `protobuf:"bytes,2,opt,name=northfoot,proto3,oneof"`
+}
+
+type SignerRemoteConfig_VerbatimHttps struct {
+	VerbatimHttps *RemoteVerbatimHttpsConfig `protobuf:"bytes,3,opt,name=verbatim_https,json=verbatimHttps,proto3,oneof"`
+}
+
+func (*SignerRemoteConfig_Northfoot) isSignerRemoteConfig_RemoteConfig() {}
+
+func (*SignerRemoteConfig_VerbatimHttps) isSignerRemoteConfig_RemoteConfig() {}
+
+type GetSignerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetSignerRequest) Reset() {
+	*x = GetSignerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSignerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSignerRequest) ProtoMessage() {}
+
+func (x *GetSignerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSignerRequest.ProtoReflect.Descriptor instead.
+func (*GetSignerRequest) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetSignerRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetSignerResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Signer *Signer `protobuf:"bytes,1,opt,name=signer,proto3" json:"signer,omitempty"`
+}
+
+func (x *GetSignerResponse) Reset() {
+	*x = GetSignerResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSignerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSignerResponse) ProtoMessage() {}
+
+func (x *GetSignerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSignerResponse.ProtoReflect.Descriptor instead.
+func (*GetSignerResponse) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetSignerResponse) GetSigner() *Signer {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+type SignerList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Signers []*Signer `protobuf:"bytes,1,rep,name=signers,proto3" json:"signers,omitempty"`
+}
+
+func (x *SignerList) Reset() {
+	*x = SignerList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignerList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignerList) ProtoMessage() {}
+
+func (x *SignerList) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignerList.ProtoReflect.Descriptor instead.
+func (*SignerList) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SignerList) GetSigners() []*Signer {
+	if x != nil {
+		return x.Signers
+	}
+	return nil
+}
+
+type ListSignersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Signers *SignerList `protobuf:"bytes,1,opt,name=signers,proto3" json:"signers,omitempty"`
+}
+
+func (x *ListSignersResponse) Reset() {
+	*x = ListSignersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListSignersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSignersResponse) ProtoMessage() {}
+
+func (x *ListSignersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSignersResponse.ProtoReflect.Descriptor instead.
+func (*ListSignersResponse) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListSignersResponse) GetSigners() *SignerList {
+	if x != nil {
+		return x.Signers
+	}
+	return nil
+}
+
+type CreateSignerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Signer *Signer `protobuf:"bytes,1,opt,name=signer,proto3" json:"signer,omitempty"`
+}
+
+func (x *CreateSignerRequest) Reset() {
+	*x = CreateSignerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateSignerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSignerRequest) ProtoMessage() {}
+
+func (x *CreateSignerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSignerRequest.ProtoReflect.Descriptor instead.
+func (*CreateSignerRequest) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CreateSignerRequest) GetSigner() *Signer {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+type UpdateSignerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// the signer to update, identified by its id
+	Signer *Signer `protobuf:"bytes,1,opt,name=signer,proto3" json:"signer,omitempty"`
+	// fields of signer to update, e.g. policy.max_lifetime. Fields in the mask that are unset in signer
+	// are cleared, including secrets that GetSigner does not return.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// if set, the update is aborted unless the stored signer still has this version
+	Version *int64 `protobuf:"varint,3,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	// changes to type, in_mem, file, hsm or remote replace the signer's CA with a new one, and are
+	// refused unless this is set. Other fields are applied to the running signer.
+	AllowNewKey bool `protobuf:"varint,4,opt,name=allow_new_key,json=allowNewKey,proto3" json:"allow_new_key,omitempty"`
+}
+
+func (x *UpdateSignerRequest) Reset() {
+	*x = UpdateSignerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateSignerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSignerRequest) ProtoMessage() {}
+
+func (x *UpdateSignerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSignerRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSignerRequest) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *UpdateSignerRequest) GetSigner() *Signer {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+func (x *UpdateSignerRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateSignerRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+func (x *UpdateSignerRequest) GetAllowNewKey() bool {
+	if x != nil {
+		return x.AllowNewKey
+	}
+	return false
+}
+
+type UpdateSignerResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Signer *Signer `protobuf:"bytes,1,opt,name=signer,proto3" json:"signer,omitempty"`
+	// whether the update replaced the signer's CA
+	NewKey bool `protobuf:"varint,2,opt,name=new_key,json=newKey,proto3" json:"new_key,omitempty"`
+}
+
+func (x *UpdateSignerResponse) Reset() {
+	*x = UpdateSignerResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateSignerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSignerResponse) ProtoMessage() {}
+
+func (x *UpdateSignerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSignerResponse.ProtoReflect.Descriptor instead.
+func (*UpdateSignerResponse) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *UpdateSignerResponse) GetSigner() *Signer {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+func (x *UpdateSignerResponse) GetNewKey() bool {
+	if x != nil {
+		return x.NewKey
+	}
+	return false
+}
+
+type RotateSignerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// override the signer's rotation config
+	Overlap   *durationpb.Duration `protobuf:"bytes,2,opt,name=overlap,proto3,oneof" json:"overlap,omitempty"`
+	CrossSign *bool                `protobuf:"varint,3,opt,name=cross_sign,json=crossSign,proto3,oneof" json:"cross_sign,omitempty"`
+}
+
+func (x *RotateSignerRequest) Reset() {
+	*x = RotateSignerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RotateSignerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateSignerRequest) ProtoMessage() {}
+
+func (x *RotateSignerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateSignerRequest.ProtoReflect.Descriptor instead.
+func (*RotateSignerRequest) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RotateSignerRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RotateSignerRequest) GetOverlap() *durationpb.Duration {
+	if x != nil {
+		return x.Overlap
+	}
+	return nil
+}
+
+func (x *RotateSignerRequest) GetCrossSign() bool {
+	if x != nil && x.CrossSign != nil {
+		return *x.CrossSign
+	}
+	return false
+}
+
+type RotateSignerResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// DER encoded CA certificate that now issues leaves
+	Ca []byte `protobuf:"bytes,1,opt,name=ca,proto3" json:"ca,omitempty"`
+	// DER encoded certificates returned by TrustBundle, including the previous CA during the overlap
+	TrustBundle [][]byte `protobuf:"bytes,2,rep,name=trust_bundle,json=trustBundle,proto3" json:"trust_bundle,omitempty"`
+}
+
+func (x *RotateSignerResponse) Reset() {
+	*x = RotateSignerResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RotateSignerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateSignerResponse) ProtoMessage() {}
+
+func (x *RotateSignerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateSignerResponse.ProtoReflect.Descriptor instead.
+func (*RotateSignerResponse) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RotateSignerResponse) GetCa() []byte {
+	if x != nil {
+		return x.Ca
+	}
+	return nil
+}
+
+func (x *RotateSignerResponse) GetTrustBundle() [][]byte {
+	if x != nil {
+		return x.TrustBundle
+	}
+	return nil
+}
+
+type DeleteSignerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteSignerRequest) Reset() {
+	*x = DeleteSignerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteSignerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSignerRequest) ProtoMessage() {}
+
+func (x *DeleteSignerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSignerRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSignerRequest) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DeleteSignerRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// Certificate is a record of a certificate issued by a signer.
+type Certificate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// lower case hex encoded serial number
+	Serial         string                 `protobuf:"bytes,1,opt,name=serial,proto3" json:"serial,omitempty"`
+	SignerId       int64                  `protobuf:"varint,2,opt,name=signer_id,json=signerId,proto3" json:"signer_id,omitempty"`
+	Subject        string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	DnsNames       []string               `protobuf:"bytes,4,rep,name=dns_names,json=dnsNames,proto3" json:"dns_names,omitempty"`
+	IpAddresses    []string               `protobuf:"bytes,5,rep,name=ip_addresses,json=ipAddresses,proto3" json:"ip_addresses,omitempty"`
+	EmailAddresses []string               `protobuf:"bytes,6,rep,name=email_addresses,json=emailAddresses,proto3" json:"email_addresses,omitempty"`
+	Uris           []string               `protobuf:"bytes,7,rep,name=uris,proto3" json:"uris,omitempty"`
+	NotBefore      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	NotAfter       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	// authenticated identity of the caller that requested the certificate, if known
+	Requester string `protobuf:"bytes,10,opt,name=requester,proto3" json:"requester,omitempty"`
+	// hex encoded SHA-256 of the DER encoded CSR
+	CsrSha256 string `protobuf:"bytes,11,opt,name=csr_sha256,json=csrSha256,proto3" json:"csr_sha256,omitempty"`
+	// DER encoded certificate
+	Cert []byte `protobuf:"bytes,12,opt,name=cert,proto3" json:"cert,omitempty"`
+	// set if the certificate has been revoked
+	RevokedAt        *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=revoked_at,json=revokedAt,proto3,oneof" json:"revoked_at,omitempty"`
+	RevocationReason RevocationReason       `protobuf:"varint,14,opt,name=revocation_reason,json=revocationReason,proto3,enum=api.mgmt.v1.RevocationReason" json:"revocation_reason,omitempty"`
+}
+
+func (x *Certificate) Reset() {
+	*x = Certificate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Certificate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Certificate) ProtoMessage() {}
+
+func (x *Certificate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Certificate.ProtoReflect.Descriptor instead.
+func (*Certificate) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Certificate) GetSerial() string {
+	if x != nil {
+		return x.Serial
+	}
+	return ""
+}
+
+func (x *Certificate) GetSignerId() int64 {
+	if x != nil {
+		return x.SignerId
+	}
+	return 0
+}
+
+func (x *Certificate) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Certificate) GetDnsNames() []string {
+	if x != nil {
+		return x.DnsNames
+	}
+	return nil
+}
+
+func (x *Certificate) GetIpAddresses() []string {
+	if x != nil {
+		return x.IpAddresses
+	}
+	return nil
+}
+
+func (x *Certificate) GetEmailAddresses() []string {
+	if x != nil {
+		return x.EmailAddresses
+	}
+	return nil
+}
+
+func (x *Certificate) GetUris() []string {
+	if x != nil {
+		return x.Uris
+	}
+	return nil
+}
+
+func (x *Certificate) GetNotBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotBefore
+	}
+	return nil
+}
+
+func (x *Certificate) GetNotAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotAfter
+	}
+	return nil
+}
+
+func (x *Certificate) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+func (x *Certificate) GetCsrSha256() string {
+	if x != nil {
+		return x.CsrSha256
+	}
+	return ""
+}
+
+func (x *Certificate) GetCert() []byte {
+	if x != nil {
+		return x.Cert
+	}
+	return nil
+}
+
+func (x *Certificate) GetRevokedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
+
+func (x *Certificate) GetRevocationReason() RevocationReason {
+	if x != nil {
+		return x.RevocationReason
+	}
+	return RevocationReason_REVOCATION_REASON_UNSPECIFIED
+}
+
+type ListCertificatesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// only certificates issued by this signer
+	SignerId *int64 `protobuf:"varint,1,opt,name=signer_id,json=signerId,proto3,oneof" json:"signer_id,omitempty"`
+	// only certificates that expire before this time
+	ExpiringBefore *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expiring_before,json=expiringBefore,proto3,oneof" json:"expiring_before,omitempty"`
+	// only certificates with a SAN containing this substring
+	San *string `protobuf:"bytes,3,opt,name=san,proto3,oneof" json:"san,omitempty"`
+	// maximum number of certificates to return (default 1000)
+	Limit *int64 `protobuf:"varint,4,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+}
+
+func (x *ListCertificatesRequest) Reset() {
+	*x = ListCertificatesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCertificatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCertificatesRequest) ProtoMessage() {}
+
+func (x *ListCertificatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCertificatesRequest.ProtoReflect.Descriptor instead.
+func (*ListCertificatesRequest) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListCertificatesRequest) GetSignerId() int64 {
+	if x != nil && x.SignerId != nil {
+		return *x.SignerId
+	}
+	return 0
+}
+
+func (x *ListCertificatesRequest) GetExpiringBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiringBefore
+	}
+	return nil
+}
+
+func (x *ListCertificatesRequest) GetSan() string {
+	if x != nil && x.San != nil {
+		return *x.San
+	}
+	return ""
+}
+
+func (x *ListCertificatesRequest) GetLimit() int64 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+type ListCertificatesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Certificates []*Certificate `protobuf:"bytes,1,rep,name=certificates,proto3" json:"certificates,omitempty"`
+}
+
+func (x *ListCertificatesResponse) Reset() {
+	*x = ListCertificatesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCertificatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCertificatesResponse) ProtoMessage() {}
+
+func (x *ListCertificatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCertificatesResponse.ProtoReflect.Descriptor instead.
+func (*ListCertificatesResponse) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListCertificatesResponse) GetCertificates() []*Certificate {
+	if x != nil {
+		return x.Certificates
+	}
+	return nil
+}
+
+type GetCertificateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// hex encoded serial number
+	Serial string `protobuf:"bytes,1,opt,name=serial,proto3" json:"serial,omitempty"`
+}
+
+func (x *GetCertificateRequest) Reset() {
+	*x = GetCertificateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCertificateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCertificateRequest) ProtoMessage() {}
+
+func (x *GetCertificateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCertificateRequest.ProtoReflect.Descriptor instead.
+func (*GetCertificateRequest) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetCertificateRequest) GetSerial() string {
+	if x != nil {
+		return x.Serial
+	}
+	return ""
+}
+
+type GetCertificateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Certificate *Certificate `protobuf:"bytes,1,opt,name=certificate,proto3" json:"certificate,omitempty"`
+}
+
+func (x *GetCertificateResponse) Reset() {
+	*x = GetCertificateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCertificateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCertificateResponse) ProtoMessage() {}
+
+func (x *GetCertificateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCertificateResponse.ProtoReflect.Descriptor instead.
+func (*GetCertificateResponse) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetCertificateResponse) GetCertificate() *Certificate {
+	if x != nil {
+		return x.Certificate
+	}
+	return nil
+}
+
+type RevokeCertificateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// hex encoded serial number
+	Serial string           `protobuf:"bytes,1,opt,name=serial,proto3" json:"serial,omitempty"`
+	Reason RevocationReason `protobuf:"varint,2,opt,name=reason,proto3,enum=api.mgmt.v1.RevocationReason" json:"reason,omitempty"`
+}
+
+func (x *RevokeCertificateRequest) Reset() {
+	*x = RevokeCertificateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevokeCertificateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeCertificateRequest) ProtoMessage() {}
+
+func (x *RevokeCertificateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeCertificateRequest.ProtoReflect.Descriptor instead.
+func (*RevokeCertificateRequest) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *RevokeCertificateRequest) GetSerial() string {
+	if x != nil {
+		return x.Serial
+	}
+	return ""
+}
+
+func (x *RevokeCertificateRequest) GetReason() RevocationReason {
+	if x != nil {
+		return x.Reason
+	}
+	return RevocationReason_REVOCATION_REASON_UNSPECIFIED
+}
+
+// RoleBinding grants a role to an authenticated identity.
+type RoleBinding struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// identity established by authentication, e.g. a SPIFFE ID. May be a glob such as spiffe://edge/ns/*/sa/*.
+	Identity string `protobuf:"bytes,1,opt,name=identity,proto3" json:"identity,omitempty"`
+	Role     Role   `protobuf:"varint,2,opt,name=role,proto3,enum=api.mgmt.v1.Role" json:"role,omitempty"`
+	// signers the role applies to, or all signers if empty
+	SignerIds []int64 `protobuf:"varint,3,rep,packed,name=signer_ids,json=signerIds,proto3" json:"signer_ids,omitempty"`
+}
+
+func (x *RoleBinding) Reset() {
+	*x = RoleBinding{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoleBinding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoleBinding) ProtoMessage() {}
+
+func (x *RoleBinding) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoleBinding.ProtoReflect.Descriptor instead.
+func (*RoleBinding) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RoleBinding) GetIdentity() string {
+	if x != nil {
+		return x.Identity
+	}
+	return ""
+}
+
+func (x *RoleBinding) GetRole() Role {
+	if x != nil {
+		return x.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+func (x *RoleBinding) GetSignerIds() []int64 {
+	if x != nil {
+		return x.SignerIds
+	}
+	return nil
+}
+
+type ListRoleBindingsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoleBindings []*RoleBinding `protobuf:"bytes,1,rep,name=role_bindings,json=roleBindings,proto3" json:"role_bindings,omitempty"`
+}
+
+func (x *ListRoleBindingsResponse) Reset() {
+	*x = ListRoleBindingsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListRoleBindingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoleBindingsResponse) ProtoMessage() {}
+
+func (x *ListRoleBindingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoleBindingsResponse.ProtoReflect.Descriptor instead.
+func (*ListRoleBindingsResponse) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListRoleBindingsResponse) GetRoleBindings() []*RoleBinding {
+	if x != nil {
+		return x.RoleBindings
+	}
+	return nil
+}
+
+type CreateRoleBindingRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoleBinding *RoleBinding `protobuf:"bytes,1,opt,name=role_binding,json=roleBinding,proto3" json:"role_binding,omitempty"`
+}
+
+func (x *CreateRoleBindingRequest) Reset() {
+	*x = CreateRoleBindingRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateRoleBindingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRoleBindingRequest) ProtoMessage() {}
+
+func (x *CreateRoleBindingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRoleBindingRequest.ProtoReflect.Descriptor instead.
+func (*CreateRoleBindingRequest) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CreateRoleBindingRequest) GetRoleBinding() *RoleBinding {
+	if x != nil {
+		return x.RoleBinding
+	}
+	return nil
+}
+
+type DeleteRoleBindingRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Identity string `protobuf:"bytes,1,opt,name=identity,proto3" json:"identity,omitempty"`
+	Role     Role   `protobuf:"varint,2,opt,name=role,proto3,enum=api.mgmt.v1.Role" json:"role,omitempty"`
+}
+
+func (x *DeleteRoleBindingRequest) Reset() {
+	*x = DeleteRoleBindingRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteRoleBindingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoleBindingRequest) ProtoMessage() {}
+
+func (x *DeleteRoleBindingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_mgmt_v1_mgmt_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoleBindingRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRoleBindingRequest) Descriptor() ([]byte, []int) {
+	return file_api_mgmt_v1_mgmt_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *DeleteRoleBindingRequest) GetIdentity() string {
+	if x != nil {
+		return x.Identity
+	}
+	return ""
+}
+
+func (x *DeleteRoleBindingRequest) GetRole() Role {
+	if x != nil {
+		return x.Role
+	}
+	return Role_ROLE_UNSPECIFIED
+}
+
+var File_api_mgmt_v1_mgmt_proto protoreflect.FileDescriptor
+
+var file_api_mgmt_v1_mgmt_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x61, 0x70, 0x69, 0x2f, 0x6d, 0x67, 0x6d, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x67,
+	0x6d, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67,
+	0x6d, 0x74, 0x2e, 0x76, 0x31, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb4, 0x09, 0x0a, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72,
+	0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x02,
+	0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x25,
+	0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x37, 0x0a, 0x06, 0x69, 0x6e, 0x5f, 0x6d, 0x65, 0x6d, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x49, 0x6e, 0x4d, 0x65, 0x6d, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x48, 0x00, 0x52, 0x05, 0x69, 0x6e, 0x4d, 0x65, 0x6d, 0x12, 0x33, 0x0a, 0x04, 0x66,
+	0x69, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x46, 0x69,
+	0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x00, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65,
+	0x12, 0x30, 0x0a, 0x03, 0x68, 0x73, 0x6d, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e,
+	0x65, 0x72, 0x48, 0x53, 0x4d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x00, 0x52, 0x03, 0x68,
+	0x73, 0x6d, 0x12, 0x39, 0x0a, 0x06, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x12, 0x2f, 0x0a,
+	0x11, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x63, 0x61, 0x5f, 0x69, 0x73, 0x73, 0x75, 0x61, 0x6e,
+	0x63, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x0f, 0x61, 0x6c, 0x6c, 0x6f,
+	0x77, 0x43, 0x61, 0x49, 0x73, 0x73, 0x75, 0x61, 0x6e, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3d,
+	0x0a, 0x18, 0x6f, 0x63, 0x73, 0x70, 0x5f, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64,
+	0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x64, 0x65, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08,
+	0x48, 0x05, 0x52, 0x16, 0x6f, 0x63, 0x73, 0x70, 0x44, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65,
+	0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x3b, 0x0a,
+	0x17, 0x73, 0x63, 0x65, 0x70, 0x5f, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x5f,
+	0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x48, 0x06,
+	0x52, 0x15, 0x73, 0x63, 0x65, 0x70, 0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x50,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x88, 0x01, 0x01, 0x12, 0x38, 0x0a, 0x06, 0x70, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x61, 0x6e, 0x63,
+	0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x48, 0x07, 0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x88, 0x01, 0x01, 0x12, 0x30, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73,
+	0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x08, 0x70, 0x72,
+	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x0f, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c,
+	0x74, 0x5f, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x08, 0x52, 0x0e, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x03, 0x48, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x88, 0x01, 0x01, 0x12, 0x3c, 0x0a, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x48, 0x0a, 0x52, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01,
+	0x01, 0x12, 0x29, 0x0a, 0x0e, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x5f, 0x63, 0x61, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x11, 0x20, 0x01, 0x28, 0x08, 0x48, 0x0b, 0x52, 0x0c, 0x70, 0x65, 0x72,
+	0x73, 0x69, 0x73, 0x74, 0x43, 0x61, 0x4b, 0x65, 0x79, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a,
+	0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x61, 0x63, 0x6d, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x08,
+	0x48, 0x0c, 0x52, 0x09, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x41, 0x63, 0x6d, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x20, 0x0a, 0x09, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x65, 0x73, 0x74, 0x18, 0x13, 0x20,
+	0x01, 0x28, 0x08, 0x48, 0x0d, 0x52, 0x08, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x45, 0x73, 0x74, 0x88,
+	0x01, 0x01, 0x12, 0x36, 0x0a, 0x15, 0x74, 0x72, 0x75, 0x73, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x5f,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x75, 0x74, 0x68, 0x18, 0x14, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x0e, 0x52, 0x12, 0x74, 0x72, 0x75, 0x73, 0x74, 0x46, 0x6f, 0x72, 0x43, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x41, 0x75, 0x74, 0x68, 0x88, 0x01, 0x01, 0x42, 0x0f, 0x0a, 0x0d, 0x73, 0x69,
+	0x67, 0x6e, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x42, 0x05, 0x0a, 0x03, 0x5f,
+	0x69, 0x64, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f,
+	0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x14, 0x0a, 0x12, 0x5f,
+	0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x63, 0x61, 0x5f, 0x69, 0x73, 0x73, 0x75, 0x61, 0x6e, 0x63,
+	0x65, 0x42, 0x1b, 0x0a, 0x19, 0x5f, 0x6f, 0x63, 0x73, 0x70, 0x5f, 0x64, 0x65, 0x6c, 0x65, 0x67,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x64, 0x65, 0x72, 0x42, 0x1a,
+	0x0a, 0x18, 0x5f, 0x73, 0x63, 0x65, 0x70, 0x5f, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67,
+	0x65, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x70,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c,
+	0x74, 0x5f, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x5f, 0x63,
+	0x61, 0x5f, 0x6b, 0x65, 0x79, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f,
+	0x61, 0x63, 0x6d, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x65,
+	0x73, 0x74, 0x42, 0x18, 0x0a, 0x16, 0x5f, 0x74, 0x72, 0x75, 0x73, 0x74, 0x5f, 0x66, 0x6f, 0x72,
+	0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x75, 0x74, 0x68, 0x22, 0xbe, 0x01, 0x0a,
+	0x0e, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x3a, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x08,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x38, 0x0a, 0x07, 0x6f,
+	0x76, 0x65, 0x72, 0x6c, 0x61, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x01, 0x52, 0x07, 0x6f, 0x76, 0x65, 0x72, 0x6c,
+	0x61, 0x70, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x6f, 0x73, 0x73, 0x5f, 0x73,
+	0x69, 0x67, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x72, 0x6f, 0x73, 0x73,
+	0x53, 0x69, 0x67, 0x6e, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
+	0x6c, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x6f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x70, 0x22, 0x4f, 0x0a,
+	0x09, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6f, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08,
+	0x63, 0x72, 0x69, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
+	0x63, 0x72, 0x69, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xc0,
+	0x02, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x34,
+	0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x5f, 0x75, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0e, 0x32, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x4b, 0x65, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x09, 0x6b, 0x65, 0x79, 0x55, 0x73,
+	0x61, 0x67, 0x65, 0x73, 0x12, 0x3e, 0x0a, 0x0e, 0x65, 0x78, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f,
+	0x75, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x74, 0x4b, 0x65,
+	0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x0c, 0x65, 0x78, 0x74, 0x4b, 0x65, 0x79, 0x55, 0x73,
+	0x61, 0x67, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x12, 0x65, 0x78, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f,
+	0x75, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x6f, 0x69, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0f, 0x65, 0x78, 0x74, 0x4b, 0x65, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x4f, 0x69, 0x64,
+	0x73, 0x12, 0x0e, 0x0a, 0x02, 0x63, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x02, 0x63,
+	0x61, 0x12, 0x25, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x6c, 0x65,
+	0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x50, 0x61,
+	0x74, 0x68, 0x4c, 0x65, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x36, 0x0a, 0x0a, 0x65, 0x78, 0x74, 0x65,
+	0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x6c, 0x65,
+	0x6e, 0x22, 0xce, 0x05, 0x0a, 0x0e, 0x49, 0x73, 0x73, 0x75, 0x61, 0x6e, 0x63, 0x65, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x12, 0x2a, 0x0a, 0x11, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f,
+	0x64, 0x6e, 0x73, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x44, 0x6e, 0x73, 0x4e, 0x61, 0x6d, 0x65, 0x73,
+	0x12, 0x28, 0x0a, 0x10, 0x64, 0x65, 0x6e, 0x69, 0x65, 0x64, 0x5f, 0x64, 0x6e, 0x73, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x64, 0x65, 0x6e, 0x69,
+	0x65, 0x64, 0x44, 0x6e, 0x73, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x61, 0x6c,
+	0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x69, 0x70, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x49, 0x70,
+	0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x64, 0x65, 0x6e, 0x69, 0x65, 0x64,
+	0x5f, 0x69, 0x70, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0e, 0x64, 0x65, 0x6e, 0x69, 0x65, 0x64, 0x49, 0x70, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73,
+	0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x75, 0x72, 0x69, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x55,
+	0x72, 0x69, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x6e, 0x69, 0x65, 0x64, 0x5f, 0x75, 0x72,
+	0x69, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x6e, 0x69, 0x65, 0x64,
+	0x55, 0x72, 0x69, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f,
+	0x65, 0x6d, 0x61, 0x69, 0x6c, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x07, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x13, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x45, 0x6d, 0x61, 0x69,
+	0x6c, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x12, 0x30, 0x0a, 0x14, 0x64, 0x65, 0x6e, 0x69,
+	0x65, 0x64, 0x5f, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73,
+	0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x12, 0x64, 0x65, 0x6e, 0x69, 0x65, 0x64, 0x45, 0x6d,
+	0x61, 0x69, 0x6c, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x12, 0x3d, 0x0a, 0x0d, 0x73, 0x75,
+	0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0c, 0x73, 0x75, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x41, 0x0a, 0x0c, 0x6d, 0x61, 0x78,
+	0x5f, 0x6c, 0x69, 0x66, 0x65, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0b, 0x6d, 0x61,
+	0x78, 0x4c, 0x69, 0x66, 0x65, 0x74, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x49, 0x0a, 0x10,
+	0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x48, 0x01, 0x52, 0x0f, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x4c, 0x69, 0x66, 0x65,
+	0x74, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x40, 0x0a, 0x0e, 0x69, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0d, 0x69, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x31, 0x0a, 0x09, 0x63, 0x65, 0x6c,
+	0x5f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x45, 0x4c, 0x52, 0x75,
+	0x6c, 0x65, 0x52, 0x08, 0x63, 0x65, 0x6c, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x42, 0x0f, 0x0a, 0x0d,
+	0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x13, 0x0a,
+	0x11, 0x5f, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x74, 0x69,
+	0x6d, 0x65, 0x22, 0x3d, 0x0a, 0x07, 0x43, 0x45, 0x4c, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x22, 0xa7, 0x01, 0x0a, 0x0c, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x75,
+	0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1b,
+	0x0a, 0x09, 0x64, 0x6e, 0x73, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x08, 0x64, 0x6e, 0x73, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x72, 0x69, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x75, 0x72, 0x69, 0x73, 0x12,
+	0x27, 0x0a, 0x0f, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x6d,
+	0x6f, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b,
+	0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x22, 0x81, 0x01, 0x0a, 0x0b,
+	0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x2f, 0x0a, 0x05, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x25, 0x0a, 0x0e,
+	0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x22,
+	0xa2, 0x01, 0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x49, 0x6e, 0x4d, 0x65, 0x6d, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2d, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x07, 0x6b, 0x65, 0x79, 0x53, 0x69, 0x7a,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x02, 0x63, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x41, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x01, 0x52, 0x02, 0x63, 0x61, 0x88, 0x01, 0x01,
+	0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x05, 0x0a,
+	0x03, 0x5f, 0x63, 0x61, 0x22, 0xd3, 0x01, 0x0a, 0x09, 0x43, 0x41, 0x53, 0x75, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x7a, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x72, 0x67, 0x61, 0x6e,
+	0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2f, 0x0a, 0x13, 0x6f, 0x72, 0x67, 0x61, 0x6e,
+	0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x12, 0x6f, 0x72, 0x67, 0x61, 0x6e, 0x69, 0x7a, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x61, 0x6c, 0x55, 0x6e, 0x69, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x72, 0x79, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x6e, 0x63, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x22, 0xea, 0x03, 0x0a, 0x08, 0x43,
+	0x41, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x35, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65,
+	0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d,
+	0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x41, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x48, 0x00, 0x52, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x88, 0x01, 0x01, 0x12, 0x3a,
+	0x0a, 0x08, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x01, 0x52, 0x08, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x69, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0c, 0x6d, 0x61,
+	0x78, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x6c, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x02, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x50, 0x61, 0x74, 0x68, 0x4c, 0x65, 0x6e, 0x88, 0x01,
+	0x01, 0x12, 0x26, 0x0a, 0x0c, 0x74, 0x72, 0x75, 0x73, 0x74, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0b, 0x74, 0x72, 0x75, 0x73, 0x74,
+	0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x6e, 0x73,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x64, 0x6e,
+	0x73, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x70,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x3e, 0x0a, 0x0e, 0x65, 0x78, 0x74,
+	0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x75, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x0e, 0x32, 0x18, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x78, 0x74, 0x4b, 0x65, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x0c, 0x65, 0x78, 0x74,
+	0x4b, 0x65, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x4c, 0x0a, 0x10, 0x6e, 0x61, 0x6d,
+	0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74,
+	0x73, 0x48, 0x04, 0x52, 0x0f, 0x6e, 0x61, 0x6d, 0x65, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61,
+	0x69, 0x6e, 0x74, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x73, 0x75, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x69, 0x74, 0x79,
+	0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x6c, 0x65,
+	0x6e, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x74, 0x72, 0x75, 0x73, 0x74, 0x5f, 0x64, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x73,
+	0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73, 0x22, 0xbb, 0x02, 0x0a, 0x0f, 0x4e, 0x61, 0x6d, 0x65,
+	0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x70,
+	0x65, 0x72, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x5f, 0x64, 0x6e, 0x73, 0x5f, 0x64, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x13, 0x70, 0x65, 0x72, 0x6d,
+	0x69, 0x74, 0x74, 0x65, 0x64, 0x44, 0x6e, 0x73, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x12,
+	0x30, 0x0a, 0x14, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x5f, 0x64, 0x6e, 0x73, 0x5f,
+	0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x12, 0x65,
+	0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x44, 0x6e, 0x73, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x73, 0x12, 0x2e, 0x0a, 0x13, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x5f, 0x69,
+	0x70, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x11,
+	0x70, 0x65, 0x72, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x49, 0x70, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x73, 0x12, 0x2c, 0x0a, 0x12, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x5f, 0x69, 0x70,
+	0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x65,
+	0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x49, 0x70, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12,
+	0x32, 0x0a, 0x15, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x5f, 0x75, 0x72, 0x69,
+	0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x13,
+	0x70, 0x65, 0x72, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x55, 0x72, 0x69, 0x44, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x73, 0x12, 0x30, 0x0a, 0x14, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x5f,
+	0x75, 0x72, 0x69, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x12, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x55, 0x72, 0x69, 0x44, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x73, 0x22, 0xf9, 0x01, 0x0a, 0x10, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72,
+	0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2b, 0x0a, 0x12, 0x74, 0x6c,
+	0x73, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x74, 0x6c, 0x73, 0x43, 0x65, 0x72, 0x74, 0x46,
+	0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x29, 0x0a, 0x11, 0x74, 0x6c, 0x73, 0x5f, 0x6b,
+	0x65, 0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x74, 0x6c, 0x73, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x61,
+	0x74, 0x68, 0x12, 0x2d, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x1b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x1e, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x07, 0x6b, 0x65, 0x79, 0x53, 0x69, 0x7a, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x2a, 0x0a, 0x02, 0x63, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x41, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x48, 0x01, 0x52, 0x02, 0x63, 0x61, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a,
+	0x09, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x63,
+	0x61, 0x22, 0xc5, 0x03, 0x0a, 0x0f, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x48, 0x53, 0x4d, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x28, 0x0a, 0x10, 0x68, 0x73, 0x6d, 0x5f, 0x6c, 0x69, 0x62,
+	0x72, 0x61, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x68, 0x73, 0x6d, 0x4c, 0x69, 0x62, 0x72, 0x61, 0x72, 0x79, 0x50, 0x61, 0x74, 0x68, 0x12,
+	0x2d, 0x0a, 0x10, 0x68, 0x73, 0x6d, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x72,
+	0x69, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0e, 0x68, 0x73, 0x6d,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x53, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x2b,
+	0x0a, 0x0f, 0x68, 0x73, 0x6d, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0d, 0x68, 0x73, 0x6d, 0x54, 0x6f,
+	0x6b, 0x65, 0x6e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0d, 0x68,
+	0x73, 0x6d, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x70, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x02, 0x52, 0x0b, 0x68, 0x73, 0x6d, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x50, 0x69,
+	0x6e, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0d, 0x68, 0x73, 0x6d, 0x5f, 0x6b, 0x65, 0x79, 0x5f,
+	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0b, 0x68,
+	0x73, 0x6d, 0x4b, 0x65, 0x79, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65,
+	0x4b, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x08,
+	0x6b, 0x65, 0x79, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x48, 0x04,
+	0x52, 0x07, 0x6b, 0x65, 0x79, 0x53, 0x69, 0x7a, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x02,
+	0x63, 0x61, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d,
+	0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x41, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48,
+	0x05, 0x52, 0x02, 0x63, 0x61, 0x88, 0x01, 0x01, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x68, 0x73, 0x6d,
+	0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x42, 0x12, 0x0a,
+	0x10, 0x5f, 0x68, 0x73, 0x6d, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x68, 0x73, 0x6d, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f,
+	0x70, 0x69, 0x6e, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x68, 0x73, 0x6d, 0x5f, 0x6b, 0x65, 0x79, 0x5f,
+	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x63, 0x61, 0x22, 0xa6, 0x04, 0x0a, 0x15, 0x52, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x4e, 0x6f, 0x72, 0x74, 0x68, 0x66, 0x6f, 0x6f, 0x74, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12,
+	0x1b, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x05,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x07, 0x6b, 0x65, 0x79, 0x53,
+	0x69, 0x7a, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x48, 0x02, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88,
+	0x01, 0x01, 0x12, 0x2a, 0x0a, 0x02, 0x63, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x41, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x03, 0x52, 0x02, 0x63, 0x61, 0x88, 0x01, 0x01, 0x12, 0x25,
+	0x0a, 0x0c, 0x63, 0x61, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x0a, 0x63, 0x61, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x61,
+	0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x36, 0x0a, 0x15, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f,
+	0x63, 0x65, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x05, 0x52, 0x12, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x65,
+	0x72, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x34, 0x0a,
+	0x14, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65,
+	0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x48, 0x06, 0x52, 0x11, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68,
+	0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x42, 0x0b, 0x0a,
+	0x09, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x63, 0x61, 0x42, 0x0f,
+	0x0a, 0x0d, 0x5f, 0x63, 0x61, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x42,
+	0x18, 0x0a, 0x16, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x5f,
+	0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x42, 0x17, 0x0a, 0x15, 0x5f, 0x63, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61,
+	0x74, 0x68, 0x22, 0x88, 0x03, 0x0a, 0x19, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x56, 0x65, 0x72,
+	0x62, 0x61, 0x74, 0x69, 0x6d, 0x48, 0x74, 0x74, 0x70, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x12, 0x19, 0x0a, 0x08, 0x63, 0x65, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x63, 0x65, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x17, 0x0a, 0x07, 0x6b,
+	0x65, 0x79, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6b, 0x65,
+	0x79, 0x55, 0x72, 0x6c, 0x12, 0x25, 0x0a, 0x0c, 0x63, 0x61, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f,
+	0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x63, 0x61,
+	0x46, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x36, 0x0a, 0x15, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f,
+	0x70, 0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x12, 0x63, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x43, 0x65, 0x72, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68,
+	0x88, 0x01, 0x01, 0x12, 0x34, 0x0a, 0x14, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6b, 0x65,
+	0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x02, 0x52, 0x11, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x46, 0x69,
+	0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x49, 0x0a, 0x10, 0x72, 0x65, 0x66,
+	0x72, 0x65, 0x73, 0x68, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x03,
+	0x52, 0x0f, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
+	0x6c, 0x88, 0x01, 0x01, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x63, 0x61, 0x5f, 0x66, 0x69, 0x6c, 0x65,
+	0x5f, 0x70, 0x61, 0x74, 0x68, 0x42, 0x18, 0x0a, 0x16, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x5f, 0x63, 0x65, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x42,
+	0x17, 0x0a, 0x15, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x66,
+	0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x72, 0x65, 0x66,
+	0x72, 0x65, 0x73, 0x68, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x22, 0xf4, 0x01,
+	0x0a, 0x12, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x12, 0x38, 0x0a, 0x0b, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x42,
+	0x0a, 0x09, 0x6e, 0x6f, 0x72, 0x74, 0x68, 0x66, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x22, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4e, 0x6f, 0x72, 0x74, 0x68, 0x66, 0x6f, 0x6f, 0x74, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x00, 0x52, 0x09, 0x6e, 0x6f, 0x72, 0x74, 0x68, 0x66, 0x6f,
+	0x6f, 0x74, 0x12, 0x4f, 0x0a, 0x0e, 0x76, 0x65, 0x72, 0x62, 0x61, 0x74, 0x69, 0x6d, 0x5f, 0x68,
+	0x74, 0x74, 0x70, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x56,
+	0x65, 0x72, 0x62, 0x61, 0x74, 0x69, 0x6d, 0x48, 0x74, 0x74, 0x70, 0x73, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x48, 0x00, 0x52, 0x0d, 0x76, 0x65, 0x72, 0x62, 0x61, 0x74, 0x69, 0x6d, 0x48, 0x74,
+	0x74, 0x70, 0x73, 0x42, 0x0f, 0x0a, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x22, 0x22, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x65,
+	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x40, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x53,
+	0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a,
+	0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e,
+	0x65, 0x72, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x22, 0x3b, 0x0a, 0x0a, 0x53, 0x69,
+	0x67, 0x6e, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x07, 0x73, 0x69, 0x67, 0x6e,
+	0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x07,
+	0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x73, 0x22, 0x48, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x53,
+	0x69, 0x67, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31,
+	0x0a, 0x07, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69,
+	0x67, 0x6e, 0x65, 0x72, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x07, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72,
+	0x73, 0x22, 0x42, 0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x65,
+	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d,
+	0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x06, 0x73,
+	0x69, 0x67, 0x6e, 0x65, 0x72, 0x22, 0xce, 0x01, 0x0a, 0x13, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a,
+	0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e,
+	0x65, 0x72, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x3b, 0x0a, 0x0b, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x52, 0x0a, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x4d, 0x61, 0x73, 0x6b, 0x12, 0x1d, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f,
+	0x6e, 0x65, 0x77, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x61,
+	0x6c, 0x6c, 0x6f, 0x77, 0x4e, 0x65, 0x77, 0x4b, 0x65, 0x79, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x5c, 0x0a, 0x14, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b,
+	0x0a, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67,
+	0x6e, 0x65, 0x72, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x6e,
+	0x65, 0x77, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6e, 0x65,
+	0x77, 0x4b, 0x65, 0x79, 0x22, 0x9e, 0x01, 0x0a, 0x13, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x53,
+	0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x38, 0x0a, 0x07,
+	0x6f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x07, 0x6f, 0x76, 0x65, 0x72,
+	0x6c, 0x61, 0x70, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x63, 0x72, 0x6f, 0x73, 0x73, 0x5f,
+	0x73, 0x69, 0x67, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x09, 0x63, 0x72,
+	0x6f, 0x73, 0x73, 0x53, 0x69, 0x67, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x6f,
+	0x76, 0x65, 0x72, 0x6c, 0x61, 0x70, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x63, 0x72, 0x6f, 0x73, 0x73,
+	0x5f, 0x73, 0x69, 0x67, 0x6e, 0x22, 0x49, 0x0a, 0x14, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x53,
+	0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a,
+	0x02, 0x63, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x63, 0x61, 0x12, 0x21, 0x0a,
+	0x0c, 0x74, 0x72, 0x75, 0x73, 0x74, 0x5f, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0c, 0x52, 0x0b, 0x74, 0x72, 0x75, 0x73, 0x74, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65,
+	0x22, 0x25, 0x0a, 0x13, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0xb9, 0x04, 0x0a, 0x0b, 0x43, 0x65, 0x72, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61,
+	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x12,
+	0x1b, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73,
+	0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x6e, 0x73, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x64, 0x6e, 0x73, 0x4e, 0x61,
+	0x6d, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x70, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x5f,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0e, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12,
+	0x12, 0x0a, 0x04, 0x75, 0x72, 0x69, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x75,
+	0x72, 0x69, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x6e, 0x6f, 0x74, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72,
+	0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x09, 0x6e, 0x6f, 0x74, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x37,
+	0x0a, 0x09, 0x6e, 0x6f, 0x74, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x6e,
+	0x6f, 0x74, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x65, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x73, 0x72, 0x5f, 0x73, 0x68, 0x61,
+	0x32, 0x35, 0x36, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x73, 0x72, 0x53, 0x68,
+	0x61, 0x32, 0x35, 0x36, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x65, 0x72, 0x74, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x04, 0x63, 0x65, 0x72, 0x74, 0x12, 0x3e, 0x0a, 0x0a, 0x72, 0x65, 0x76, 0x6f,
+	0x6b, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x48, 0x00, 0x52, 0x09, 0x72, 0x65, 0x76, 0x6f,
+	0x6b, 0x65, 0x64, 0x41, 0x74, 0x88, 0x01, 0x01, 0x12, 0x4a, 0x0a, 0x11, 0x72, 0x65, 0x76, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x0e, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x52, 0x10, 0x72, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x72, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x22, 0xeb, 0x01, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x65, 0x72, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x20, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x48, 0x00, 0x52, 0x08, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x88, 0x01,
+	0x01, 0x12, 0x48, 0x0a, 0x0f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x62, 0x65,
+	0x66, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x48, 0x01, 0x52, 0x0e, 0x65, 0x78, 0x70, 0x69, 0x72, 0x69,
+	0x6e, 0x67, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x73,
+	0x61, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x03, 0x73, 0x61, 0x6e, 0x88,
+	0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x48, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a,
+	0x0a, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x42, 0x12, 0x0a, 0x10, 0x5f,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x42,
+	0x06, 0x0a, 0x04, 0x5f, 0x73, 0x61, 0x6e, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x22, 0x58, 0x0a, 0x18, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a,
+	0x0c, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x0c, 0x63,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x22, 0x2f, 0x0a, 0x15, 0x47,
+	0x65, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x22, 0x54, 0x0a, 0x16,
+	0x47, 0x65, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x22, 0x69, 0x0a, 0x18, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x43, 0x65, 0x72, 0x74,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x12, 0x35, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x6f, 0x0a,
+	0x0b, 0x52, 0x6f, 0x6c, 0x65, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x1a, 0x0a, 0x08,
+	0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x25, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12,
+	0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x03, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x73, 0x22, 0x59,
+	0x0a, 0x18, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x6f, 0x6c, 0x65, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x0d, 0x72, 0x6f,
+	0x6c, 0x65, 0x5f, 0x62, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x6f, 0x6c, 0x65, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x0c, 0x72, 0x6f, 0x6c,
+	0x65, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x57, 0x0a, 0x18, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x52, 0x6f, 0x6c, 0x65, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3b, 0x0a, 0x0c, 0x72, 0x6f, 0x6c, 0x65, 0x5f, 0x62, 0x69,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x42, 0x69,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x0b, 0x72, 0x6f, 0x6c, 0x65, 0x42, 0x69, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x22, 0x5d, 0x0a, 0x18, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x6f, 0x6c, 0x65,
+	0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x25, 0x0a, 0x04, 0x72, 0x6f,
+	0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d,
+	0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x04, 0x72, 0x6f, 0x6c,
+	0x65, 0x2a, 0x83, 0x01, 0x0a, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x1b, 0x0a, 0x17, 0x53, 0x49, 0x47, 0x4e, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x15, 0x0a,
+	0x11, 0x53, 0x49, 0x47, 0x4e, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x49, 0x4e, 0x4d,
+	0x45, 0x4d, 0x10, 0x01, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x49, 0x47, 0x4e, 0x45, 0x52, 0x5f, 0x54,
+	0x59, 0x50, 0x45, 0x5f, 0x46, 0x49, 0x4c, 0x45, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x49,
+	0x47, 0x4e, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x48, 0x53, 0x4d, 0x10, 0x03, 0x12,
+	0x16, 0x0a, 0x12, 0x53, 0x49, 0x47, 0x4e, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52,
+	0x45, 0x4d, 0x4f, 0x54, 0x45, 0x10, 0x04, 0x2a, 0xf7, 0x01, 0x0a, 0x08, 0x4b, 0x65, 0x79, 0x55,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x19, 0x0a, 0x15, 0x4b, 0x45, 0x59, 0x5f, 0x55, 0x53, 0x41, 0x47,
+	0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x1f, 0x0a, 0x1b, 0x4b, 0x45, 0x59, 0x5f, 0x55, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x44, 0x49, 0x47,
+	0x49, 0x54, 0x41, 0x4c, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x10, 0x01,
+	0x12, 0x20, 0x0a, 0x1c, 0x4b, 0x45, 0x59, 0x5f, 0x55, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x43, 0x4f,
+	0x4e, 0x54, 0x45, 0x4e, 0x54, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x49, 0x54, 0x4d, 0x45, 0x4e, 0x54,
+	0x10, 0x02, 0x12, 0x1e, 0x0a, 0x1a, 0x4b, 0x45, 0x59, 0x5f, 0x55, 0x53, 0x41, 0x47, 0x45, 0x5f,
+	0x4b, 0x45, 0x59, 0x5f, 0x45, 0x4e, 0x43, 0x49, 0x50, 0x48, 0x45, 0x52, 0x4d, 0x45, 0x4e, 0x54,
+	0x10, 0x03, 0x12, 0x1f, 0x0a, 0x1b, 0x4b, 0x45, 0x59, 0x5f, 0x55, 0x53, 0x41, 0x47, 0x45, 0x5f,
+	0x44, 0x41, 0x54, 0x41, 0x5f, 0x45, 0x4e, 0x43, 0x49, 0x50, 0x48, 0x45, 0x52, 0x4d, 0x45, 0x4e,
+	0x54, 0x10, 0x04, 0x12, 0x1b, 0x0a, 0x17, 0x4b, 0x45, 0x59, 0x5f, 0x55, 0x53, 0x41, 0x47, 0x45,
+	0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x41, 0x47, 0x52, 0x45, 0x45, 0x4d, 0x45, 0x4e, 0x54, 0x10, 0x05,
+	0x12, 0x17, 0x0a, 0x13, 0x4b, 0x45, 0x59, 0x5f, 0x55, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x43, 0x45,
+	0x52, 0x54, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x10, 0x06, 0x12, 0x16, 0x0a, 0x12, 0x4b, 0x45, 0x59,
+	0x5f, 0x55, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x43, 0x52, 0x4c, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x10,
+	0x07, 0x2a, 0xef, 0x01, 0x0a, 0x0b, 0x45, 0x78, 0x74, 0x4b, 0x65, 0x79, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x1d, 0x0a, 0x19, 0x45, 0x58, 0x54, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x55, 0x53, 0x41,
+	0x47, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00,
+	0x12, 0x1d, 0x0a, 0x19, 0x45, 0x58, 0x54, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x55, 0x53, 0x41, 0x47,
+	0x45, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x45, 0x52, 0x5f, 0x41, 0x55, 0x54, 0x48, 0x10, 0x01, 0x12,
+	0x1d, 0x0a, 0x19, 0x45, 0x58, 0x54, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x55, 0x53, 0x41, 0x47, 0x45,
+	0x5f, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x41, 0x55, 0x54, 0x48, 0x10, 0x02, 0x12, 0x1e,
+	0x0a, 0x1a, 0x45, 0x58, 0x54, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x55, 0x53, 0x41, 0x47, 0x45, 0x5f,
+	0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x03, 0x12, 0x22,
+	0x0a, 0x1e, 0x45, 0x58, 0x54, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x55, 0x53, 0x41, 0x47, 0x45, 0x5f,
+	0x45, 0x4d, 0x41, 0x49, 0x4c, 0x5f, 0x50, 0x52, 0x4f, 0x54, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e,
+	0x10, 0x04, 0x12, 0x1f, 0x0a, 0x1b, 0x45, 0x58, 0x54, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x55, 0x53,
+	0x41, 0x47, 0x45, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x4d, 0x50, 0x49, 0x4e,
+	0x47, 0x10, 0x05, 0x12, 0x1e, 0x0a, 0x1a, 0x45, 0x58, 0x54, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x55,
+	0x53, 0x41, 0x47, 0x45, 0x5f, 0x4f, 0x43, 0x53, 0x50, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x49, 0x4e,
+	0x47, 0x10, 0x06, 0x2a, 0x87, 0x02, 0x0a, 0x0c, 0x53, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x12, 0x1d, 0x0a, 0x19, 0x53, 0x55, 0x42, 0x4a, 0x45, 0x43, 0x54, 0x5f,
+	0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19, 0x53, 0x55, 0x42, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x46,
+	0x49, 0x45, 0x4c, 0x44, 0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x4f, 0x4e, 0x5f, 0x4e, 0x41, 0x4d, 0x45,
+	0x10, 0x01, 0x12, 0x1e, 0x0a, 0x1a, 0x53, 0x55, 0x42, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x46, 0x49,
+	0x45, 0x4c, 0x44, 0x5f, 0x4f, 0x52, 0x47, 0x41, 0x4e, 0x49, 0x5a, 0x41, 0x54, 0x49, 0x4f, 0x4e,
+	0x10, 0x02, 0x12, 0x25, 0x0a, 0x21, 0x53, 0x55, 0x42, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x46, 0x49,
+	0x45, 0x4c, 0x44, 0x5f, 0x4f, 0x52, 0x47, 0x41, 0x4e, 0x49, 0x5a, 0x41, 0x54, 0x49, 0x4f, 0x4e,
+	0x41, 0x4c, 0x5f, 0x55, 0x4e, 0x49, 0x54, 0x10, 0x03, 0x12, 0x19, 0x0a, 0x15, 0x53, 0x55, 0x42,
+	0x4a, 0x45, 0x43, 0x54, 0x5f, 0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x43, 0x4f, 0x55, 0x4e, 0x54,
+	0x52, 0x59, 0x10, 0x04, 0x12, 0x1a, 0x0a, 0x16, 0x53, 0x55, 0x42, 0x4a, 0x45, 0x43, 0x54, 0x5f,
+	0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x50, 0x52, 0x4f, 0x56, 0x49, 0x4e, 0x43, 0x45, 0x10, 0x05,
+	0x12, 0x1a, 0x0a, 0x16, 0x53, 0x55, 0x42, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x46, 0x49, 0x45, 0x4c,
+	0x44, 0x5f, 0x4c, 0x4f, 0x43, 0x41, 0x4c, 0x49, 0x54, 0x59, 0x10, 0x06, 0x12, 0x1f, 0x0a, 0x1b,
+	0x53, 0x55, 0x42, 0x4a, 0x45, 0x43, 0x54, 0x5f, 0x46, 0x49, 0x45, 0x4c, 0x44, 0x5f, 0x53, 0x45,
+	0x52, 0x49, 0x41, 0x4c, 0x5f, 0x4e, 0x55, 0x4d, 0x42, 0x45, 0x52, 0x10, 0x07, 0x2a, 0x83, 0x01,
+	0x0a, 0x0e, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x20, 0x0a, 0x1c, 0x50, 0x52, 0x49, 0x56, 0x41, 0x54, 0x45, 0x5f, 0x4b, 0x45, 0x59, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44,
+	0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x52, 0x49, 0x56, 0x41, 0x54, 0x45, 0x5f, 0x4b, 0x45,
+	0x59, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x52, 0x53, 0x41, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13,
+	0x50, 0x52, 0x49, 0x56, 0x41, 0x54, 0x45, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x45, 0x43, 0x10, 0x02, 0x12, 0x1c, 0x0a, 0x18, 0x50, 0x52, 0x49, 0x56, 0x41, 0x54, 0x45,
+	0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x45, 0x44, 0x32, 0x35, 0x35, 0x31,
+	0x39, 0x10, 0x03, 0x2a, 0x64, 0x0a, 0x0a, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x1b, 0x0a, 0x17, 0x52, 0x45, 0x4d, 0x4f, 0x54, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x19,
+	0x0a, 0x15, 0x52, 0x45, 0x4d, 0x4f, 0x54, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4e, 0x4f,
+	0x52, 0x54, 0x48, 0x46, 0x4f, 0x4f, 0x54, 0x10, 0x01, 0x12, 0x1e, 0x0a, 0x1a, 0x52, 0x45, 0x4d,
+	0x4f, 0x54, 0x45, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x56, 0x45, 0x52, 0x42, 0x41, 0x54, 0x49,
+	0x4d, 0x5f, 0x48, 0x54, 0x54, 0x50, 0x53, 0x10, 0x02, 0x2a, 0xf3, 0x02, 0x0a, 0x10, 0x52, 0x65,
+	0x76, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x21,
+	0x0a, 0x1d, 0x52, 0x45, 0x56, 0x4f, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x41,
+	0x53, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x00, 0x12, 0x24, 0x0a, 0x20, 0x52, 0x45, 0x56, 0x4f, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x52, 0x45, 0x41, 0x53, 0x4f, 0x4e, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x52,
+	0x4f, 0x4d, 0x49, 0x53, 0x45, 0x10, 0x01, 0x12, 0x23, 0x0a, 0x1f, 0x52, 0x45, 0x56, 0x4f, 0x43,
+	0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x41, 0x53, 0x4f, 0x4e, 0x5f, 0x43, 0x41, 0x5f,
+	0x43, 0x4f, 0x4d, 0x50, 0x52, 0x4f, 0x4d, 0x49, 0x53, 0x45, 0x10, 0x02, 0x12, 0x29, 0x0a, 0x25,
+	0x52, 0x45, 0x56, 0x4f, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x41, 0x53, 0x4f,
+	0x4e, 0x5f, 0x41, 0x46, 0x46, 0x49, 0x4c, 0x49, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x48,
+	0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x03, 0x12, 0x20, 0x0a, 0x1c, 0x52, 0x45, 0x56, 0x4f, 0x43,
+	0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x41, 0x53, 0x4f, 0x4e, 0x5f, 0x53, 0x55, 0x50,
+	0x45, 0x52, 0x53, 0x45, 0x44, 0x45, 0x44, 0x10, 0x04, 0x12, 0x2c, 0x0a, 0x28, 0x52, 0x45, 0x56,
+	0x4f, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x41, 0x53, 0x4f, 0x4e, 0x5f, 0x43,
+	0x45, 0x53, 0x53, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4f, 0x46, 0x5f, 0x4f, 0x50, 0x45, 0x52,
+	0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x05, 0x12, 0x26, 0x0a, 0x22, 0x52, 0x45, 0x56, 0x4f, 0x43,
+	0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x41, 0x53, 0x4f, 0x4e, 0x5f, 0x43, 0x45, 0x52,
+	0x54, 0x49, 0x46, 0x49, 0x43, 0x41, 0x54, 0x45, 0x5f, 0x48, 0x4f, 0x4c, 0x44, 0x10, 0x06, 0x12,
+	0x29, 0x0a, 0x25, 0x52, 0x45, 0x56, 0x4f, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45,
+	0x41, 0x53, 0x4f, 0x4e, 0x5f, 0x50, 0x52, 0x49, 0x56, 0x49, 0x4c, 0x45, 0x47, 0x45, 0x5f, 0x57,
+	0x49, 0x54, 0x48, 0x44, 0x52, 0x41, 0x57, 0x4e, 0x10, 0x09, 0x12, 0x23, 0x0a, 0x1f, 0x52, 0x45,
+	0x56, 0x4f, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x41, 0x53, 0x4f, 0x4e, 0x5f,
+	0x41, 0x41, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x52, 0x4f, 0x4d, 0x49, 0x53, 0x45, 0x10, 0x0a, 0x2a,
+	0x62, 0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x10, 0x52, 0x4f, 0x4c, 0x45, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0e, 0x0a,
+	0x0a, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x41, 0x44, 0x4d, 0x49, 0x4e, 0x10, 0x01, 0x12, 0x11, 0x0a,
+	0x0d, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x10, 0x02,
+	0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x49, 0x53, 0x53, 0x55, 0x45, 0x52, 0x10,
+	0x03, 0x12, 0x10, 0x0a, 0x0c, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x41, 0x55, 0x44, 0x49, 0x54, 0x4f,
+	0x52, 0x10, 0x04, 0x32, 0xf1, 0x07, 0x0a, 0x11, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4a, 0x0a, 0x09, 0x47, 0x65, 0x74,
+	0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x69, 0x67,
+	0x6e, 0x65, 0x72, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x20, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53,
+	0x69, 0x67, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48,
+	0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x20,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x53, 0x0a, 0x0c, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x20, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d,
+	0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x69, 0x67,
+	0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53,
+	0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x53, 0x0a,
+	0x0c, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x20, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x74, 0x61,
+	0x74, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x21, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f,
+	0x74, 0x61, 0x74, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x48, 0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x69, 0x67, 0x6e,
+	0x65, 0x72, 0x12, 0x20, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x5f, 0x0a, 0x10,
+	0x4c, 0x69, 0x73, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73,
+	0x12, 0x24, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x59, 0x0a,
+	0x0e, 0x47, 0x65, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12,
+	0x22, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x52, 0x0a, 0x11, 0x52, 0x65, 0x76, 0x6f,
+	0x6b, 0x65, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x25, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x76, 0x6f,
+	0x6b, 0x65, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x51, 0x0a, 0x10,
+	0x4c, 0x69, 0x73, 0x74, 0x52, 0x6f, 0x6c, 0x65, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73,
+	0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x25, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d,
+	0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x6f, 0x6c, 0x65, 0x42,
+	0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x52, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6c, 0x65, 0x42, 0x69, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x12, 0x25, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d, 0x67, 0x6d, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6c, 0x65, 0x42, 0x69, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x12, 0x52, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x6f, 0x6c,
+	0x65, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x25, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x6d,
+	0x67, 0x6d, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x6f, 0x6c,
+	0x65, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x61, 0x6b, 0x65, 0x78, 0x6b, 0x73, 0x2f, 0x6e, 0x6f,
+	0x72, 0x74, 0x68, 0x66, 0x6f, 0x6f, 0x74, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x6d, 0x67, 0x6d, 0x74,
+	0x2f, 0x76, 0x31, 0x3b, 0x6d, 0x67, 0x6d, 0x74, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_api_mgmt_v1_mgmt_proto_rawDescOnce sync.Once
+	file_api_mgmt_v1_mgmt_proto_rawDescData = file_api_mgmt_v1_mgmt_proto_rawDesc
+)
+
+func file_api_mgmt_v1_mgmt_proto_rawDescGZIP() []byte {
+	file_api_mgmt_v1_mgmt_proto_rawDescOnce.Do(func() {
+		file_api_mgmt_v1_mgmt_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_mgmt_v1_mgmt_proto_rawDescData)
+	})
+	return file_api_mgmt_v1_mgmt_proto_rawDescData
+}
+
+var file_api_mgmt_v1_mgmt_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_api_mgmt_v1_mgmt_proto_msgTypes = make([]protoimpl.MessageInfo, 37)
+var file_api_mgmt_v1_mgmt_proto_goTypes = []interface{}{
+	(SignerType)(0),                   // 0: api.mgmt.v1.SignerType
+	(KeyUsage)(0),                     // 1: api.mgmt.v1.KeyUsage
+	(ExtKeyUsage)(0),                  // 2: api.mgmt.v1.ExtKeyUsage
+	(SubjectField)(0),                 // 3: api.mgmt.v1.SubjectField
+	(PrivateKeyType)(0),               // 4: api.mgmt.v1.PrivateKeyType
+	(RemoteType)(0),                   // 5: api.mgmt.v1.RemoteType
+	(RevocationReason)(0),             // 6: api.mgmt.v1.RevocationReason
+	(Role)(0),                         // 7: api.mgmt.v1.Role
+	(*Signer)(nil),                    // 8: api.mgmt.v1.Signer
+	(*RotationConfig)(nil),            // 9: api.mgmt.v1.RotationConfig
+	(*Extension)(nil),                 // 10: api.mgmt.v1.Extension
+	(*Profile)(nil),                   // 11: api.mgmt.v1.Profile
+	(*IssuancePolicy)(nil),            // 12: api.mgmt.v1.IssuancePolicy
+	(*CELRule)(nil),                   // 13: api.mgmt.v1.CELRule
+	(*IdentityRule)(nil),              // 14: api.mgmt.v1.IdentityRule
+	(*SubjectRule)(nil),               // 15: api.mgmt.v1.SubjectRule
+	(*SignerInMemConfig)(nil),         // 16: api.mgmt.v1.SignerInMemConfig
+	(*CASubject)(nil),                 // 17: api.mgmt.v1.CASubject
+	(*CAConfig)(nil),                  // 18: api.mgmt.v1.CAConfig
+	(*NameConstraints)(nil),           // 19: api.mgmt.v1.NameConstraints
+	(*SignerFileConfig)(nil),          // 20: api.mgmt.v1.SignerFileConfig
+	(*SignerHSMConfig)(nil),           // 21: api.mgmt.v1.SignerHSMConfig
+	(*RemoteNorthfootConfig)(nil),     // 22: api.mgmt.v1.RemoteNorthfootConfig
+	(*RemoteVerbatimHttpsConfig)(nil), // 23: api.mgmt.v1.RemoteVerbatimHttpsConfig
+	(*SignerRemoteConfig)(nil),        // 24: api.mgmt.v1.SignerRemoteConfig
+	(*GetSignerRequest)(nil),          // 25: api.mgmt.v1.GetSignerRequest
+	(*GetSignerResponse)(nil),         // 26: api.mgmt.v1.GetSignerResponse
+	(*SignerList)(nil),                // 27: api.mgmt.v1.SignerList
+	(*ListSignersResponse)(nil),       // 28: api.mgmt.v1.ListSignersResponse
+	(*CreateSignerRequest)(nil),       // 29: api.mgmt.v1.CreateSignerRequest
+	(*UpdateSignerRequest)(nil),       // 30: api.mgmt.v1.UpdateSignerRequest
+	(*UpdateSignerResponse)(nil),      // 31: api.mgmt.v1.UpdateSignerResponse
+	(*RotateSignerRequest)(nil),       // 32: api.mgmt.v1.RotateSignerRequest
+	(*RotateSignerResponse)(nil),      // 33: api.mgmt.v1.RotateSignerResponse
+	(*DeleteSignerRequest)(nil),       // 34: api.mgmt.v1.DeleteSignerRequest
+	(*Certificate)(nil),               // 35: api.mgmt.v1.Certificate
+	(*ListCertificatesRequest)(nil),   // 36: api.mgmt.v1.ListCertificatesRequest
+	(*ListCertificatesResponse)(nil),  // 37: api.mgmt.v1.ListCertificatesResponse
+	(*GetCertificateRequest)(nil),     // 38: api.mgmt.v1.GetCertificateRequest
+	(*GetCertificateResponse)(nil),    // 39: api.mgmt.v1.GetCertificateResponse
+	(*RevokeCertificateRequest)(nil),  // 40: api.mgmt.v1.RevokeCertificateRequest
+	(*RoleBinding)(nil),               // 41: api.mgmt.v1.RoleBinding
+	(*ListRoleBindingsResponse)(nil),  // 42: api.mgmt.v1.ListRoleBindingsResponse
+	(*CreateRoleBindingRequest)(nil),  // 43: api.mgmt.v1.CreateRoleBindingRequest
+	(*DeleteRoleBindingRequest)(nil),  // 44: api.mgmt.v1.DeleteRoleBindingRequest
+	(*durationpb.Duration)(nil),       // 45: google.protobuf.Duration
+	(*fieldmaskpb.FieldMask)(nil),     // 46: google.protobuf.FieldMask
+	(*timestamppb.Timestamp)(nil),     // 47: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),             // 48: google.protobuf.Empty
+}
+var file_api_mgmt_v1_mgmt_proto_depIdxs = []int32{
+	0,  // 0: api.mgmt.v1.Signer.type:type_name -> api.mgmt.v1.SignerType
+	16, // 1: api.mgmt.v1.Signer.in_mem:type_name -> api.mgmt.v1.SignerInMemConfig
+	20, // 2: api.mgmt.v1.Signer.file:type_name -> api.mgmt.v1.SignerFileConfig
+	21, // 3: api.mgmt.v1.Signer.hsm:type_name -> api.mgmt.v1.SignerHSMConfig
+	24, // 4: api.mgmt.v1.Signer.remote:type_name -> api.mgmt.v1.SignerRemoteConfig
+	12, // 5: api.mgmt.v1.Signer.policy:type_name -> api.mgmt.v1.IssuancePolicy
+	11, // 6: api.mgmt.v1.Signer.profiles:type_name -> api.mgmt.v1.Profile
+	9,  // 7: api.mgmt.v1.Signer.rotation:type_name -> api.mgmt.v1.RotationConfig
+	45, // 8: api.mgmt.v1.RotationConfig.interval:type_name -> google.protobuf.Duration
+	45, // 9: api.mgmt.v1.RotationConfig.overlap:type_name -> google.protobuf.Duration
+	1,  // 10: api.mgmt.v1.Profile.key_usages:type_name -> api.mgmt.v1.KeyUsage
+	2,  // 11: api.mgmt.v1.Profile.ext_key_usages:type_name -> api.mgmt.v1.ExtKeyUsage
+	10, // 12: api.mgmt.v1.Profile.extensions:type_name -> api.mgmt.v1.Extension
+	15, // 13: api.mgmt.v1.IssuancePolicy.subject_rules:type_name -> api.mgmt.v1.SubjectRule
+	45, // 14: api.mgmt.v1.IssuancePolicy.max_lifetime:type_name -> google.protobuf.Duration
+	45, // 15: api.mgmt.v1.IssuancePolicy.default_lifetime:type_name -> google.protobuf.Duration
+	14, // 16: api.mgmt.v1.IssuancePolicy.identity_rules:type_name -> api.mgmt.v1.IdentityRule
+	13, // 17: api.mgmt.v1.IssuancePolicy.cel_rules:type_name -> api.mgmt.v1.CELRule
+	3,  // 18: api.mgmt.v1.SubjectRule.field:type_name -> api.mgmt.v1.SubjectField
+	4,  // 19: api.mgmt.v1.SignerInMemConfig.key:type_name -> api.mgmt.v1.PrivateKeyType
+	18, // 20: api.mgmt.v1.SignerInMemConfig.ca:type_name -> api.mgmt.v1.CAConfig
+	17, // 21: api.mgmt.v1.CAConfig.subject:type_name -> api.mgmt.v1.CASubject
+	45, // 22: api.mgmt.v1.CAConfig.validity:type_name -> google.protobuf.Duration
+	2,  // 23: api.mgmt.v1.CAConfig.ext_key_usages:type_name -> api.mgmt.v1.ExtKeyUsage
+	19, // 24: api.mgmt.v1.CAConfig.name_constraints:type_name -> api.mgmt.v1.NameConstraints
+	4,  // 25: api.mgmt.v1.SignerFileConfig.key:type_name -> api.mgmt.v1.PrivateKeyType
+	18, // 26: api.mgmt.v1.SignerFileConfig.ca:type_name -> api.mgmt.v1.CAConfig
+	4,  // 27: api.mgmt.v1.SignerHSMConfig.key:type_name -> api.mgmt.v1.PrivateKeyType
+	18, // 28: api.mgmt.v1.SignerHSMConfig.ca:type_name -> api.mgmt.v1.CAConfig
+	4,  // 29: api.mgmt.v1.RemoteNorthfootConfig.key:type_name -> api.mgmt.v1.PrivateKeyType
+	45, // 30: api.mgmt.v1.RemoteNorthfootConfig.duration:type_name -> google.protobuf.Duration
+	18, // 31: api.mgmt.v1.RemoteNorthfootConfig.ca:type_name -> api.mgmt.v1.CAConfig
+	45, // 32: api.mgmt.v1.RemoteVerbatimHttpsConfig.refresh_interval:type_name -> google.protobuf.Duration
+	5,  // 33: api.mgmt.v1.SignerRemoteConfig.remote_type:type_name -> api.mgmt.v1.RemoteType
+	22, // 34: api.mgmt.v1.SignerRemoteConfig.northfoot:type_name -> api.mgmt.v1.RemoteNorthfootConfig
+	23, // 35: api.mgmt.v1.SignerRemoteConfig.verbatim_https:type_name -> api.mgmt.v1.RemoteVerbatimHttpsConfig
+	8,  // 36: api.mgmt.v1.GetSignerResponse.signer:type_name -> api.mgmt.v1.Signer
+	8,  // 37: api.mgmt.v1.SignerList.signers:type_name -> api.mgmt.v1.Signer
+	27, // 38: api.mgmt.v1.ListSignersResponse.signers:type_name -> api.mgmt.v1.SignerList
+	8,  // 39: api.mgmt.v1.CreateSignerRequest.signer:type_name -> api.mgmt.v1.Signer
+	8,  // 40: api.mgmt.v1.UpdateSignerRequest.signer:type_name -> api.mgmt.v1.Signer
+	46, // 41: api.mgmt.v1.UpdateSignerRequest.update_mask:type_name -> google.protobuf.FieldMask
+	8,  // 42: api.mgmt.v1.UpdateSignerResponse.signer:type_name -> api.mgmt.v1.Signer
+	45, // 43: api.mgmt.v1.RotateSignerRequest.overlap:type_name -> google.protobuf.Duration
+	47, // 44: api.mgmt.v1.Certificate.not_before:type_name -> google.protobuf.Timestamp
+	47, // 45: api.mgmt.v1.Certificate.not_after:type_name -> google.protobuf.Timestamp
+	47, // 46: api.mgmt.v1.Certificate.revoked_at:type_name -> google.protobuf.Timestamp
+	6,  // 47: api.mgmt.v1.Certificate.revocation_reason:type_name -> api.mgmt.v1.RevocationReason
+	47, // 48: api.mgmt.v1.ListCertificatesRequest.expiring_before:type_name -> google.protobuf.Timestamp
+	35, // 49: api.mgmt.v1.ListCertificatesResponse.certificates:type_name -> api.mgmt.v1.Certificate
+	35, // 50: api.mgmt.v1.GetCertificateResponse.certificate:type_name -> api.mgmt.v1.Certificate
+	6,  // 51: api.mgmt.v1.RevokeCertificateRequest.reason:type_name -> api.mgmt.v1.RevocationReason
+	7,  // 52: api.mgmt.v1.RoleBinding.role:type_name -> api.mgmt.v1.Role
+	41, // 53: api.mgmt.v1.ListRoleBindingsResponse.role_bindings:type_name -> api.mgmt.v1.RoleBinding
+	41, // 54: api.mgmt.v1.CreateRoleBindingRequest.role_binding:type_name -> api.mgmt.v1.RoleBinding
+	7,  // 55: api.mgmt.v1.DeleteRoleBindingRequest.role:type_name -> api.mgmt.v1.Role
+	25, // 56: api.mgmt.v1.ManagementService.GetSigner:input_type -> api.mgmt.v1.GetSignerRequest
+	48, // 57: api.mgmt.v1.ManagementService.ListSigners:input_type -> google.protobuf.Empty
+	29, // 58: api.mgmt.v1.ManagementService.CreateSigner:input_type -> api.mgmt.v1.CreateSignerRequest
+	30, // 59: api.mgmt.v1.ManagementService.UpdateSigner:input_type -> api.mgmt.v1.UpdateSignerRequest
+	32, // 60: api.mgmt.v1.ManagementService.RotateSigner:input_type -> api.mgmt.v1.RotateSignerRequest
+	34, // 61: api.mgmt.v1.ManagementService.DeleteSigner:input_type -> api.mgmt.v1.DeleteSignerRequest
+	36, // 62: api.mgmt.v1.ManagementService.ListCertificates:input_type -> api.mgmt.v1.ListCertificatesRequest
+	38, // 63: api.mgmt.v1.ManagementService.GetCertificate:input_type -> api.mgmt.v1.GetCertificateRequest
+	40, // 64: api.mgmt.v1.ManagementService.RevokeCertificate:input_type -> api.mgmt.v1.RevokeCertificateRequest
+	48, // 65: api.mgmt.v1.ManagementService.ListRoleBindings:input_type -> google.protobuf.Empty
+	43, // 66: api.mgmt.v1.ManagementService.CreateRoleBinding:input_type -> api.mgmt.v1.CreateRoleBindingRequest
+	44, // 67: api.mgmt.v1.ManagementService.DeleteRoleBinding:input_type -> api.mgmt.v1.DeleteRoleBindingRequest
+	26, // 68: api.mgmt.v1.ManagementService.GetSigner:output_type -> api.mgmt.v1.GetSignerResponse
+	28, // 69: api.mgmt.v1.ManagementService.ListSigners:output_type -> api.mgmt.v1.ListSignersResponse
+	48, // 70: api.mgmt.v1.ManagementService.CreateSigner:output_type -> google.protobuf.Empty
+	31, // 71: api.mgmt.v1.ManagementService.UpdateSigner:output_type -> api.mgmt.v1.UpdateSignerResponse
+	33, // 72: api.mgmt.v1.ManagementService.RotateSigner:output_type -> api.mgmt.v1.RotateSignerResponse
+	48, // 73: api.mgmt.v1.ManagementService.DeleteSigner:output_type -> google.protobuf.Empty
+	37, // 74: api.mgmt.v1.ManagementService.ListCertificates:output_type -> api.mgmt.v1.ListCertificatesResponse
+	39, // 75: api.mgmt.v1.ManagementService.GetCertificate:output_type -> api.mgmt.v1.GetCertificateResponse
+	48, // 76: api.mgmt.v1.ManagementService.RevokeCertificate:output_type -> google.protobuf.Empty
+	42, // 77: api.mgmt.v1.ManagementService.ListRoleBindings:output_type -> api.mgmt.v1.ListRoleBindingsResponse
+	48, // 78: api.mgmt.v1.ManagementService.CreateRoleBinding:output_type -> google.protobuf.Empty
+	48, // 79: api.mgmt.v1.ManagementService.DeleteRoleBinding:output_type -> google.protobuf.Empty
+	68, // [68:80] is the sub-list for method output_type
+	56, // [56:68] is the sub-list for method input_type
+	56, // [56:56] is the sub-list for extension type_name
+	56, // [56:56] is the sub-list for extension extendee
+	0,  // [0:56] is the sub-list for field type_name
+}
+
+func init() { file_api_mgmt_v1_mgmt_proto_init() }
+func file_api_mgmt_v1_mgmt_proto_init() {
+	if File_api_mgmt_v1_mgmt_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_mgmt_v1_mgmt_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Signer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RotationConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Extension); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Profile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IssuancePolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CELRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IdentityRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubjectRule); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1226,7 +4379,7 @@ func file_api_mgmt_v1_mgmt_proto_init() {
 				return nil
 			}
 		}
-		file_api_mgmt_v1_mgmt_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+		file_api_mgmt_v1_mgmt_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SignerInMemConfig); i {
 			case 0:
 				return &v.state
@@ -1238,7 +4391,43 @@ func file_api_mgmt_v1_mgmt_proto_init() {
 				return nil
 			}
 		}
-		file_api_mgmt_v1_mgmt_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+		file_api_mgmt_v1_mgmt_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CASubject); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CAConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NameConstraints); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SignerFileConfig); i {
 			case 0:
 				return &v.state
@@ -1250,7 +4439,7 @@ func file_api_mgmt_v1_mgmt_proto_init() {
 				return nil
 			}
 		}
-		file_api_mgmt_v1_mgmt_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+		file_api_mgmt_v1_mgmt_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SignerHSMConfig); i {
 			case 0:
 				return &v.state
@@ -1262,7 +4451,7 @@ func file_api_mgmt_v1_mgmt_proto_init() {
 				return nil
 			}
 		}
-		file_api_mgmt_v1_mgmt_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+		file_api_mgmt_v1_mgmt_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RemoteNorthfootConfig); i {
 			case 0:
 				return &v.state
@@ -1274,7 +4463,7 @@ func file_api_mgmt_v1_mgmt_proto_init() {
 				return nil
 			}
 		}
-		file_api_mgmt_v1_mgmt_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+		file_api_mgmt_v1_mgmt_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RemoteVerbatimHttpsConfig); i {
 			case 0:
 				return &v.state
@@ -1286,7 +4475,7 @@ func file_api_mgmt_v1_mgmt_proto_init() {
 				return nil
 			}
 		}
-		file_api_mgmt_v1_mgmt_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+		file_api_mgmt_v1_mgmt_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SignerRemoteConfig); i {
 			case 0:
 				return &v.state
@@ -1298,7 +4487,7 @@ func file_api_mgmt_v1_mgmt_proto_init() {
 				return nil
 			}
 		}
-		file_api_mgmt_v1_mgmt_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+		file_api_mgmt_v1_mgmt_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetSignerRequest); i {
 			case 0:
 				return &v.state
@@ -1310,7 +4499,7 @@ func file_api_mgmt_v1_mgmt_proto_init() {
 				return nil
 			}
 		}
-		file_api_mgmt_v1_mgmt_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+		file_api_mgmt_v1_mgmt_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetSignerResponse); i {
 			case 0:
 				return &v.state
@@ -1322,7 +4511,7 @@ func file_api_mgmt_v1_mgmt_proto_init() {
 				return nil
 			}
 		}
-		file_api_mgmt_v1_mgmt_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_api_mgmt_v1_mgmt_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SignerList); i {
 			case 0:
 				return &v.state
@@ -1334,7 +4523,7 @@ func file_api_mgmt_v1_mgmt_proto_init() {
 				return nil
 			}
 		}
-		file_api_mgmt_v1_mgmt_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+		file_api_mgmt_v1_mgmt_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ListSignersResponse); i {
 			case 0:
 				return &v.state
@@ -1346,7 +4535,7 @@ func file_api_mgmt_v1_mgmt_proto_init() {
 				return nil
 			}
 		}
-		file_api_mgmt_v1_mgmt_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+		file_api_mgmt_v1_mgmt_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CreateSignerRequest); i {
 			case 0:
 				return &v.state
@@ -1358,7 +4547,55 @@ func file_api_mgmt_v1_mgmt_proto_init() {
 				return nil
 			}
 		}
-		file_api_mgmt_v1_mgmt_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+		file_api_mgmt_v1_mgmt_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateSignerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateSignerResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RotateSignerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RotateSignerResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DeleteSignerRequest); i {
 			case 0:
 				return &v.state
@@ -1370,6 +4607,126 @@ func file_api_mgmt_v1_mgmt_proto_init() {
 				return nil
 			}
 		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Certificate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListCertificatesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListCertificatesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCertificateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetCertificateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeCertificateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RoleBinding); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRoleBindingsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateRoleBindingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_mgmt_v1_mgmt_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteRoleBindingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_api_mgmt_v1_mgmt_proto_msgTypes[0].OneofWrappers = []interface{}{
 		(*Signer_InMem)(nil),
@@ -1379,17 +4736,28 @@ func file_api_mgmt_v1_mgmt_proto_init() {
 	}
 	file_api_mgmt_v1_mgmt_proto_msgTypes[1].OneofWrappers = []interface{}{}
 	file_api_mgmt_v1_mgmt_proto_msgTypes[3].OneofWrappers = []interface{}{}
-	file_api_mgmt_v1_mgmt_proto_msgTypes[6].OneofWrappers = []interface{}{
+	file_api_mgmt_v1_mgmt_proto_msgTypes[4].OneofWrappers = []interface{}{}
+	file_api_mgmt_v1_mgmt_proto_msgTypes[8].OneofWrappers = []interface{}{}
+	file_api_mgmt_v1_mgmt_proto_msgTypes[10].OneofWrappers = []interface{}{}
+	file_api_mgmt_v1_mgmt_proto_msgTypes[12].OneofWrappers = []interface{}{}
+	file_api_mgmt_v1_mgmt_proto_msgTypes[13].OneofWrappers = []interface{}{}
+	file_api_mgmt_v1_mgmt_proto_msgTypes[14].OneofWrappers = []interface{}{}
+	file_api_mgmt_v1_mgmt_proto_msgTypes[15].OneofWrappers = []interface{}{}
+	file_api_mgmt_v1_mgmt_proto_msgTypes[16].OneofWrappers = []interface{}{
 		(*SignerRemoteConfig_Northfoot)(nil),
 		(*SignerRemoteConfig_VerbatimHttps)(nil),
 	}
+	file_api_mgmt_v1_mgmt_proto_msgTypes[22].OneofWrappers = []interface{}{}
+	file_api_mgmt_v1_mgmt_proto_msgTypes[24].OneofWrappers = []interface{}{}
+	file_api_mgmt_v1_mgmt_proto_msgTypes[27].OneofWrappers = []interface{}{}
+	file_api_mgmt_v1_mgmt_proto_msgTypes[28].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_mgmt_v1_mgmt_proto_rawDesc,
-			NumEnums:      3,
-			NumMessages:   13,
+			NumEnums:      8,
+			NumMessages:   37,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
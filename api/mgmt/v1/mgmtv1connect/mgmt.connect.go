@@ -31,7 +31,15 @@ type ManagementServiceClient interface {
 	GetSigner(context.Context, *connect_go.Request[v1.GetSignerRequest]) (*connect_go.Response[v1.GetSignerResponse], error)
 	ListSigners(context.Context, *connect_go.Request[emptypb.Empty]) (*connect_go.Response[v1.ListSignersResponse], error)
 	CreateSigner(context.Context, *connect_go.Request[v1.CreateSignerRequest]) (*connect_go.Response[emptypb.Empty], error)
+	UpdateSigner(context.Context, *connect_go.Request[v1.UpdateSignerRequest]) (*connect_go.Response[v1.UpdateSignerResponse], error)
+	RotateSigner(context.Context, *connect_go.Request[v1.RotateSignerRequest]) (*connect_go.Response[v1.RotateSignerResponse], error)
 	DeleteSigner(context.Context, *connect_go.Request[v1.DeleteSignerRequest]) (*connect_go.Response[emptypb.Empty], error)
+	ListCertificates(context.Context, *connect_go.Request[v1.ListCertificatesRequest]) (*connect_go.Response[v1.ListCertificatesResponse], error)
+	GetCertificate(context.Context, *connect_go.Request[v1.GetCertificateRequest]) (*connect_go.Response[v1.GetCertificateResponse], error)
+	RevokeCertificate(context.Context, *connect_go.Request[v1.RevokeCertificateRequest]) (*connect_go.Response[emptypb.Empty], error)
+	ListRoleBindings(context.Context, *connect_go.Request[emptypb.Empty]) (*connect_go.Response[v1.ListRoleBindingsResponse], error)
+	CreateRoleBinding(context.Context, *connect_go.Request[v1.CreateRoleBindingRequest]) (*connect_go.Response[emptypb.Empty], error)
+	DeleteRoleBinding(context.Context, *connect_go.Request[v1.DeleteRoleBindingRequest]) (*connect_go.Response[emptypb.Empty], error)
 }
 
 // NewManagementServiceClient constructs a client for the api.mgmt.v1.ManagementService service. By
@@ -59,20 +67,68 @@ func NewManagementServiceClient(httpClient connect_go.HTTPClient, baseURL string
 			baseURL+"/api.mgmt.v1.ManagementService/CreateSigner",
 			opts...,
 		),
+		updateSigner: connect_go.NewClient[v1.UpdateSignerRequest, v1.UpdateSignerResponse](
+			httpClient,
+			baseURL+"/api.mgmt.v1.ManagementService/UpdateSigner",
+			opts...,
+		),
+		rotateSigner: connect_go.NewClient[v1.RotateSignerRequest, v1.RotateSignerResponse](
+			httpClient,
+			baseURL+"/api.mgmt.v1.ManagementService/RotateSigner",
+			opts...,
+		),
 		deleteSigner: connect_go.NewClient[v1.DeleteSignerRequest, emptypb.Empty](
 			httpClient,
 			baseURL+"/api.mgmt.v1.ManagementService/DeleteSigner",
 			opts...,
 		),
+		listCertificates: connect_go.NewClient[v1.ListCertificatesRequest, v1.ListCertificatesResponse](
+			httpClient,
+			baseURL+"/api.mgmt.v1.ManagementService/ListCertificates",
+			opts...,
+		),
+		getCertificate: connect_go.NewClient[v1.GetCertificateRequest, v1.GetCertificateResponse](
+			httpClient,
+			baseURL+"/api.mgmt.v1.ManagementService/GetCertificate",
+			opts...,
+		),
+		revokeCertificate: connect_go.NewClient[v1.RevokeCertificateRequest, emptypb.Empty](
+			httpClient,
+			baseURL+"/api.mgmt.v1.ManagementService/RevokeCertificate",
+			opts...,
+		),
+		listRoleBindings: connect_go.NewClient[emptypb.Empty, v1.ListRoleBindingsResponse](
+			httpClient,
+			baseURL+"/api.mgmt.v1.ManagementService/ListRoleBindings",
+			opts...,
+		),
+		createRoleBinding: connect_go.NewClient[v1.CreateRoleBindingRequest, emptypb.Empty](
+			httpClient,
+			baseURL+"/api.mgmt.v1.ManagementService/CreateRoleBinding",
+			opts...,
+		),
+		deleteRoleBinding: connect_go.NewClient[v1.DeleteRoleBindingRequest, emptypb.Empty](
+			httpClient,
+			baseURL+"/api.mgmt.v1.ManagementService/DeleteRoleBinding",
+			opts...,
+		),
 	}
 }
 
 // managementServiceClient implements ManagementServiceClient.
 type managementServiceClient struct {
-	getSigner    *connect_go.Client[v1.GetSignerRequest, v1.GetSignerResponse]
-	listSigners  *connect_go.Client[emptypb.Empty, v1.ListSignersResponse]
-	createSigner *connect_go.Client[v1.CreateSignerRequest, emptypb.Empty]
-	deleteSigner *connect_go.Client[v1.DeleteSignerRequest, emptypb.Empty]
+	getSigner         *connect_go.Client[v1.GetSignerRequest, v1.GetSignerResponse]
+	listSigners       *connect_go.Client[emptypb.Empty, v1.ListSignersResponse]
+	createSigner      *connect_go.Client[v1.CreateSignerRequest, emptypb.Empty]
+	updateSigner      *connect_go.Client[v1.UpdateSignerRequest, v1.UpdateSignerResponse]
+	rotateSigner      *connect_go.Client[v1.RotateSignerRequest, v1.RotateSignerResponse]
+	deleteSigner      *connect_go.Client[v1.DeleteSignerRequest, emptypb.Empty]
+	listCertificates  *connect_go.Client[v1.ListCertificatesRequest, v1.ListCertificatesResponse]
+	getCertificate    *connect_go.Client[v1.GetCertificateRequest, v1.GetCertificateResponse]
+	revokeCertificate *connect_go.Client[v1.RevokeCertificateRequest, emptypb.Empty]
+	listRoleBindings  *connect_go.Client[emptypb.Empty, v1.ListRoleBindingsResponse]
+	createRoleBinding *connect_go.Client[v1.CreateRoleBindingRequest, emptypb.Empty]
+	deleteRoleBinding *connect_go.Client[v1.DeleteRoleBindingRequest, emptypb.Empty]
 }
 
 // GetSigner calls api.mgmt.v1.ManagementService.GetSigner.
@@ -90,17 +146,65 @@ func (c *managementServiceClient) CreateSigner(ctx context.Context, req *connect
 	return c.createSigner.CallUnary(ctx, req)
 }
 
+// UpdateSigner calls api.mgmt.v1.ManagementService.UpdateSigner.
+func (c *managementServiceClient) UpdateSigner(ctx context.Context, req *connect_go.Request[v1.UpdateSignerRequest]) (*connect_go.Response[v1.UpdateSignerResponse], error) {
+	return c.updateSigner.CallUnary(ctx, req)
+}
+
+// RotateSigner calls api.mgmt.v1.ManagementService.RotateSigner.
+func (c *managementServiceClient) RotateSigner(ctx context.Context, req *connect_go.Request[v1.RotateSignerRequest]) (*connect_go.Response[v1.RotateSignerResponse], error) {
+	return c.rotateSigner.CallUnary(ctx, req)
+}
+
 // DeleteSigner calls api.mgmt.v1.ManagementService.DeleteSigner.
 func (c *managementServiceClient) DeleteSigner(ctx context.Context, req *connect_go.Request[v1.DeleteSignerRequest]) (*connect_go.Response[emptypb.Empty], error) {
 	return c.deleteSigner.CallUnary(ctx, req)
 }
 
+// ListCertificates calls api.mgmt.v1.ManagementService.ListCertificates.
+func (c *managementServiceClient) ListCertificates(ctx context.Context, req *connect_go.Request[v1.ListCertificatesRequest]) (*connect_go.Response[v1.ListCertificatesResponse], error) {
+	return c.listCertificates.CallUnary(ctx, req)
+}
+
+// GetCertificate calls api.mgmt.v1.ManagementService.GetCertificate.
+func (c *managementServiceClient) GetCertificate(ctx context.Context, req *connect_go.Request[v1.GetCertificateRequest]) (*connect_go.Response[v1.GetCertificateResponse], error) {
+	return c.getCertificate.CallUnary(ctx, req)
+}
+
+// RevokeCertificate calls api.mgmt.v1.ManagementService.RevokeCertificate.
+func (c *managementServiceClient) RevokeCertificate(ctx context.Context, req *connect_go.Request[v1.RevokeCertificateRequest]) (*connect_go.Response[emptypb.Empty], error) {
+	return c.revokeCertificate.CallUnary(ctx, req)
+}
+
+// ListRoleBindings calls api.mgmt.v1.ManagementService.ListRoleBindings.
+func (c *managementServiceClient) ListRoleBindings(ctx context.Context, req *connect_go.Request[emptypb.Empty]) (*connect_go.Response[v1.ListRoleBindingsResponse], error) {
+	return c.listRoleBindings.CallUnary(ctx, req)
+}
+
+// CreateRoleBinding calls api.mgmt.v1.ManagementService.CreateRoleBinding.
+func (c *managementServiceClient) CreateRoleBinding(ctx context.Context, req *connect_go.Request[v1.CreateRoleBindingRequest]) (*connect_go.Response[emptypb.Empty], error) {
+	return c.createRoleBinding.CallUnary(ctx, req)
+}
+
+// DeleteRoleBinding calls api.mgmt.v1.ManagementService.DeleteRoleBinding.
+func (c *managementServiceClient) DeleteRoleBinding(ctx context.Context, req *connect_go.Request[v1.DeleteRoleBindingRequest]) (*connect_go.Response[emptypb.Empty], error) {
+	return c.deleteRoleBinding.CallUnary(ctx, req)
+}
+
 // ManagementServiceHandler is an implementation of the api.mgmt.v1.ManagementService service.
 type ManagementServiceHandler interface {
 	GetSigner(context.Context, *connect_go.Request[v1.GetSignerRequest]) (*connect_go.Response[v1.GetSignerResponse], error)
 	ListSigners(context.Context, *connect_go.Request[emptypb.Empty]) (*connect_go.Response[v1.ListSignersResponse], error)
 	CreateSigner(context.Context, *connect_go.Request[v1.CreateSignerRequest]) (*connect_go.Response[emptypb.Empty], error)
+	UpdateSigner(context.Context, *connect_go.Request[v1.UpdateSignerRequest]) (*connect_go.Response[v1.UpdateSignerResponse], error)
+	RotateSigner(context.Context, *connect_go.Request[v1.RotateSignerRequest]) (*connect_go.Response[v1.RotateSignerResponse], error)
 	DeleteSigner(context.Context, *connect_go.Request[v1.DeleteSignerRequest]) (*connect_go.Response[emptypb.Empty], error)
+	ListCertificates(context.Context, *connect_go.Request[v1.ListCertificatesRequest]) (*connect_go.Response[v1.ListCertificatesResponse], error)
+	GetCertificate(context.Context, *connect_go.Request[v1.GetCertificateRequest]) (*connect_go.Response[v1.GetCertificateResponse], error)
+	RevokeCertificate(context.Context, *connect_go.Request[v1.RevokeCertificateRequest]) (*connect_go.Response[emptypb.Empty], error)
+	ListRoleBindings(context.Context, *connect_go.Request[emptypb.Empty]) (*connect_go.Response[v1.ListRoleBindingsResponse], error)
+	CreateRoleBinding(context.Context, *connect_go.Request[v1.CreateRoleBindingRequest]) (*connect_go.Response[emptypb.Empty], error)
+	DeleteRoleBinding(context.Context, *connect_go.Request[v1.DeleteRoleBindingRequest]) (*connect_go.Response[emptypb.Empty], error)
 }
 
 // NewManagementServiceHandler builds an HTTP handler from the service implementation. It returns
@@ -125,11 +229,51 @@ func NewManagementServiceHandler(svc ManagementServiceHandler, opts ...connect_g
 		svc.CreateSigner,
 		opts...,
 	))
+	mux.Handle("/api.mgmt.v1.ManagementService/UpdateSigner", connect_go.NewUnaryHandler(
+		"/api.mgmt.v1.ManagementService/UpdateSigner",
+		svc.UpdateSigner,
+		opts...,
+	))
+	mux.Handle("/api.mgmt.v1.ManagementService/RotateSigner", connect_go.NewUnaryHandler(
+		"/api.mgmt.v1.ManagementService/RotateSigner",
+		svc.RotateSigner,
+		opts...,
+	))
 	mux.Handle("/api.mgmt.v1.ManagementService/DeleteSigner", connect_go.NewUnaryHandler(
 		"/api.mgmt.v1.ManagementService/DeleteSigner",
 		svc.DeleteSigner,
 		opts...,
 	))
+	mux.Handle("/api.mgmt.v1.ManagementService/ListCertificates", connect_go.NewUnaryHandler(
+		"/api.mgmt.v1.ManagementService/ListCertificates",
+		svc.ListCertificates,
+		opts...,
+	))
+	mux.Handle("/api.mgmt.v1.ManagementService/GetCertificate", connect_go.NewUnaryHandler(
+		"/api.mgmt.v1.ManagementService/GetCertificate",
+		svc.GetCertificate,
+		opts...,
+	))
+	mux.Handle("/api.mgmt.v1.ManagementService/RevokeCertificate", connect_go.NewUnaryHandler(
+		"/api.mgmt.v1.ManagementService/RevokeCertificate",
+		svc.RevokeCertificate,
+		opts...,
+	))
+	mux.Handle("/api.mgmt.v1.ManagementService/ListRoleBindings", connect_go.NewUnaryHandler(
+		"/api.mgmt.v1.ManagementService/ListRoleBindings",
+		svc.ListRoleBindings,
+		opts...,
+	))
+	mux.Handle("/api.mgmt.v1.ManagementService/CreateRoleBinding", connect_go.NewUnaryHandler(
+		"/api.mgmt.v1.ManagementService/CreateRoleBinding",
+		svc.CreateRoleBinding,
+		opts...,
+	))
+	mux.Handle("/api.mgmt.v1.ManagementService/DeleteRoleBinding", connect_go.NewUnaryHandler(
+		"/api.mgmt.v1.ManagementService/DeleteRoleBinding",
+		svc.DeleteRoleBinding,
+		opts...,
+	))
 	return "/api.mgmt.v1.ManagementService/", mux
 }
 
@@ -148,6 +292,38 @@ func (UnimplementedManagementServiceHandler) CreateSigner(context.Context, *conn
 	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("api.mgmt.v1.ManagementService.CreateSigner is not implemented"))
 }
 
+func (UnimplementedManagementServiceHandler) UpdateSigner(context.Context, *connect_go.Request[v1.UpdateSignerRequest]) (*connect_go.Response[v1.UpdateSignerResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("api.mgmt.v1.ManagementService.UpdateSigner is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) RotateSigner(context.Context, *connect_go.Request[v1.RotateSignerRequest]) (*connect_go.Response[v1.RotateSignerResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("api.mgmt.v1.ManagementService.RotateSigner is not implemented"))
+}
+
 func (UnimplementedManagementServiceHandler) DeleteSigner(context.Context, *connect_go.Request[v1.DeleteSignerRequest]) (*connect_go.Response[emptypb.Empty], error) {
 	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("api.mgmt.v1.ManagementService.DeleteSigner is not implemented"))
 }
+
+func (UnimplementedManagementServiceHandler) ListCertificates(context.Context, *connect_go.Request[v1.ListCertificatesRequest]) (*connect_go.Response[v1.ListCertificatesResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("api.mgmt.v1.ManagementService.ListCertificates is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) GetCertificate(context.Context, *connect_go.Request[v1.GetCertificateRequest]) (*connect_go.Response[v1.GetCertificateResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("api.mgmt.v1.ManagementService.GetCertificate is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) RevokeCertificate(context.Context, *connect_go.Request[v1.RevokeCertificateRequest]) (*connect_go.Response[emptypb.Empty], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("api.mgmt.v1.ManagementService.RevokeCertificate is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) ListRoleBindings(context.Context, *connect_go.Request[emptypb.Empty]) (*connect_go.Response[v1.ListRoleBindingsResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("api.mgmt.v1.ManagementService.ListRoleBindings is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) CreateRoleBinding(context.Context, *connect_go.Request[v1.CreateRoleBindingRequest]) (*connect_go.Response[emptypb.Empty], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("api.mgmt.v1.ManagementService.CreateRoleBinding is not implemented"))
+}
+
+func (UnimplementedManagementServiceHandler) DeleteRoleBinding(context.Context, *connect_go.Request[v1.DeleteRoleBindingRequest]) (*connect_go.Response[emptypb.Empty], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("api.mgmt.v1.ManagementService.DeleteRoleBinding is not implemented"))
+}
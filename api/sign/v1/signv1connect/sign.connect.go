@@ -29,6 +29,7 @@ const (
 type SignServiceClient interface {
 	Sign(context.Context, *connect_go.Request[v1.SignRequest]) (*connect_go.Response[v1.SignResponse], error)
 	TrustBundle(context.Context, *connect_go.Request[v1.TrustBundleRequest]) (*connect_go.Response[v1.TrustBundleResponse], error)
+	GetCRL(context.Context, *connect_go.Request[v1.GetCRLRequest]) (*connect_go.Response[v1.GetCRLResponse], error)
 }
 
 // NewSignServiceClient constructs a client for the api.sign.v1.SignService service. By default, it
@@ -51,6 +52,11 @@ func NewSignServiceClient(httpClient connect_go.HTTPClient, baseURL string, opts
 			baseURL+"/api.sign.v1.SignService/TrustBundle",
 			opts...,
 		),
+		getCRL: connect_go.NewClient[v1.GetCRLRequest, v1.GetCRLResponse](
+			httpClient,
+			baseURL+"/api.sign.v1.SignService/GetCRL",
+			opts...,
+		),
 	}
 }
 
@@ -58,6 +64,7 @@ func NewSignServiceClient(httpClient connect_go.HTTPClient, baseURL string, opts
 type signServiceClient struct {
 	sign        *connect_go.Client[v1.SignRequest, v1.SignResponse]
 	trustBundle *connect_go.Client[v1.TrustBundleRequest, v1.TrustBundleResponse]
+	getCRL      *connect_go.Client[v1.GetCRLRequest, v1.GetCRLResponse]
 }
 
 // Sign calls api.sign.v1.SignService.Sign.
@@ -70,10 +77,16 @@ func (c *signServiceClient) TrustBundle(ctx context.Context, req *connect_go.Req
 	return c.trustBundle.CallUnary(ctx, req)
 }
 
+// GetCRL calls api.sign.v1.SignService.GetCRL.
+func (c *signServiceClient) GetCRL(ctx context.Context, req *connect_go.Request[v1.GetCRLRequest]) (*connect_go.Response[v1.GetCRLResponse], error) {
+	return c.getCRL.CallUnary(ctx, req)
+}
+
 // SignServiceHandler is an implementation of the api.sign.v1.SignService service.
 type SignServiceHandler interface {
 	Sign(context.Context, *connect_go.Request[v1.SignRequest]) (*connect_go.Response[v1.SignResponse], error)
 	TrustBundle(context.Context, *connect_go.Request[v1.TrustBundleRequest]) (*connect_go.Response[v1.TrustBundleResponse], error)
+	GetCRL(context.Context, *connect_go.Request[v1.GetCRLRequest]) (*connect_go.Response[v1.GetCRLResponse], error)
 }
 
 // NewSignServiceHandler builds an HTTP handler from the service implementation. It returns the path
@@ -93,6 +106,11 @@ func NewSignServiceHandler(svc SignServiceHandler, opts ...connect_go.HandlerOpt
 		svc.TrustBundle,
 		opts...,
 	))
+	mux.Handle("/api.sign.v1.SignService/GetCRL", connect_go.NewUnaryHandler(
+		"/api.sign.v1.SignService/GetCRL",
+		svc.GetCRL,
+		opts...,
+	))
 	return "/api.sign.v1.SignService/", mux
 }
 
@@ -106,3 +124,7 @@ func (UnimplementedSignServiceHandler) Sign(context.Context, *connect_go.Request
 func (UnimplementedSignServiceHandler) TrustBundle(context.Context, *connect_go.Request[v1.TrustBundleRequest]) (*connect_go.Response[v1.TrustBundleResponse], error) {
 	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("api.sign.v1.SignService.TrustBundle is not implemented"))
 }
+
+func (UnimplementedSignServiceHandler) GetCRL(context.Context, *connect_go.Request[v1.GetCRLRequest]) (*connect_go.Response[v1.GetCRLResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("api.sign.v1.SignService.GetCRL is not implemented"))
+}
@@ -111,6 +111,16 @@ func main() {
 	}
 	fmt.Printf("%s\n", signResp.Msg.String())
 
+	fmt.Println("testing list certificates")
+	signerID := int64(1)
+	certsResp, certsErr := client.ListCertificates(context.Background(), connect.NewRequest(&mgmtv1.ListCertificatesRequest{
+		SignerId: &signerID,
+	}))
+	if certsErr != nil {
+		panic(certsErr)
+	}
+	fmt.Printf("found %d certificates\n", len(certsResp.Msg.Certificates))
+
 	fmt.Println("testing trust bundle")
 	trustResp, trustErr := signClient.TrustBundle(context.Background(), connect.NewRequest(&signv1.TrustBundleRequest{
 		SignerId: 1,
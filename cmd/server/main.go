@@ -18,22 +18,54 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/bufbuild/connect-go"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"go.uber.org/zap"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 
 	"github.com/jakexks/northfoot/api/mgmt/v1/mgmtv1connect"
 	"github.com/jakexks/northfoot/api/sign/v1/signv1connect"
+	"github.com/jakexks/northfoot/internal/authn"
 	"github.com/jakexks/northfoot/internal/server"
+	"github.com/jakexks/northfoot/internal/util"
 )
 
+// shutdownTimeout bounds how long in-flight requests may take to finish on
+// shutdown.
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	log, _ := zap.NewProduction()
 	defer log.Sync()
 
-	s, err := server.NewServer(server.WithLogger(log), server.WithDatastore("./northfoot.db"))
+	certFile, keyFile := os.Getenv("NORTHFOOT_TLS_CERT_FILE"), os.Getenv("NORTHFOOT_TLS_KEY_FILE")
+	useTLS := certFile != "" && keyFile != ""
+	baseURL := "http://localhost:8080"
+	if useTLS {
+		baseURL = "https://localhost:8080"
+	}
+
+	options := []server.ServerOption{server.WithLogger(log), server.WithDatastore("./northfoot.db"), server.WithBaseURL(baseURL)}
+	if password := os.Getenv("NORTHFOOT_EST_PASSWORD"); password != "" {
+		options = append(options, server.WithBasicAuth(authn.StaticBasicAuth(os.Getenv("NORTHFOOT_EST_USERNAME"), password)))
+	}
+	if option := keyEncryptionOption(log); option != nil {
+		options = append(options, option)
+	}
+	s, err := server.NewServer(options...)
 	if err != nil {
 		log.Fatal("failed to create server", zap.Error(err))
 	}
@@ -42,14 +74,157 @@ func main() {
 		log.Fatal("failed to init server", zap.Error(err))
 	}
 
+	authenticators, closeAuthenticators := bearerAuthenticators(log)
+	defer closeAuthenticators()
+	if os.Getenv("NORTHFOOT_ADMINS") != "" && !useTLS && len(authenticators) == 0 {
+		log.Fatal("NORTHFOOT_ADMINS requires callers to authenticate with TLS client certificates, NORTHFOOT_API_TOKEN or JWT-SVIDs")
+	}
+
 	mux := http.NewServeMux()
-	mux.Handle(signv1connect.NewSignServiceHandler(s))
-	mux.Handle(mgmtv1connect.NewManagementServiceHandler(s))
-	log.Info("starting server", zap.String("bind", "localhost:8080"))
-	err = http.ListenAndServe(
-		"localhost:8080",
+	mux.Handle(signv1connect.NewSignServiceHandler(s, serviceOptions(log, s, authenticators, "NORTHFOOT_SIGN_ALLOWED_CLIENTS")...))
+	mux.Handle(mgmtv1connect.NewManagementServiceHandler(s, serviceOptions(log, s, authenticators, "NORTHFOOT_MGMT_ALLOWED_CLIENTS")...))
+	mux.Handle("/crl/", s.CRLHandler())
+	mux.Handle("/ocsp/", s.OCSPHandler())
+	mux.Handle("/acme/", s.ACMEHandler())
+	mux.Handle("/.well-known/est/", s.ESTHandler())
+	mux.Handle("/scep/", s.SCEPHandler())
+	srv := &http.Server{
+		Addr: "localhost:8080",
 		// Use h2c so we can serve HTTP/2 without TLS.
-		h2c.NewHandler(mux, &http2.Server{}),
-	)
-	log.Error("server stopped", zap.Error(err))
+		Handler: h2c.NewHandler(mux, &http2.Server{}),
+	}
+	if useTLS {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			log.Fatal("failed to load TLS certificate", zap.Error(err))
+		}
+		var clientCAs []*x509.Certificate
+		if caFile := os.Getenv("NORTHFOOT_TLS_CLIENT_CA_FILE"); caFile != "" {
+			pemBytes, err := os.ReadFile(caFile)
+			if err != nil {
+				log.Fatal("failed to read client CA file", zap.Error(err))
+			}
+			if clientCAs, err = util.ParseCertificatesPEM(pemBytes); err != nil {
+				log.Fatal("failed to parse client CA file", zap.Error(err))
+			}
+		}
+		srv.Handler = authn.ClientCertificateMiddleware(mux, s.TrustedClientChain)
+		srv.TLSConfig = s.TLSConfig(cert, clientCAs)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		log.Info("shutting down server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Error("failed to shut down server", zap.Error(err))
+		}
+	}()
+
+	log.Info("starting server", zap.String("bind", srv.Addr), zap.Bool("tls", useTLS))
+	if useTLS {
+		err = srv.ListenAndServeTLS("", "")
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		log.Error("server stopped", zap.Error(err))
+	}
+	if err := s.Close(); err != nil {
+		log.Error("failed to close server", zap.Error(err))
+	}
+}
+
+// serviceOptions requires callers to authenticate with a client certificate
+// or one of authenticators, if any, restricts a service to the client
+// certificates in the allow list held by an environment variable, if it is
+// set, and enforces role bindings if NORTHFOOT_ADMINS lists the bootstrap
+// admin identities.
+func serviceOptions(log *zap.Logger, s *server.Server, authenticators []authn.Authenticator, env string) []connect.HandlerOption {
+	var interceptors []connect.Interceptor
+	if len(authenticators) > 0 {
+		interceptors = append(interceptors, authn.AuthenticationInterceptor(authenticators...))
+	}
+	if list := os.Getenv(env); list != "" {
+		allowed, err := authn.ParseCertificateMatcher(list)
+		if err != nil {
+			log.Fatal("invalid client allow list", zap.String("env", env), zap.Error(err))
+		}
+		interceptors = append(interceptors, authn.ClientCertificateInterceptor(allowed))
+	}
+	if admins := os.Getenv("NORTHFOOT_ADMINS"); admins != "" {
+		interceptors = append(interceptors, s.AuthorizationInterceptor(strings.Split(admins, ",")...))
+	}
+	if len(interceptors) == 0 {
+		return nil
+	}
+	return []connect.HandlerOption{connect.WithInterceptors(interceptors...)}
+}
+
+// bearerAuthenticators returns the authenticators for the static token in
+// NORTHFOOT_API_TOKEN and for JWT-SVIDs verified against the bundle in
+// NORTHFOOT_JWT_BUNDLE_FILE or served at NORTHFOOT_JWT_BUNDLE_ENDPOINT, and a
+// function that stops refreshing the bundle.
+func bearerAuthenticators(log *zap.Logger) ([]authn.Authenticator, func()) {
+	var authenticators []authn.Authenticator
+	if token := os.Getenv("NORTHFOOT_API_TOKEN"); token != "" {
+		authenticators = append(authenticators, authn.StaticToken(token))
+	}
+	bundleFile, bundleEndpoint := os.Getenv("NORTHFOOT_JWT_BUNDLE_FILE"), os.Getenv("NORTHFOOT_JWT_BUNDLE_ENDPOINT")
+	if bundleFile == "" && bundleEndpoint == "" {
+		return authenticators, func() {}
+	}
+	trustDomain, err := spiffeid.TrustDomainFromString(os.Getenv("NORTHFOOT_JWT_TRUST_DOMAIN"))
+	if err != nil {
+		log.Fatal("invalid JWT-SVID trust domain", zap.Error(err))
+	}
+	audience := os.Getenv("NORTHFOOT_JWT_AUDIENCE")
+	if audience == "" {
+		log.Fatal("NORTHFOOT_JWT_AUDIENCE must be set to accept JWT-SVIDs")
+	}
+	allowed := spiffeid.MatchMemberOf(trustDomain)
+	if list := os.Getenv("NORTHFOOT_JWT_ALLOWED_IDS"); list != "" {
+		if allowed, err = authn.ParseIDMatcher(list); err != nil {
+			log.Fatal("invalid JWT-SVID allow list", zap.Error(err))
+		}
+	}
+	var bundles *authn.JWTBundleSource
+	if bundleFile != "" {
+		bundles, err = authn.NewFileJWTBundleSource(trustDomain, bundleFile, 0, log)
+	} else {
+		bundles, err = authn.NewEndpointJWTBundleSource(trustDomain, bundleEndpoint, 0, log)
+	}
+	if err != nil {
+		log.Fatal("failed to load JWT bundle", zap.Error(err))
+	}
+	authenticators = append(authenticators, authn.SpiffeJWT(bundles, strings.Split(audience, ","), allowed))
+	return authenticators, func() { bundles.Close() }
+}
+
+// keyEncryptionOption returns the key encryption key for persisted CA keys
+// from NORTHFOOT_KEK or the file in NORTHFOOT_KEK_FILE, both base64 encoded,
+// or derives it from NORTHFOOT_KEK_PASSPHRASE.
+func keyEncryptionOption(log *zap.Logger) server.ServerOption {
+	encoded := os.Getenv("NORTHFOOT_KEK")
+	if file := os.Getenv("NORTHFOOT_KEK_FILE"); file != "" {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatal("failed to read key encryption key file", zap.Error(err))
+		}
+		encoded = string(contents)
+	}
+	if encoded != "" {
+		kek, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			log.Fatal("key encryption key is not base64 encoded", zap.Error(err))
+		}
+		return server.WithKeyEncryptionKey(kek)
+	}
+	if passphrase := os.Getenv("NORTHFOOT_KEK_PASSPHRASE"); passphrase != "" {
+		return server.WithKeyEncryptionPassphrase(passphrase)
+	}
+	return nil
 }
@@ -0,0 +1,240 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package acme implements an RFC 8555 ACME server in front of Northfoot
+// signers. Each signer has its own directory at /acme/{signer id}/directory.
+// Account, order and challenge state is held in memory, like the CA keys of
+// the signers it fronts.
+package acme
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Issuer issues certificates on behalf of the ACME server.
+type Issuer interface {
+	// SignerExists reports whether a signer with the given ID is configured
+	// and allows ACME enrollment.
+	SignerExists(ctx context.Context, signerID int64) (bool, error)
+	// Issue signs the DER encoded csr with the given signer and returns the
+	// certificate followed by any intermediates. A zero duration uses the
+	// signer's default.
+	Issue(ctx context.Context, signerID int64, csr []byte, duration time.Duration) ([]*x509.Certificate, error)
+}
+
+// Resolver looks up TXT records for dns-01 challenges. *net.Resolver
+// satisfies Resolver.
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+type Option func(*Handler)
+
+// WithResolver sets the resolver used to validate dns-01 challenges.
+func WithResolver(resolver Resolver) Option {
+	return func(h *Handler) {
+		h.resolver = resolver
+	}
+}
+
+// WithBaseURL sets the externally reachable URL of the server. If unset, it
+// is derived from each request.
+func WithBaseURL(baseURL string) Option {
+	return func(h *Handler) {
+		h.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithLogger sets the logger.
+func WithLogger(logger *zap.Logger) Option {
+	return func(h *Handler) {
+		h.log = logger
+	}
+}
+
+// WithChallengePorts overrides the ports used to validate http-01 (default
+// 80) and tls-alpn-01 (default 443) challenges.
+func WithChallengePorts(http01, tlsALPN01 int) Option {
+	return func(h *Handler) {
+		h.http01Port = http01
+		h.tlsALPN01Port = tlsALPN01
+	}
+}
+
+// Handler serves the ACME API for every signer under /acme/.
+type Handler struct {
+	issuer        Issuer
+	resolver      Resolver
+	log           *zap.Logger
+	baseURL       string
+	http01Port    int
+	tlsALPN01Port int
+
+	nonces *nonces
+	store  *store
+}
+
+func NewHandler(issuer Issuer, options ...Option) *Handler {
+	h := &Handler{
+		issuer:        issuer,
+		resolver:      net.DefaultResolver,
+		log:           zap.NewNop(),
+		http01Port:    80,
+		tlsALPN01Port: 443,
+		nonces:        newNonces(),
+		store:         newStore(),
+	}
+	for _, option := range options {
+		option(h)
+	}
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/acme/"), "/"), "/")
+	if len(parts) < 2 {
+		http.NotFound(w, r)
+		return
+	}
+	signerID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	exists, err := h.issuer.SignerExists(r.Context(), signerID)
+	if err != nil {
+		h.log.Error("failed to look up signer", zap.Int64("signer_id", signerID), zap.Error(err))
+		h.writeProblem(w, r, signerID, serverInternal("failed to look up signer"))
+		return
+	}
+	if !exists {
+		http.NotFound(w, r)
+		return
+	}
+	resource, id, sub := parts[1], "", ""
+	if len(parts) > 2 {
+		id = parts[2]
+	}
+	if len(parts) > 3 {
+		sub = parts[3]
+	}
+	if len(parts) > 4 {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case resource == "directory" && id == "":
+		h.directory(w, r, signerID)
+	case resource == "new-nonce" && id == "":
+		h.newNonce(w, r, signerID)
+	case r.Method != http.MethodPost:
+		w.Header().Set("Allow", http.MethodPost)
+		h.writeProblem(w, r, signerID, &problem{Type: errMalformed, Detail: "method not allowed", Status: http.StatusMethodNotAllowed})
+	case resource == "new-account" && id == "":
+		h.newAccount(w, r, signerID)
+	case resource == "account" && id != "" && sub == "":
+		h.account(w, r, signerID, id)
+	case resource == "account" && id != "" && sub == "orders":
+		h.accountOrders(w, r, signerID, id)
+	case resource == "new-order" && id == "":
+		h.newOrder(w, r, signerID)
+	case resource == "order" && id != "" && sub == "":
+		h.order(w, r, signerID, id)
+	case resource == "order" && id != "" && sub == "finalize":
+		h.finalize(w, r, signerID, id)
+	case resource == "authz" && id != "" && sub == "":
+		h.authorization(w, r, signerID, id)
+	case resource == "chall" && id != "" && sub == "":
+		h.challenge(w, r, signerID, id)
+	case resource == "cert" && id != "" && sub == "":
+		h.certificate(w, r, signerID, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// root returns the externally reachable URL of the server.
+func (h *Handler) root(r *http.Request) string {
+	if h.baseURL != "" {
+		return h.baseURL
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// base returns the URL prefix of a signer's ACME resources.
+func (h *Handler) base(r *http.Request, signerID int64) string {
+	return h.root(r) + "/acme/" + strconv.FormatInt(signerID, 10)
+}
+
+func (h *Handler) directory(w http.ResponseWriter, r *http.Request, signerID int64) {
+	base := h.base(r, signerID)
+	h.writeJSON(w, r, signerID, http.StatusOK, map[string]any{
+		"newNonce":   base + "/new-nonce",
+		"newAccount": base + "/new-account",
+		"newOrder":   base + "/new-order",
+		"meta": map[string]any{
+			"externalAccountRequired": false,
+		},
+	})
+}
+
+func (h *Handler) newNonce(w http.ResponseWriter, r *http.Request, signerID int64) {
+	h.setHeaders(w, r, signerID)
+	w.Header().Set("Cache-Control", "no-store")
+	if r.Method == http.MethodHead {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) setHeaders(w http.ResponseWriter, r *http.Request, signerID int64) {
+	w.Header().Set("Replay-Nonce", h.nonces.issue())
+	w.Header().Add("Link", "<"+h.base(r, signerID)+"/directory>;rel=\"index\"")
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, r *http.Request, signerID int64, status int, v any) {
+	h.setHeaders(w, r, signerID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.log.Debug("failed to write ACME response", zap.Error(err))
+	}
+}
+
+func (h *Handler) writeProblem(w http.ResponseWriter, r *http.Request, signerID int64, p *problem) {
+	h.setHeaders(w, r, signerID)
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		h.log.Debug("failed to write ACME problem", zap.Error(err))
+	}
+}
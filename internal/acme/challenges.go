@@ -0,0 +1,126 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package acme
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/asn1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const acmeTLS1Protocol = "acme-tls/1"
+
+// id-pe-acmeIdentifier from RFC 8737.
+var oidACMEIdentifier = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 31}
+
+func challengeFailed(typ, detail string) *problem {
+	return &problem{Type: typ, Detail: detail, Status: http.StatusForbidden}
+}
+
+func (h *Handler) validateHTTP01(ctx context.Context, domain, token, keyAuth string) *problem {
+	url := "http://" + net.JoinHostPort(domain, strconv.Itoa(h.http01Port)) + "/.well-known/acme-challenge/" + token
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return challengeFailed(errConnection, err.Error())
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return challengeFailed(errConnection, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return challengeFailed(errIncorrectResponse, fmt.Sprintf("%s returned status %d", url, resp.StatusCode))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<12))
+	if err != nil {
+		return challengeFailed(errConnection, err.Error())
+	}
+	if subtle.ConstantTimeCompare(bytes.TrimSpace(body), []byte(keyAuth)) != 1 {
+		return challengeFailed(errIncorrectResponse, "key authorization from "+url+" does not match")
+	}
+	return nil
+}
+
+func (h *Handler) validateDNS01(ctx context.Context, domain, keyAuth string) *problem {
+	name := "_acme-challenge." + domain
+	records, err := h.resolver.LookupTXT(ctx, name)
+	if err != nil {
+		return challengeFailed(errDNS, fmt.Sprintf("failed to look up TXT records for %s: %s", name, err))
+	}
+	sum := sha256.Sum256([]byte(keyAuth))
+	want := base64.RawURLEncoding.EncodeToString(sum[:])
+	for _, record := range records {
+		if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(record)), []byte(want)) == 1 {
+			return nil
+		}
+	}
+	return challengeFailed(errIncorrectResponse, "no matching TXT record found at "+name)
+}
+
+func (h *Handler) validateTLSALPN01(ctx context.Context, domain, keyAuth string) *problem {
+	dialer := &tls.Dialer{Config: &tls.Config{
+		ServerName: domain,
+		NextProtos: []string{acmeTLS1Protocol},
+		// The challenge certificate is self-signed, it is checked below.
+		InsecureSkipVerify: true,
+	}}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(domain, strconv.Itoa(h.tlsALPN01Port)))
+	if err != nil {
+		return challengeFailed(errTLS, err.Error())
+	}
+	defer conn.Close()
+	state := conn.(*tls.Conn).ConnectionState()
+	if state.NegotiatedProtocol != acmeTLS1Protocol {
+		return challengeFailed(errTLS, "server did not negotiate "+acmeTLS1Protocol)
+	}
+	if len(state.PeerCertificates) != 1 {
+		return challengeFailed(errTLS, "server must present exactly one certificate")
+	}
+	cert := state.PeerCertificates[0]
+	if len(cert.DNSNames) != 1 || !strings.EqualFold(cert.DNSNames[0], domain) || len(cert.IPAddresses) > 0 || len(cert.URIs) > 0 || len(cert.EmailAddresses) > 0 {
+		return challengeFailed(errIncorrectResponse, "challenge certificate must contain only the dns name "+domain)
+	}
+	sum := sha256.Sum256([]byte(keyAuth))
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidACMEIdentifier) {
+			continue
+		}
+		if !ext.Critical {
+			return challengeFailed(errIncorrectResponse, "acmeIdentifier extension must be critical")
+		}
+		var value []byte
+		if rest, err := asn1.Unmarshal(ext.Value, &value); err != nil || len(rest) > 0 {
+			return challengeFailed(errIncorrectResponse, "malformed acmeIdentifier extension")
+		}
+		if subtle.ConstantTimeCompare(value, sum[:]) != 1 {
+			return challengeFailed(errIncorrectResponse, "acmeIdentifier extension does not match key authorization")
+		}
+		return nil
+	}
+	return challengeFailed(errIncorrectResponse, "challenge certificate has no acmeIdentifier extension")
+}
@@ -0,0 +1,458 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package acme
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jakexks/northfoot/internal/authn"
+)
+
+const challengeTimeout = 30 * time.Second
+
+func (h *Handler) newAccount(w http.ResponseWriter, r *http.Request, signerID int64) {
+	req, p := h.verify(r, signerID, true)
+	if p != nil {
+		h.writeProblem(w, r, signerID, p)
+		return
+	}
+	var payload struct {
+		Contact              []string `json:"contact"`
+		TermsOfServiceAgreed bool     `json:"termsOfServiceAgreed"`
+		OnlyReturnExisting   bool     `json:"onlyReturnExisting"`
+	}
+	if err := json.Unmarshal(req.payload, &payload); err != nil {
+		h.writeProblem(w, r, signerID, malformed("invalid new-account payload"))
+		return
+	}
+	thumbprint, err := req.jwk.Thumbprint(crypto.SHA256)
+	if err != nil {
+		h.writeProblem(w, r, signerID, malformed("failed to compute JWK thumbprint"))
+		return
+	}
+	acct, created, p := h.store.getOrCreateAccount(&account{
+		signerID:   signerID,
+		key:        req.jwk,
+		thumbprint: base64.RawURLEncoding.EncodeToString(thumbprint),
+		Status:     statusValid,
+		Contact:    payload.Contact,
+	}, !payload.OnlyReturnExisting)
+	if p != nil {
+		h.writeProblem(w, r, signerID, p)
+		return
+	}
+	if acct == nil {
+		h.writeProblem(w, r, signerID, &problem{Type: errAccountDoesNotExist, Detail: "no account exists for this key", Status: http.StatusBadRequest})
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+		h.log.Info("created ACME account", zap.Int64("signer_id", signerID), zap.String("account", acct.id))
+	}
+	w.Header().Set("Location", h.accountURL(r, signerID, acct.id))
+	h.writeJSON(w, r, signerID, status, h.accountResource(r, signerID, acct))
+}
+
+func (h *Handler) account(w http.ResponseWriter, r *http.Request, signerID int64, id string) {
+	req, p := h.verify(r, signerID, false)
+	if p != nil {
+		h.writeProblem(w, r, signerID, p)
+		return
+	}
+	if req.account.id != id {
+		h.writeProblem(w, r, signerID, unauthorized("account does not match request signer"))
+		return
+	}
+	acct := req.account
+	if len(req.payload) > 0 {
+		var payload struct {
+			Contact []string `json:"contact"`
+			Status  string   `json:"status"`
+		}
+		if err := json.Unmarshal(req.payload, &payload); err != nil {
+			h.writeProblem(w, r, signerID, malformed("invalid account payload"))
+			return
+		}
+		if payload.Status != "" && payload.Status != statusDeactivated {
+			h.writeProblem(w, r, signerID, malformed("accounts can only be deactivated"))
+			return
+		}
+		acct = h.store.updateAccount(id, func(a *account) {
+			if payload.Contact != nil {
+				a.Contact = payload.Contact
+			}
+			if payload.Status != "" {
+				a.Status = payload.Status
+			}
+		})
+	}
+	h.writeJSON(w, r, signerID, http.StatusOK, h.accountResource(r, signerID, acct))
+}
+
+func (h *Handler) accountOrders(w http.ResponseWriter, r *http.Request, signerID int64, id string) {
+	req, p := h.verify(r, signerID, false)
+	if p != nil {
+		h.writeProblem(w, r, signerID, p)
+		return
+	}
+	if req.account.id != id {
+		h.writeProblem(w, r, signerID, unauthorized("account does not match request signer"))
+		return
+	}
+	orders := []string{}
+	for _, orderID := range h.store.listOrders(id) {
+		orders = append(orders, h.base(r, signerID)+"/order/"+orderID)
+	}
+	h.writeJSON(w, r, signerID, http.StatusOK, map[string]any{"orders": orders})
+}
+
+func (h *Handler) newOrder(w http.ResponseWriter, r *http.Request, signerID int64) {
+	req, p := h.verify(r, signerID, false)
+	if p != nil {
+		h.writeProblem(w, r, signerID, p)
+		return
+	}
+	var payload struct {
+		Identifiers []identifier `json:"identifiers"`
+		NotBefore   *time.Time   `json:"notBefore"`
+		NotAfter    *time.Time   `json:"notAfter"`
+	}
+	if err := json.Unmarshal(req.payload, &payload); err != nil {
+		h.writeProblem(w, r, signerID, malformed("invalid new-order payload"))
+		return
+	}
+	if len(payload.Identifiers) == 0 {
+		h.writeProblem(w, r, signerID, malformed("order must contain at least one identifier"))
+		return
+	}
+	if len(payload.Identifiers) > maxOrderIdentifiers {
+		h.writeProblem(w, r, signerID, &problem{Type: errRejectedIdentifier, Detail: "order has too many identifiers", Status: http.StatusBadRequest})
+		return
+	}
+	seen := make(map[string]bool)
+	var identifiers []identifier
+	for _, ident := range payload.Identifiers {
+		if ident.Type != "dns" {
+			h.writeProblem(w, r, signerID, &problem{Type: errUnsupportedIdentifier, Detail: "only dns identifiers are supported", Status: http.StatusBadRequest})
+			return
+		}
+		value := strings.ToLower(strings.TrimSuffix(ident.Value, "."))
+		if !validDNSName(value) {
+			h.writeProblem(w, r, signerID, &problem{Type: errRejectedIdentifier, Detail: "invalid dns identifier " + ident.Value, Status: http.StatusBadRequest})
+			return
+		}
+		if !seen[value] {
+			seen[value] = true
+			identifiers = append(identifiers, identifier{Type: "dns", Value: value})
+		}
+	}
+	if payload.NotBefore != nil {
+		h.writeProblem(w, r, signerID, malformed("notBefore is not supported"))
+		return
+	}
+	var duration time.Duration
+	if payload.NotAfter != nil {
+		duration = time.Until(*payload.NotAfter)
+		if duration <= 0 {
+			h.writeProblem(w, r, signerID, malformed("notAfter is in the past"))
+			return
+		}
+	}
+
+	o, p := h.store.createOrder(&order{
+		accountID:   req.account.id,
+		signerID:    signerID,
+		duration:    duration,
+		Status:      statusPending,
+		Expires:     time.Now().Add(orderLifetime).UTC(),
+		Identifiers: identifiers,
+		NotAfter:    payload.NotAfter,
+	}, func(ident identifier) []string {
+		// wildcards can only be validated through DNS
+		if strings.HasPrefix(ident.Value, "*.") {
+			return []string{challengeDNS01}
+		}
+		return []string{challengeHTTP01, challengeDNS01, challengeTLSALPN01}
+	})
+	if p != nil {
+		h.writeProblem(w, r, signerID, p)
+		return
+	}
+	h.log.Info("created ACME order", zap.Int64("signer_id", signerID), zap.String("account", req.account.id), zap.String("order", o.id))
+	w.Header().Set("Location", h.base(r, signerID)+"/order/"+o.id)
+	h.writeJSON(w, r, signerID, http.StatusCreated, h.orderResource(r, signerID, o))
+}
+
+func (h *Handler) order(w http.ResponseWriter, r *http.Request, signerID int64, id string) {
+	req, p := h.verify(r, signerID, false)
+	if p != nil {
+		h.writeProblem(w, r, signerID, p)
+		return
+	}
+	o := h.store.getOrder(id)
+	if o == nil || o.signerID != signerID || o.accountID != req.account.id {
+		h.writeProblem(w, r, signerID, notFound("order not found"))
+		return
+	}
+	h.writeJSON(w, r, signerID, http.StatusOK, h.orderResource(r, signerID, o))
+}
+
+func (h *Handler) finalize(w http.ResponseWriter, r *http.Request, signerID int64, id string) {
+	req, p := h.verify(r, signerID, false)
+	if p != nil {
+		h.writeProblem(w, r, signerID, p)
+		return
+	}
+	o := h.store.getOrder(id)
+	if o == nil || o.signerID != signerID || o.accountID != req.account.id {
+		h.writeProblem(w, r, signerID, notFound("order not found"))
+		return
+	}
+	if o.Status != statusReady {
+		h.writeProblem(w, r, signerID, &problem{Type: errOrderNotReady, Detail: "order is " + o.Status, Status: http.StatusForbidden})
+		return
+	}
+	var payload struct {
+		CSR string `json:"csr"`
+	}
+	if err := json.Unmarshal(req.payload, &payload); err != nil {
+		h.writeProblem(w, r, signerID, malformed("invalid finalize payload"))
+		return
+	}
+	der, err := base64.RawURLEncoding.DecodeString(payload.CSR)
+	if err != nil {
+		h.writeProblem(w, r, signerID, &problem{Type: errBadCSR, Detail: "csr is not base64url encoded", Status: http.StatusBadRequest})
+		return
+	}
+	if p := checkCSR(der, o.Identifiers); p != nil {
+		h.writeProblem(w, r, signerID, p)
+		return
+	}
+
+	// Only one finalize request may proceed per order.
+	started := false
+	h.store.updateOrder(id, func(o *order) {
+		if o.Status == statusReady {
+			o.Status = statusProcessing
+			started = true
+		}
+	})
+	if !started {
+		h.writeProblem(w, r, signerID, &problem{Type: errOrderNotReady, Detail: "order is already being finalized", Status: http.StatusForbidden})
+		return
+	}
+
+	ctx := authn.WithIdentity(r.Context(), h.accountURL(r, signerID, req.account.id))
+	chain, err := h.issuer.Issue(ctx, signerID, der, o.duration)
+	if err != nil {
+		h.log.Warn("failed to issue ACME certificate", zap.Int64("signer_id", signerID), zap.String("order", id), zap.Error(err))
+		p := &problem{Type: errBadCSR, Detail: "failed to issue certificate: " + err.Error(), Status: http.StatusBadRequest}
+		o = h.store.updateOrder(id, func(o *order) {
+			o.Status = statusInvalid
+			o.Error = p
+		})
+		h.writeProblem(w, r, signerID, p)
+		return
+	}
+	certID := h.store.createCertificate(&certificate{accountID: req.account.id, chain: chain})
+	o = h.store.updateOrder(id, func(o *order) {
+		o.Status = statusValid
+		o.certID = certID
+	})
+	h.log.Info("finalized ACME order", zap.Int64("signer_id", signerID), zap.String("order", id), zap.String("serial", chain[0].SerialNumber.Text(16)))
+	w.Header().Set("Location", h.base(r, signerID)+"/order/"+o.id)
+	h.writeJSON(w, r, signerID, http.StatusOK, h.orderResource(r, signerID, o))
+}
+
+func (h *Handler) authorization(w http.ResponseWriter, r *http.Request, signerID int64, id string) {
+	req, p := h.verify(r, signerID, false)
+	if p != nil {
+		h.writeProblem(w, r, signerID, p)
+		return
+	}
+	authz := h.store.getAuthorization(id)
+	if authz == nil || authz.signerID != signerID || authz.accountID != req.account.id {
+		h.writeProblem(w, r, signerID, notFound("authorization not found"))
+		return
+	}
+	h.writeJSON(w, r, signerID, http.StatusOK, h.authorizationResource(r, signerID, authz))
+}
+
+func (h *Handler) challenge(w http.ResponseWriter, r *http.Request, signerID int64, id string) {
+	req, p := h.verify(r, signerID, false)
+	if p != nil {
+		h.writeProblem(w, r, signerID, p)
+		return
+	}
+	ch, authz := h.store.getChallenge(id)
+	if ch == nil || authz.signerID != signerID || authz.accountID != req.account.id {
+		h.writeProblem(w, r, signerID, notFound("challenge not found"))
+		return
+	}
+	// An empty JSON object asks the server to validate the challenge, a
+	// POST-as-GET only fetches it.
+	if len(req.payload) > 0 && h.store.startChallenge(id) {
+		ch.Status = statusProcessing
+		keyAuth := ch.Token + "." + req.account.thumbprint
+		go h.validate(ch.id, ch.Type, authz.Identifier.Value, ch.Token, keyAuth)
+	}
+	w.Header().Add("Link", "<"+h.base(r, signerID)+"/authz/"+authz.id+">;rel=\"up\"")
+	h.writeJSON(w, r, signerID, http.StatusOK, h.challengeResource(r, signerID, ch))
+}
+
+func (h *Handler) validate(id, typ, domain, token, keyAuth string) {
+	ctx, cancel := context.WithTimeout(context.Background(), challengeTimeout)
+	defer cancel()
+	var p *problem
+	switch typ {
+	case challengeHTTP01:
+		p = h.validateHTTP01(ctx, domain, token, keyAuth)
+	case challengeDNS01:
+		p = h.validateDNS01(ctx, domain, keyAuth)
+	case challengeTLSALPN01:
+		p = h.validateTLSALPN01(ctx, domain, keyAuth)
+	}
+	if p != nil {
+		h.log.Info("ACME challenge failed", zap.String("type", typ), zap.String("domain", domain), zap.Error(p))
+	} else {
+		h.log.Info("ACME challenge succeeded", zap.String("type", typ), zap.String("domain", domain))
+	}
+	h.store.completeChallenge(id, p)
+}
+
+func (h *Handler) certificate(w http.ResponseWriter, r *http.Request, signerID int64, id string) {
+	req, p := h.verify(r, signerID, false)
+	if p != nil {
+		h.writeProblem(w, r, signerID, p)
+		return
+	}
+	cert := h.store.getCertificate(id)
+	if cert == nil || cert.accountID != req.account.id {
+		h.writeProblem(w, r, signerID, notFound("certificate not found"))
+		return
+	}
+	h.setHeaders(w, r, signerID)
+	w.Header().Set("Content-Type", "application/pem-certificate-chain")
+	w.WriteHeader(http.StatusOK)
+	for _, c := range cert.chain {
+		if err := pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: c.Raw}); err != nil {
+			h.log.Debug("failed to write ACME certificate", zap.Error(err))
+			return
+		}
+	}
+}
+
+// checkCSR verifies that a CSR requests exactly the identifiers of an order.
+func checkCSR(der []byte, identifiers []identifier) *problem {
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return &problem{Type: errBadCSR, Detail: "failed to parse csr", Status: http.StatusBadRequest}
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return &problem{Type: errBadCSR, Detail: "csr signature is invalid", Status: http.StatusBadRequest}
+	}
+	if len(csr.IPAddresses) > 0 || len(csr.EmailAddresses) > 0 || len(csr.URIs) > 0 {
+		return &problem{Type: errBadCSR, Detail: "csr may only contain dns names", Status: http.StatusBadRequest}
+	}
+	names := make(map[string]bool)
+	for _, name := range csr.DNSNames {
+		names[strings.ToLower(name)] = true
+	}
+	if cn := csr.Subject.CommonName; cn != "" && !names[strings.ToLower(cn)] {
+		return &problem{Type: errBadCSR, Detail: "csr common name must be one of its dns names", Status: http.StatusBadRequest}
+	}
+	var want, got []string
+	for _, ident := range identifiers {
+		want = append(want, ident.Value)
+	}
+	for name := range names {
+		got = append(got, name)
+	}
+	sort.Strings(want)
+	sort.Strings(got)
+	if strings.Join(want, ",") != strings.Join(got, ",") {
+		return &problem{Type: errBadCSR, Detail: "csr dns names do not match the order identifiers", Status: http.StatusBadRequest}
+	}
+	return nil
+}
+
+// validDNSName reports whether name is a plausible DNS name, optionally with
+// a leading wildcard label.
+func validDNSName(name string) bool {
+	name = strings.TrimPrefix(name, "*.")
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (h *Handler) accountURL(r *http.Request, signerID int64, id string) string {
+	return h.base(r, signerID) + "/account/" + id
+}
+
+func (h *Handler) accountResource(r *http.Request, signerID int64, acct *account) *account {
+	acct.Orders = h.accountURL(r, signerID, acct.id) + "/orders"
+	return acct
+}
+
+func (h *Handler) orderResource(r *http.Request, signerID int64, o *order) *order {
+	base := h.base(r, signerID)
+	o.Authorizations = nil
+	for _, id := range o.authzIDs {
+		o.Authorizations = append(o.Authorizations, base+"/authz/"+id)
+	}
+	o.Finalize = base + "/order/" + o.id + "/finalize"
+	if o.certID != "" {
+		o.Certificate = base + "/cert/" + o.certID
+	}
+	return o
+}
+
+func (h *Handler) authorizationResource(r *http.Request, signerID int64, authz *authorization) *authorization {
+	for _, ch := range authz.Challenges {
+		h.challengeResource(r, signerID, ch)
+	}
+	return authz
+}
+
+func (h *Handler) challengeResource(r *http.Request, signerID int64, ch *challenge) *challenge {
+	ch.URL = h.base(r, signerID) + "/chall/" + ch.id
+	return ch
+}
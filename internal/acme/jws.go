@@ -0,0 +1,165 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package acme
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+)
+
+const (
+	nonceLifetime  = time.Hour
+	maxNonces      = 100000
+	maxRequestSize = 1 << 16
+)
+
+var allowedAlgorithms = map[string]bool{
+	string(jose.RS256): true,
+	string(jose.PS256): true,
+	string(jose.ES256): true,
+	string(jose.ES384): true,
+	string(jose.ES512): true,
+	string(jose.EdDSA): true,
+}
+
+// nonces tracks the anti-replay nonces handed out to clients. Each nonce can
+// be used once. Once max nonces are outstanding the oldest are dropped, and
+// clients that were holding them retry after a badNonce error.
+type nonces struct {
+	lock   sync.Mutex
+	issued map[string]time.Time
+	// outstanding nonces in the order they were issued, and so expire
+	queue []string
+	max   int
+}
+
+func newNonces() *nonces {
+	return &nonces{issued: make(map[string]time.Time), max: maxNonces}
+}
+
+func (n *nonces) issue() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(b)
+	now := time.Now()
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	for len(n.queue) > 0 {
+		expiry, found := n.issued[n.queue[0]]
+		if found && now.Before(expiry) && len(n.queue) < n.max {
+			break
+		}
+		delete(n.issued, n.queue[0])
+		n.queue = n.queue[1:]
+	}
+	n.issued[nonce] = now.Add(nonceLifetime)
+	n.queue = append(n.queue, nonce)
+	return nonce
+}
+
+func (n *nonces) consume(nonce string) bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	expiry, found := n.issued[nonce]
+	delete(n.issued, nonce)
+	return found && time.Now().Before(expiry)
+}
+
+// signedRequest is a verified JWS request body.
+type signedRequest struct {
+	payload []byte
+	// set for requests signed with an account's key
+	account *account
+	// set for requests signed with an embedded key
+	jwk *jose.JSONWebKey
+}
+
+// verify authenticates a JWS request. New account requests must embed their
+// key, every other request must reference an existing account.
+func (h *Handler) verify(r *http.Request, signerID int64, embeddedKey bool) (*signedRequest, *problem) {
+	if ct := r.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/jose+json") {
+		return nil, &problem{Type: errMalformed, Detail: "content type must be application/jose+json", Status: http.StatusUnsupportedMediaType}
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		return nil, malformed("failed to read request body")
+	}
+	jws, err := jose.ParseSigned(string(body))
+	if err != nil {
+		return nil, malformed("failed to parse JWS: " + err.Error())
+	}
+	if len(jws.Signatures) != 1 {
+		return nil, malformed("JWS must have exactly one signature")
+	}
+	protected := jws.Signatures[0].Protected
+	if !allowedAlgorithms[protected.Algorithm] {
+		return nil, &problem{Type: errBadSignatureAlgorithm, Detail: "unsupported JWS algorithm " + protected.Algorithm, Status: http.StatusBadRequest}
+	}
+	if !h.nonces.consume(protected.Nonce) {
+		return nil, &problem{Type: errBadNonce, Detail: "invalid or reused nonce", Status: http.StatusBadRequest}
+	}
+	if url, _ := protected.ExtraHeaders["url"].(string); url != h.root(r)+r.URL.Path {
+		return nil, unauthorized("JWS url header does not match request URL")
+	}
+
+	req := &signedRequest{}
+	var key any
+	switch {
+	case embeddedKey:
+		if protected.JSONWebKey == nil || protected.KeyID != "" {
+			return nil, malformed("JWS must contain a jwk and no kid")
+		}
+		if !protected.JSONWebKey.Valid() || !protected.JSONWebKey.IsPublic() {
+			return nil, malformed("JWS jwk must be a valid public key")
+		}
+		req.jwk = protected.JSONWebKey
+		key = protected.JSONWebKey
+	default:
+		if protected.JSONWebKey != nil || protected.KeyID == "" {
+			return nil, malformed("JWS must contain a kid and no jwk")
+		}
+		prefix := h.base(r, signerID) + "/account/"
+		if !strings.HasPrefix(protected.KeyID, prefix) {
+			return nil, &problem{Type: errAccountDoesNotExist, Detail: "unknown account", Status: http.StatusBadRequest}
+		}
+		acct := h.store.getAccount(signerID, strings.TrimPrefix(protected.KeyID, prefix))
+		if acct == nil {
+			return nil, &problem{Type: errAccountDoesNotExist, Detail: "unknown account", Status: http.StatusBadRequest}
+		}
+		if acct.Status != statusValid {
+			return nil, unauthorized("account is " + acct.Status)
+		}
+		req.account = acct
+		key = acct.key
+	}
+	payload, err := jws.Verify(key)
+	if err != nil {
+		return nil, malformed("JWS signature is invalid")
+	}
+	req.payload = payload
+	return req, nil
+}
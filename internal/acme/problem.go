@@ -0,0 +1,70 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package acme
+
+import "net/http"
+
+const (
+	errPrefix                = "urn:ietf:params:acme:error:"
+	errAccountDoesNotExist   = errPrefix + "accountDoesNotExist"
+	errBadCSR                = errPrefix + "badCSR"
+	errBadNonce              = errPrefix + "badNonce"
+	errBadSignatureAlgorithm = errPrefix + "badSignatureAlgorithm"
+	errConnection            = errPrefix + "connection"
+	errDNS                   = errPrefix + "dns"
+	errIncorrectResponse     = errPrefix + "incorrectResponse"
+	errMalformed             = errPrefix + "malformed"
+	errOrderNotReady         = errPrefix + "orderNotReady"
+	errRateLimited           = errPrefix + "rateLimited"
+	errRejectedIdentifier    = errPrefix + "rejectedIdentifier"
+	errServerInternal        = errPrefix + "serverInternal"
+	errTLS                   = errPrefix + "tls"
+	errUnauthorized          = errPrefix + "unauthorized"
+	errUnsupportedIdentifier = errPrefix + "unsupportedIdentifier"
+)
+
+// problem is an RFC 7807 problem document, as used by ACME for errors.
+type problem struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail,omitempty"`
+	Status int    `json:"status,omitempty"`
+}
+
+func (p *problem) Error() string {
+	return p.Type + ": " + p.Detail
+}
+
+func malformed(detail string) *problem {
+	return &problem{Type: errMalformed, Detail: detail, Status: http.StatusBadRequest}
+}
+
+func unauthorized(detail string) *problem {
+	return &problem{Type: errUnauthorized, Detail: detail, Status: http.StatusForbidden}
+}
+
+func notFound(detail string) *problem {
+	return &problem{Type: errMalformed, Detail: detail, Status: http.StatusNotFound}
+}
+
+func rateLimited(detail string) *problem {
+	return &problem{Type: errRateLimited, Detail: detail, Status: http.StatusTooManyRequests}
+}
+
+func serverInternal(detail string) *problem {
+	return &problem{Type: errServerInternal, Detail: detail, Status: http.StatusInternalServerError}
+}
@@ -0,0 +1,427 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package acme
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+)
+
+const (
+	statusPending     = "pending"
+	statusProcessing  = "processing"
+	statusReady       = "ready"
+	statusValid       = "valid"
+	statusInvalid     = "invalid"
+	statusDeactivated = "deactivated"
+
+	challengeHTTP01    = "http-01"
+	challengeDNS01     = "dns-01"
+	challengeTLSALPN01 = "tls-alpn-01"
+
+	orderLifetime = 24 * time.Hour
+
+	// bound the memory an ACME client can make the server hold
+	maxAccounts         = 10000
+	maxOrdersPerAccount = 300
+	maxOrderIdentifiers = 100
+)
+
+type identifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type account struct {
+	id         string
+	signerID   int64
+	key        *jose.JSONWebKey
+	thumbprint string
+
+	Status  string   `json:"status"`
+	Contact []string `json:"contact,omitempty"`
+	Orders  string   `json:"orders,omitempty"`
+}
+
+type order struct {
+	id        string
+	accountID string
+	signerID  int64
+	authzIDs  []string
+	certID    string
+	duration  time.Duration
+
+	Status         string       `json:"status"`
+	Expires        time.Time    `json:"expires"`
+	Identifiers    []identifier `json:"identifiers"`
+	NotBefore      *time.Time   `json:"notBefore,omitempty"`
+	NotAfter       *time.Time   `json:"notAfter,omitempty"`
+	Error          *problem     `json:"error,omitempty"`
+	Authorizations []string     `json:"authorizations"`
+	Finalize       string       `json:"finalize"`
+	Certificate    string       `json:"certificate,omitempty"`
+}
+
+type authorization struct {
+	id        string
+	accountID string
+	signerID  int64
+
+	Identifier identifier   `json:"identifier"`
+	Status     string       `json:"status"`
+	Expires    time.Time    `json:"expires"`
+	Challenges []*challenge `json:"challenges"`
+	Wildcard   bool         `json:"wildcard,omitempty"`
+}
+
+type challenge struct {
+	id      string
+	authzID string
+
+	Type      string     `json:"type"`
+	URL       string     `json:"url"`
+	Status    string     `json:"status"`
+	Token     string     `json:"token"`
+	Validated *time.Time `json:"validated,omitempty"`
+	Error     *problem   `json:"error,omitempty"`
+}
+
+type certificate struct {
+	accountID string
+	chain     []*x509.Certificate
+}
+
+// store holds ACME state in memory. Objects are only ever modified while
+// holding lock, and copies are returned to handlers. Orders are forgotten,
+// along with their authorizations, challenges and certificate, once they
+// expire.
+type store struct {
+	lock           sync.Mutex
+	accounts       map[string]*account
+	accountsByKey  map[string]*account
+	orders         map[string]*order
+	authorizations map[string]*authorization
+	challenges     map[string]*challenge
+	certificates   map[string]*certificate
+	// IDs of orders in the order they expire
+	expiring []string
+	// number of unexpired orders of each account
+	accountOrders map[string]int
+
+	maxAccounts         int
+	maxOrdersPerAccount int
+}
+
+func newStore() *store {
+	return &store{
+		accounts:            make(map[string]*account),
+		accountsByKey:       make(map[string]*account),
+		orders:              make(map[string]*order),
+		authorizations:      make(map[string]*authorization),
+		challenges:          make(map[string]*challenge),
+		certificates:        make(map[string]*certificate),
+		accountOrders:       make(map[string]int),
+		maxAccounts:         maxAccounts,
+		maxOrdersPerAccount: maxOrdersPerAccount,
+	}
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func accountKey(signerID int64, thumbprint string) string {
+	return strconv.FormatInt(signerID, 10) + "/" + thumbprint
+}
+
+// getAccount returns a copy of the account with the given ID.
+func (s *store) getAccount(signerID int64, id string) *account {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	acct, found := s.accounts[id]
+	if !found || acct.signerID != signerID {
+		return nil
+	}
+	cp := *acct
+	return &cp
+}
+
+// getOrCreateAccount returns the account for a key, creating it with acct's
+// values if it does not exist yet and there is room for it.
+func (s *store) getOrCreateAccount(acct *account, create bool) (*account, bool, *problem) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if existing, found := s.accountsByKey[accountKey(acct.signerID, acct.thumbprint)]; found {
+		cp := *existing
+		return &cp, false, nil
+	}
+	if !create {
+		return nil, false, nil
+	}
+	if len(s.accounts) >= s.maxAccounts {
+		return nil, false, rateLimited("too many ACME accounts")
+	}
+	acct.id = newID()
+	s.accounts[acct.id] = acct
+	s.accountsByKey[accountKey(acct.signerID, acct.thumbprint)] = acct
+	cp := *acct
+	return &cp, true, nil
+}
+
+func (s *store) updateAccount(id string, update func(*account)) *account {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	acct, found := s.accounts[id]
+	if !found {
+		return nil
+	}
+	update(acct)
+	cp := *acct
+	return &cp
+}
+
+// createOrder stores a new order and one pending authorization per
+// identifier, unless its account has too many unexpired orders.
+func (s *store) createOrder(o *order, challengeTypes func(identifier) []string) (*order, *problem) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.expireOrders(time.Now())
+	if s.accountOrders[o.accountID] >= s.maxOrdersPerAccount {
+		return nil, rateLimited("too many unexpired orders for this account")
+	}
+	o.id = newID()
+	for _, ident := range o.Identifiers {
+		authz := &authorization{
+			id:         newID(),
+			accountID:  o.accountID,
+			signerID:   o.signerID,
+			Identifier: ident,
+			Status:     statusPending,
+			Expires:    o.Expires,
+		}
+		if len(ident.Value) > 2 && ident.Value[:2] == "*." {
+			authz.Identifier.Value = ident.Value[2:]
+			authz.Wildcard = true
+		}
+		token := newID()
+		for _, typ := range challengeTypes(ident) {
+			ch := &challenge{
+				id:      newID(),
+				authzID: authz.id,
+				Type:    typ,
+				Status:  statusPending,
+				Token:   token,
+			}
+			s.challenges[ch.id] = ch
+			authz.Challenges = append(authz.Challenges, ch)
+		}
+		s.authorizations[authz.id] = authz
+		o.authzIDs = append(o.authzIDs, authz.id)
+	}
+	s.orders[o.id] = o
+	s.expiring = append(s.expiring, o.id)
+	s.accountOrders[o.accountID]++
+	return s.copyOrder(o), nil
+}
+
+// expireOrders forgets the orders that expired before now. Orders all live
+// for orderLifetime, so they expire in the order they were created. Must be
+// called with lock held.
+func (s *store) expireOrders(now time.Time) {
+	for len(s.expiring) > 0 {
+		o := s.orders[s.expiring[0]]
+		if !now.After(o.Expires) {
+			return
+		}
+		for _, authzID := range o.authzIDs {
+			for _, ch := range s.authorizations[authzID].Challenges {
+				delete(s.challenges, ch.id)
+			}
+			delete(s.authorizations, authzID)
+		}
+		delete(s.certificates, o.certID)
+		delete(s.orders, o.id)
+		if s.accountOrders[o.accountID]--; s.accountOrders[o.accountID] == 0 {
+			delete(s.accountOrders, o.accountID)
+		}
+		s.expiring = s.expiring[1:]
+	}
+}
+
+func (s *store) getOrder(id string) *order {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	o, found := s.orders[id]
+	if !found {
+		return nil
+	}
+	s.refreshOrder(o)
+	return s.copyOrder(o)
+}
+
+// listOrders returns the IDs of an account's pending, ready and processing
+// orders.
+func (s *store) listOrders(accountID string) []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var ids []string
+	for id, o := range s.orders {
+		if o.accountID != accountID {
+			continue
+		}
+		s.refreshOrder(o)
+		if o.Status == statusPending || o.Status == statusReady || o.Status == statusProcessing {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (s *store) updateOrder(id string, update func(*order)) *order {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	o, found := s.orders[id]
+	if !found {
+		return nil
+	}
+	update(o)
+	return s.copyOrder(o)
+}
+
+// refreshOrder updates the status of a pending order from its
+// authorizations. Must be called with lock held.
+func (s *store) refreshOrder(o *order) {
+	if o.Status != statusPending && o.Status != statusReady {
+		return
+	}
+	if time.Now().After(o.Expires) {
+		o.Status = statusInvalid
+		return
+	}
+	ready := true
+	for _, id := range o.authzIDs {
+		switch s.authorizations[id].Status {
+		case statusValid:
+		case statusPending:
+			ready = false
+		default:
+			o.Status = statusInvalid
+			return
+		}
+	}
+	if ready {
+		o.Status = statusReady
+	}
+}
+
+func (s *store) copyOrder(o *order) *order {
+	cp := *o
+	cp.Identifiers = append([]identifier(nil), o.Identifiers...)
+	cp.authzIDs = append([]string(nil), o.authzIDs...)
+	return &cp
+}
+
+func (s *store) getAuthorization(id string) *authorization {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	authz, found := s.authorizations[id]
+	if !found {
+		return nil
+	}
+	return copyAuthorization(authz)
+}
+
+func copyAuthorization(authz *authorization) *authorization {
+	cp := *authz
+	cp.Challenges = nil
+	for _, ch := range authz.Challenges {
+		chcp := *ch
+		cp.Challenges = append(cp.Challenges, &chcp)
+	}
+	return &cp
+}
+
+// getChallenge returns copies of a challenge and its authorization.
+func (s *store) getChallenge(id string) (*challenge, *authorization) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ch, found := s.challenges[id]
+	if !found {
+		return nil, nil
+	}
+	cp := *ch
+	return &cp, copyAuthorization(s.authorizations[ch.authzID])
+}
+
+// startChallenge marks a pending challenge as processing, returning false if
+// it was not pending.
+func (s *store) startChallenge(id string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ch := s.challenges[id]
+	if ch.Status != statusPending || s.authorizations[ch.authzID].Status != statusPending {
+		return false
+	}
+	ch.Status = statusProcessing
+	return true
+}
+
+// completeChallenge records the result of validating a challenge, and
+// updates its authorization accordingly.
+func (s *store) completeChallenge(id string, p *problem) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ch := s.challenges[id]
+	authz := s.authorizations[ch.authzID]
+	if p != nil {
+		ch.Status = statusInvalid
+		ch.Error = p
+		authz.Status = statusInvalid
+		return
+	}
+	now := time.Now()
+	ch.Status = statusValid
+	ch.Validated = &now
+	authz.Status = statusValid
+}
+
+func (s *store) createCertificate(cert *certificate) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	id := newID()
+	s.certificates[id] = cert
+	return id
+}
+
+func (s *store) getCertificate(id string) *certificate {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.certificates[id]
+}
@@ -0,0 +1,116 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package acme
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestOrder(accountID string, expires time.Time) *order {
+	return &order{
+		accountID:   accountID,
+		signerID:    1,
+		Status:      statusPending,
+		Expires:     expires,
+		Identifiers: []identifier{{Type: "dns", Value: "example.com"}},
+	}
+}
+
+func httpChallenges(identifier) []string { return []string{challengeHTTP01} }
+
+func TestStoreExpiresOrders(t *testing.T) {
+	s := newStore()
+	expired, p := s.createOrder(newTestOrder("a", time.Now().Add(-time.Second)), httpChallenges)
+	if p != nil {
+		t.Fatal(p)
+	}
+	certID := s.createCertificate(&certificate{accountID: "a"})
+	s.updateOrder(expired.id, func(o *order) {
+		o.Status = statusValid
+		o.certID = certID
+	})
+	live, p := s.createOrder(newTestOrder("a", time.Now().Add(orderLifetime)), httpChallenges)
+	if p != nil {
+		t.Fatal(p)
+	}
+
+	if s.getOrder(expired.id) != nil {
+		t.Error("expired order was not forgotten")
+	}
+	if s.getOrder(live.id) == nil {
+		t.Error("unexpired order was forgotten")
+	}
+	if len(s.authorizations) != 1 || len(s.challenges) != 1 || len(s.certificates) != 0 {
+		t.Errorf("store holds %d authorizations, %d challenges and %d certificates, want 1, 1 and 0",
+			len(s.authorizations), len(s.challenges), len(s.certificates))
+	}
+	if s.accountOrders["a"] != 1 {
+		t.Errorf("account has %d unexpired orders, want 1", s.accountOrders["a"])
+	}
+}
+
+func TestStoreLimits(t *testing.T) {
+	s := newStore()
+	s.maxAccounts = 1
+	s.maxOrdersPerAccount = 1
+	newAccount := func(thumbprint string) (*account, *problem) {
+		acct, _, p := s.getOrCreateAccount(&account{signerID: 1, thumbprint: thumbprint, Status: statusValid}, true)
+		return acct, p
+	}
+
+	first, p := newAccount("first")
+	if p != nil {
+		t.Fatal(p)
+	}
+	if _, p := newAccount("second"); p == nil || p.Type != errRateLimited {
+		t.Errorf("creating an account over the limit returned %v, want rateLimited", p)
+	}
+	if acct, p := newAccount("first"); p != nil || acct.id != first.id {
+		t.Errorf("existing account was not returned over the limit: %v", p)
+	}
+
+	if _, p := s.createOrder(newTestOrder(first.id, time.Now().Add(orderLifetime)), httpChallenges); p != nil {
+		t.Fatal(p)
+	}
+	if _, p := s.createOrder(newTestOrder(first.id, time.Now().Add(orderLifetime)), httpChallenges); p == nil || p.Type != errRateLimited {
+		t.Errorf("creating an order over the limit returned %v, want rateLimited", p)
+	}
+}
+
+func TestNoncesLimit(t *testing.T) {
+	n := newNonces()
+	n.max = 2
+	oldest := n.issue()
+	second := n.issue()
+	third := n.issue()
+	if n.consume(oldest) {
+		t.Error("oldest nonce was accepted after the limit was reached")
+	}
+	for _, nonce := range []string{second, third} {
+		if !n.consume(nonce) {
+			t.Errorf("nonce %s was not accepted", nonce)
+		}
+		if n.consume(nonce) {
+			t.Errorf("nonce %s was accepted twice", nonce)
+		}
+	}
+	if len(n.queue) > n.max {
+		t.Errorf("%d nonces are queued, want at most %d", len(n.queue), n.max)
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package authn
+
+import "crypto/subtle"
+
+// BasicAuthFunc checks HTTP basic credentials, returning the identity of the
+// caller if they are valid.
+type BasicAuthFunc func(username, password string) (string, bool)
+
+// StaticBasicAuth accepts a single username and password. Callers are
+// identified by the username.
+func StaticBasicAuth(username, password string) BasicAuthFunc {
+	return func(u, p string) (string, bool) {
+		userOK := subtle.ConstantTimeCompare([]byte(u), []byte(username)) == 1
+		passOK := subtle.ConstantTimeCompare([]byte(p), []byte(password)) == 1
+		if !userOK || !passOK {
+			return "", false
+		}
+		return username, true
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package authn
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/bundle/jwtbundle"
+	"github.com/spiffe/go-spiffe/v2/federation"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"go.uber.org/zap"
+)
+
+const defaultBundleRefreshInterval = 5 * time.Minute
+
+// JWTBundleSource is a jwtbundle.Source for a single trust domain that is
+// periodically reloaded, keeping the last good bundle if a reload fails.
+type JWTBundleSource struct {
+	trustDomain spiffeid.TrustDomain
+	load        func(context.Context) (*jwtbundle.Bundle, error)
+	log         *zap.Logger
+	current     atomic.Value // *jwtbundle.Bundle
+	stop        chan struct{}
+}
+
+// NewFileJWTBundleSource loads a trust domain's JWT bundle from a JWKS file,
+// reloading it every refresh (default 5 minutes).
+func NewFileJWTBundleSource(trustDomain spiffeid.TrustDomain, path string, refresh time.Duration, log *zap.Logger) (*JWTBundleSource, error) {
+	return newJWTBundleSource(trustDomain, func(context.Context) (*jwtbundle.Bundle, error) {
+		return jwtbundle.Load(trustDomain, path)
+	}, refresh, log)
+}
+
+// NewEndpointJWTBundleSource fetches a trust domain's JWT bundle from a
+// SPIFFE bundle endpoint using the https_web profile, refetching it every
+// refresh (default 5 minutes).
+func NewEndpointJWTBundleSource(trustDomain spiffeid.TrustDomain, url string, refresh time.Duration, log *zap.Logger) (*JWTBundleSource, error) {
+	return newJWTBundleSource(trustDomain, func(ctx context.Context) (*jwtbundle.Bundle, error) {
+		bundle, err := federation.FetchBundle(ctx, trustDomain, url)
+		if err != nil {
+			return nil, err
+		}
+		return bundle.JWTBundle(), nil
+	}, refresh, log)
+}
+
+func newJWTBundleSource(trustDomain spiffeid.TrustDomain, load func(context.Context) (*jwtbundle.Bundle, error), refresh time.Duration, log *zap.Logger) (*JWTBundleSource, error) {
+	if refresh <= 0 {
+		refresh = defaultBundleRefreshInterval
+	}
+	s := &JWTBundleSource{
+		trustDomain: trustDomain,
+		load:        load,
+		log:         log,
+		stop:        make(chan struct{}),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), refresh)
+	defer cancel()
+	bundle, err := load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWT bundle for %q: %w", trustDomain, err)
+	}
+	s.current.Store(bundle)
+	go s.refresh(refresh)
+	return s, nil
+}
+
+func (s *JWTBundleSource) refresh(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		bundle, err := s.load(ctx)
+		cancel()
+		if err != nil {
+			s.log.Warn("failed to refresh JWT bundle", zap.String("trust_domain", s.trustDomain.String()), zap.Error(err))
+			continue
+		}
+		if !bundle.Equal(s.current.Load().(*jwtbundle.Bundle)) {
+			s.log.Info("JWT bundle changed", zap.String("trust_domain", s.trustDomain.String()))
+			s.current.Store(bundle)
+		}
+	}
+}
+
+// GetJWTBundleForTrustDomain implements jwtbundle.Source.
+func (s *JWTBundleSource) GetJWTBundleForTrustDomain(trustDomain spiffeid.TrustDomain) (*jwtbundle.Bundle, error) {
+	if trustDomain != s.trustDomain {
+		return nil, fmt.Errorf("no JWT bundle for trust domain %q", trustDomain)
+	}
+	return s.current.Load().(*jwtbundle.Bundle), nil
+}
+
+// Close stops refreshing the bundle.
+func (s *JWTBundleSource) Close() error {
+	close(s.stop)
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package authn
+
+import (
+	"crypto/x509"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+)
+
+// MatchPathPrefix accepts IDs in a trust domain whose path is prefix or
+// below it, e.g. /ns/edge accepts /ns/edge/sa/radio but not /ns/edge2.
+func MatchPathPrefix(td spiffeid.TrustDomain, prefix string) spiffeid.Matcher {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return func(id spiffeid.ID) error {
+		if id.MemberOf(td) && (id.Path() == prefix || strings.HasPrefix(id.Path(), prefix+"/")) {
+			return nil
+		}
+		return fmt.Errorf("unexpected ID %q", id)
+	}
+}
+
+// MatchGlob accepts IDs matching a path.Match pattern, such as
+// spiffe://edge/ns/*/sa/*. Wildcards do not match across path segments.
+func MatchGlob(pattern string) (spiffeid.Matcher, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return func(id spiffeid.ID) error {
+		if ok, _ := path.Match(pattern, id.String()); ok {
+			return nil
+		}
+		return fmt.Errorf("unexpected ID %q", id)
+	}, nil
+}
+
+// MatchAnyOf accepts IDs accepted by any of matchers.
+func MatchAnyOf(matchers ...spiffeid.Matcher) spiffeid.Matcher {
+	return func(id spiffeid.ID) error {
+		for _, match := range matchers {
+			if match(id) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("unexpected ID %q", id)
+	}
+}
+
+// CertificateMatcher decides whether a verified client certificate is
+// allowed.
+type CertificateMatcher func(cert *x509.Certificate) error
+
+// MatchSPIFFEID accepts X.509-SVIDs with a SPIFFE ID accepted by matcher.
+func MatchSPIFFEID(matcher spiffeid.Matcher) CertificateMatcher {
+	return func(cert *x509.Certificate) error {
+		id, err := x509svid.IDFromCert(cert)
+		if err != nil {
+			return err
+		}
+		return matcher(id)
+	}
+}
+
+// MatchCommonName accepts certificates with a subject common name matching
+// a path.Match pattern.
+func MatchCommonName(pattern string) (CertificateMatcher, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return func(cert *x509.Certificate) error {
+		if ok, _ := path.Match(pattern, cert.Subject.CommonName); ok {
+			return nil
+		}
+		return fmt.Errorf("unexpected common name %q", cert.Subject.CommonName)
+	}, nil
+}
+
+// MatchAnyCertificate accepts certificates accepted by any of matchers.
+func MatchAnyCertificate(matchers ...CertificateMatcher) CertificateMatcher {
+	return func(cert *x509.Certificate) error {
+		for _, match := range matchers {
+			if match(cert) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("certificate %q is not allowed", X509Identity(cert))
+	}
+}
+
+// ParseCertificateMatcher parses a comma separated allow-list of SPIFFE ID
+// globs (spiffe://...) and common name globs.
+func ParseCertificateMatcher(list string) (CertificateMatcher, error) {
+	var matchers []CertificateMatcher
+	for _, pattern := range strings.Split(list, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if strings.HasPrefix(pattern, "spiffe://") {
+			glob, err := MatchGlob(pattern)
+			if err != nil {
+				return nil, err
+			}
+			matchers = append(matchers, MatchSPIFFEID(glob))
+			continue
+		}
+		cn, err := MatchCommonName(pattern)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, cn)
+	}
+	return MatchAnyCertificate(matchers...), nil
+}
+
+// ParseIDMatcher parses a comma separated allow-list of SPIFFE IDs. IDs with
+// glob characters are matched by MatchGlob, others by MatchPathPrefix, so
+// spiffe://edge accepts every ID in the trust domain.
+func ParseIDMatcher(list string) (spiffeid.Matcher, error) {
+	var matchers []spiffeid.Matcher
+	for _, pattern := range strings.Split(list, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if strings.ContainsAny(pattern, `*?[\`) {
+			glob, err := MatchGlob(pattern)
+			if err != nil {
+				return nil, err
+			}
+			matchers = append(matchers, glob)
+			continue
+		}
+		id, err := spiffeid.FromString(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SPIFFE ID %q: %w", pattern, err)
+		}
+		matchers = append(matchers, MatchPathPrefix(id.TrustDomain(), id.Path()))
+	}
+	return MatchAnyOf(matchers...), nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package authn
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+)
+
+func TestParseIDMatcher(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		list    string
+		id      string
+		allowed bool
+	}{
+		{"trust domain", "spiffe://edge", "spiffe://edge/ns/team/sa/web", true},
+		{"other trust domain", "spiffe://edge", "spiffe://other/ns/team/sa/web", false},
+		{"path prefix", "spiffe://edge/ns/team", "spiffe://edge/ns/team/sa/web", true},
+		{"path prefix itself", "spiffe://edge/ns/team", "spiffe://edge/ns/team", true},
+		{"path prefix without segment boundary", "spiffe://edge/ns/team", "spiffe://edge/ns/team2/sa/web", false},
+		{"glob", "spiffe://edge/ns/*/sa/*", "spiffe://edge/ns/team/sa/web", true},
+		{"glob across segments", "spiffe://edge/ns/*/sa/*", "spiffe://edge/ns/team/sa/web/extra", false},
+		{"glob in another trust domain", "spiffe://edge/ns/*/sa/*", "spiffe://other/ns/team/sa/web", false},
+		{"any of list", "spiffe://other, spiffe://edge/ns/*/sa/web", "spiffe://edge/ns/team/sa/web", true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := ParseIDMatcher(tt.list)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := match(spiffeid.RequireFromString(tt.id)); (err == nil) != tt.allowed {
+				t.Errorf("match(%q) = %v, want allowed %v", tt.id, err, tt.allowed)
+			}
+		})
+	}
+	for _, list := range []string{"edge", "spiffe://edge/ns/[", "https://edge/ns"} {
+		if _, err := ParseIDMatcher(list); err == nil {
+			t.Errorf("ParseIDMatcher(%q) accepted an invalid list", list)
+		}
+	}
+}
+
+func TestParseCertificateMatcher(t *testing.T) {
+	svid := func(id string) *x509.Certificate {
+		return &x509.Certificate{URIs: []*url.URL{{Scheme: "spiffe", Host: "edge", Path: id}}}
+	}
+	match, err := ParseCertificateMatcher("spiffe://edge/ns/*/sa/*, radio-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name    string
+		cert    *x509.Certificate
+		allowed bool
+	}{
+		{"SPIFFE ID", svid("/ns/team/sa/web"), true},
+		{"SPIFFE ID outside glob", svid("/ns/team/sa/web/extra"), false},
+		{"common name", &x509.Certificate{Subject: pkix.Name{CommonName: "radio-7"}}, true},
+		{"other common name", &x509.Certificate{Subject: pkix.Name{CommonName: "laptop-7"}}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := match(tt.cert); (err == nil) != tt.allowed {
+				t.Errorf("match() = %v, want allowed %v", err, tt.allowed)
+			}
+		})
+	}
+}
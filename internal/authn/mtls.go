@@ -0,0 +1,86 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package authn
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net/http"
+
+	"github.com/bufbuild/connect-go"
+)
+
+type peerCertificateKey struct{}
+
+// ChainVerifier accepts verified client certificate chains that may
+// identify the caller.
+type ChainVerifier func(chain []*x509.Certificate) error
+
+// ClientCertificateMiddleware identifies requests made with a verified TLS
+// client certificate by X509Identity, if one of its chains is accepted by
+// trusted, and makes the certificate available to
+// ClientCertificateInterceptor.
+func ClientCertificateMiddleware(next http.Handler, trusted ChainVerifier) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil {
+			for _, chain := range r.TLS.VerifiedChains {
+				if trusted(chain) != nil {
+					continue
+				}
+				cert := chain[0]
+				ctx := context.WithValue(r.Context(), peerCertificateKey{}, cert)
+				r = r.WithContext(WithIdentity(ctx, X509Identity(cert)))
+				break
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// PeerCertificateFromContext returns the verified client certificate set by
+// ClientCertificateMiddleware, if any.
+func PeerCertificateFromContext(ctx context.Context) (*x509.Certificate, bool) {
+	cert, ok := ctx.Value(peerCertificateKey{}).(*x509.Certificate)
+	return cert, ok
+}
+
+// ClientCertificateInterceptor requires callers to present a verified TLS
+// client certificate accepted by allowed. It must be used behind
+// ClientCertificateMiddleware.
+func ClientCertificateInterceptor(allowed CertificateMatcher) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return connect.UnaryFunc(func(
+			ctx context.Context,
+			req connect.AnyRequest,
+		) (connect.AnyResponse, error) {
+			cert, ok := PeerCertificateFromContext(ctx)
+			if !ok {
+				return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("client certificate required"))
+			}
+			if err := allowed(cert); err != nil {
+				return nil, connect.NewError(connect.CodePermissionDenied, errors.New("client certificate not allowed"))
+			}
+			res, err := next(WithIdentity(ctx, X509Identity(cert)), req)
+			if err != nil {
+				return nil, err
+			}
+			return res, nil
+		})
+	}
+}
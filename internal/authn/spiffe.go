@@ -19,31 +19,29 @@ package authn
 
 import (
 	"context"
-	"errors"
+	"strings"
 
-	"github.com/bufbuild/connect-go"
+	"github.com/spiffe/go-spiffe/v2/bundle/jwtbundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"github.com/spiffe/go-spiffe/v2/svid/jwtsvid"
 )
 
-func SpiffeJWTInterceptor(allowed string) connect.UnaryInterceptorFunc {
-	return func(next connect.UnaryFunc) connect.UnaryFunc {
-		return connect.UnaryFunc(func(
-			ctx context.Context,
-			req connect.AnyRequest,
-		) (connect.AnyResponse, error) {
-			authToken := req.Header().Get("Authorization")
-			svid, err := jwtsvid.ParseInsecure(authToken, []string{"northfoot"})
-			if err != nil {
-				return nil, connect.NewError(connect.CodePermissionDenied, errors.New("invalid svid"))
-			}
-			if svid.ID.String() != allowed {
-				return nil, connect.NewError(connect.CodePermissionDenied, errors.New("invalid svid"))
-			}
-			res, err := next(ctx, req)
-			if err != nil {
-				return nil, err
-			}
-			return res, nil
-		})
+// SpiffeJWT accepts callers with a JWT-SVID bearer token, as its SPIFFE ID.
+// The token must be signed by a key in bundles, be intended for one of
+// audience, and carry a SPIFFE ID accepted by allowed.
+func SpiffeJWT(bundles jwtbundle.Source, audience []string, allowed spiffeid.Matcher) Authenticator {
+	return func(_ context.Context, authorization string) (string, error) {
+		token, ok := strings.CutPrefix(authorization, "Bearer ")
+		if !ok {
+			return "", errInvalidCredentials
+		}
+		svid, err := jwtsvid.ParseAndValidate(token, bundles, audience)
+		if err != nil {
+			return "", err
+		}
+		if err := allowed(svid.ID); err != nil {
+			return "", err
+		}
+		return svid.ID.String(), nil
 	}
 }
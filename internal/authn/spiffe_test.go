@@ -0,0 +1,124 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package authn
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"go.uber.org/zap"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// newTestJWTBundle writes a JWKS file with the public key of key for the
+// edge trust domain and returns a source loading it.
+func newTestJWTBundle(t *testing.T, key *ecdsa.PrivateKey) *JWTBundleSource {
+	t.Helper()
+	jwks, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &key.PublicKey, KeyID: "edge-1", Use: "jwt-svid"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "bundle.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	bundles, err := NewFileJWTBundleSource(spiffeid.RequireTrustDomainFromString("edge"), path, 0, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { bundles.Close() })
+	return bundles
+}
+
+// newTestJWTSVID returns a JWT-SVID for subject signed by key.
+func newTestJWTSVID(t *testing.T, key *ecdsa.PrivateKey, subject string, audience []string, expiry time.Time) string {
+	t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: jose.ES256,
+		Key:       jose.JSONWebKey{Key: key, KeyID: "edge-1"},
+	}, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Signed(signer).Claims(jwt.Claims{
+		Subject:  subject,
+		Audience: audience,
+		Expiry:   jwt.NewNumericDate(expiry),
+	}).CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestSpiffeJWT(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forger, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowed, err := MatchGlob("spiffe://edge/ns/*/sa/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticate := SpiffeJWT(newTestJWTBundle(t, key), []string{"northfoot"}, allowed)
+	valid := time.Now().Add(time.Hour)
+	for _, tt := range []struct {
+		name          string
+		authorization string
+		wantIdentity  string
+	}{
+		{"valid", "Bearer " + newTestJWTSVID(t, key, "spiffe://edge/ns/team/sa/web", []string{"northfoot"}, valid), "spiffe://edge/ns/team/sa/web"},
+		{"one of several audiences", "Bearer " + newTestJWTSVID(t, key, "spiffe://edge/ns/team/sa/web", []string{"other", "northfoot"}, valid), "spiffe://edge/ns/team/sa/web"},
+		{"forged signature", "Bearer " + newTestJWTSVID(t, forger, "spiffe://edge/ns/team/sa/web", []string{"northfoot"}, valid), ""},
+		{"wrong audience", "Bearer " + newTestJWTSVID(t, key, "spiffe://edge/ns/team/sa/web", []string{"other"}, valid), ""},
+		{"expired", "Bearer " + newTestJWTSVID(t, key, "spiffe://edge/ns/team/sa/web", []string{"northfoot"}, time.Now().Add(-time.Hour)), ""},
+		{"ID outside glob", "Bearer " + newTestJWTSVID(t, key, "spiffe://edge/ns/team/sa/web/extra", []string{"northfoot"}, valid), ""},
+		{"ID in another trust domain", "Bearer " + newTestJWTSVID(t, key, "spiffe://other/ns/team/sa/web", []string{"northfoot"}, valid), ""},
+		{"missing bearer scheme", newTestJWTSVID(t, key, "spiffe://edge/ns/team/sa/web", []string{"northfoot"}, valid), ""},
+		{"malformed token", "Bearer not-a-jwt", ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := authenticate(context.Background(), tt.authorization)
+			if tt.wantIdentity == "" {
+				if err == nil {
+					t.Fatalf("SpiffeJWT() accepted the token as %q", identity)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if identity != tt.wantIdentity {
+				t.Errorf("SpiffeJWT() identity = %q, want %q", identity, tt.wantIdentity)
+			}
+		})
+	}
+}
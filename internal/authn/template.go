@@ -0,0 +1,105 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package authn
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var templateVariable = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// IdentityTemplate matches identities such as spiffe://edge/ns/{ns}/sa/{sa},
+// capturing the named variables, each of which matches within a single path
+// segment.
+type IdentityTemplate struct {
+	re    *regexp.Regexp
+	names []string
+}
+
+// ParseIdentityTemplate parses an identity template. Each variable may only
+// appear once.
+func ParseIdentityTemplate(template string) (*IdentityTemplate, error) {
+	var (
+		expr  strings.Builder
+		names []string
+		last  int
+	)
+	expr.WriteString("^")
+	for _, loc := range templateVariable.FindAllStringSubmatchIndex(template, -1) {
+		name := template[loc[2]:loc[3]]
+		for _, seen := range names {
+			if seen == name {
+				return nil, fmt.Errorf("variable {%s} appears more than once in %q", name, template)
+			}
+		}
+		names = append(names, name)
+		expr.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		expr.WriteString("([^/]+)")
+		last = loc[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(template[last:]))
+	expr.WriteString("$")
+	if strings.ContainsAny(templateVariable.ReplaceAllString(template, ""), "{}") {
+		return nil, fmt.Errorf("invalid variable in %q", template)
+	}
+	return &IdentityTemplate{re: regexp.MustCompile(expr.String()), names: names}, nil
+}
+
+// Match returns the variables captured from identity, if it matches.
+func (t *IdentityTemplate) Match(identity string) (map[string]string, bool) {
+	m := t.re.FindStringSubmatch(identity)
+	if m == nil {
+		return nil, false
+	}
+	vars := make(map[string]string, len(t.names))
+	for i, name := range t.names {
+		vars[name] = m[i+1]
+	}
+	return vars, true
+}
+
+// Names returns the variables captured by the template.
+func (t *IdentityTemplate) Names() []string {
+	return t.names
+}
+
+// TemplateVariables returns the variables referenced by a template such as
+// {sa}.{ns}.svc.
+func TemplateVariables(template string) []string {
+	var names []string
+	for _, m := range templateVariable.FindAllStringSubmatch(template, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// ExpandTemplate substitutes vars into template, failing if it references
+// a variable that is not set.
+func ExpandTemplate(template string, vars map[string]string) (string, error) {
+	var err error
+	expanded := templateVariable.ReplaceAllStringFunc(template, func(v string) string {
+		value, ok := vars[v[1:len(v)-1]]
+		if !ok {
+			err = fmt.Errorf("variable %s is not set", v)
+		}
+		return value
+	})
+	return expanded, err
+}
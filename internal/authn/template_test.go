@@ -0,0 +1,82 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package authn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIdentityTemplate(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		template string
+		identity string
+		want     map[string]string
+	}{
+		{"variables", "spiffe://edge/ns/{ns}/sa/{sa}", "spiffe://edge/ns/team/sa/web", map[string]string{"ns": "team", "sa": "web"}},
+		{"no variables", "spiffe://edge/ns/team/sa/web", "spiffe://edge/ns/team/sa/web", map[string]string{}},
+		{"variable within a segment", "spiffe://edge/sa/{sa}-worker", "spiffe://edge/sa/web-worker", map[string]string{"sa": "web"}},
+		{"variable across segments", "spiffe://edge/ns/{ns}/sa/{sa}", "spiffe://edge/ns/team/sa/web/extra", nil},
+		{"empty variable", "spiffe://edge/ns/{ns}/sa/{sa}", "spiffe://edge/ns//sa/web", nil},
+		{"other trust domain", "spiffe://edge/ns/{ns}/sa/{sa}", "spiffe://other/ns/team/sa/web", nil},
+		{"prefix only", "spiffe://edge/ns/{ns}", "spiffe://edge/ns/team/sa/web", nil},
+		{"literal metacharacters", "spiffe://edge/a.b/{sa}", "spiffe://edge/aXb/web", nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := ParseIdentityTemplate(tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vars, ok := template.Match(tt.identity)
+			if ok != (tt.want != nil) {
+				t.Fatalf("Match(%q) matched = %v, want %v", tt.identity, ok, tt.want != nil)
+			}
+			if ok && !reflect.DeepEqual(vars, tt.want) {
+				t.Errorf("Match(%q) = %v, want %v", tt.identity, vars, tt.want)
+			}
+		})
+	}
+	for _, template := range []string{
+		"spiffe://edge/ns/{ns}/sa/{ns}",
+		"spiffe://edge/ns/{ns",
+		"spiffe://edge/ns/{1ns}",
+		"spiffe://edge/ns/{}",
+	} {
+		if _, err := ParseIdentityTemplate(template); err == nil {
+			t.Errorf("ParseIdentityTemplate(%q) accepted an invalid template", template)
+		}
+	}
+}
+
+func TestExpandTemplate(t *testing.T) {
+	vars := map[string]string{"ns": "team", "sa": "web"}
+	expanded, err := ExpandTemplate("{sa}.{ns}.svc", vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expanded != "web.team.svc" {
+		t.Errorf("ExpandTemplate() = %q, want web.team.svc", expanded)
+	}
+	if _, err := ExpandTemplate("{sa}.{cluster}.svc", vars); err == nil {
+		t.Error("ExpandTemplate() accepted an unset variable")
+	}
+	if got := TemplateVariables("{sa}.{ns}.svc"); !reflect.DeepEqual(got, []string{"sa", "ns"}) {
+		t.Errorf("TemplateVariables() = %v, want [sa ns]", got)
+	}
+}
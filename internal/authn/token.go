@@ -19,26 +19,51 @@ package authn
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 
 	"github.com/bufbuild/connect-go"
 )
 
-func StaticTokenInterceptor(token string) connect.UnaryInterceptorFunc {
+// Authenticator returns the identity of a caller from the value of its
+// Authorization header.
+type Authenticator func(ctx context.Context, authorization string) (string, error)
+
+var errInvalidCredentials = errors.New("invalid credentials")
+
+// StaticToken accepts callers whose Authorization header is token, as
+// StaticTokenIdentity.
+func StaticToken(token string) Authenticator {
+	return func(_ context.Context, authorization string) (string, error) {
+		if subtle.ConstantTimeCompare([]byte(authorization), []byte(token)) != 1 {
+			return "", errInvalidCredentials
+		}
+		return StaticTokenIdentity, nil
+	}
+}
+
+// AuthenticationInterceptor identifies callers by the first of
+// authenticators that accepts their Authorization header. Callers without
+// one must already have been identified by ClientCertificateMiddleware.
+func AuthenticationInterceptor(authenticators ...Authenticator) connect.UnaryInterceptorFunc {
 	return func(next connect.UnaryFunc) connect.UnaryFunc {
 		return connect.UnaryFunc(func(
 			ctx context.Context,
 			req connect.AnyRequest,
 		) (connect.AnyResponse, error) {
-			authToken := req.Header().Get("Authorization")
-			if authToken != token {
-				return nil, connect.NewError(connect.CodePermissionDenied, errors.New("invalid token"))
+			authorization := req.Header().Get("Authorization")
+			if authorization == "" {
+				if _, ok := PeerCertificateFromContext(ctx); !ok {
+					return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("credentials required"))
+				}
+				return next(ctx, req)
 			}
-			res, err := next(ctx, req)
-			if err != nil {
-				return nil, err
+			for _, authenticate := range authenticators {
+				if identity, err := authenticate(ctx, authorization); err == nil {
+					return next(WithIdentity(ctx, identity), req)
+				}
 			}
-			return res, nil
+			return nil, connect.NewError(connect.CodeUnauthenticated, errInvalidCredentials)
 		})
 	}
 }
@@ -0,0 +1,77 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package authn
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestAuthenticationInterceptor(t *testing.T) {
+	peer := &x509.Certificate{Subject: pkix.Name{CommonName: "device"}}
+	withPeer := WithIdentity(context.WithValue(context.Background(), peerCertificateKey{}, peer), X509Identity(peer))
+	var identity string
+	call := AuthenticationInterceptor(StaticToken("secret"), StaticToken("Bearer other"))(func(ctx context.Context, _ connect.AnyRequest) (connect.AnyResponse, error) {
+		identity, _ = IdentityFromContext(ctx)
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	})
+	for _, tt := range []struct {
+		name          string
+		ctx           context.Context
+		authorization string
+		wantCode      connect.Code
+		wantIdentity  string
+	}{
+		{name: "token", ctx: context.Background(), authorization: "secret", wantIdentity: StaticTokenIdentity},
+		{name: "second authenticator", ctx: context.Background(), authorization: "Bearer other", wantIdentity: StaticTokenIdentity},
+		{name: "wrong token", ctx: context.Background(), authorization: "secret2", wantCode: connect.CodeUnauthenticated},
+		{name: "no credentials", ctx: context.Background(), wantCode: connect.CodeUnauthenticated},
+		{name: "client certificate", ctx: withPeer, wantIdentity: X509Identity(peer)},
+		{name: "token overrides client certificate", ctx: withPeer, authorization: "secret", wantIdentity: StaticTokenIdentity},
+		{name: "wrong token with client certificate", ctx: withPeer, authorization: "secret2", wantCode: connect.CodeUnauthenticated},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			identity = ""
+			req := connect.NewRequest(&emptypb.Empty{})
+			if tt.authorization != "" {
+				req.Header().Set("Authorization", tt.authorization)
+			}
+			_, err := call(tt.ctx, req)
+			if tt.wantCode != 0 {
+				if connect.CodeOf(err) != tt.wantCode {
+					t.Fatalf("error = %v, want %v", err, tt.wantCode)
+				}
+				if identity != "" {
+					t.Errorf("rejected call reached the handler as %q", identity)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if identity != tt.wantIdentity {
+				t.Errorf("identity = %q, want %q", identity, tt.wantIdentity)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package authn
+
+import (
+	"crypto/x509"
+
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+)
+
+// X509Identity returns the identity of the holder of a client certificate:
+// its SPIFFE ID if it is an X.509-SVID, otherwise its subject.
+func X509Identity(cert *x509.Certificate) string {
+	if id, err := x509svid.IDFromCert(cert); err == nil {
+		return id.String()
+	}
+	return cert.Subject.String()
+}
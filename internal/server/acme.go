@@ -0,0 +1,89 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+	"github.com/jakexks/northfoot/internal/acme"
+)
+
+// ACMEHandler returns a handler serving an ACME directory for every signer
+// that allows ACME under /acme/{signer id}/.
+func (s *Server) ACMEHandler(options ...acme.Option) http.Handler {
+	options = append([]acme.Option{acme.WithLogger(s.log.Named("acme")), acme.WithBaseURL(s.baseURL)}, options...)
+	return acme.NewHandler(&acmeIssuer{s: s}, options...)
+}
+
+// acmeIssuer issues ACME certificates through the same path as Sign.
+type acmeIssuer struct {
+	s *Server
+}
+
+func (a *acmeIssuer) SignerExists(ctx context.Context, signerID int64) (bool, error) {
+	signer, err := a.s.getSigner(ctx, signerID)
+	if errors.Is(err, errSignerNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return signer.config.GetAllowAcme(), nil
+}
+
+func (a *acmeIssuer) Issue(ctx context.Context, signerID int64, csr []byte, duration time.Duration) ([]*x509.Certificate, error) {
+	// the signer may have stopped allowing ACME since the order was created
+	exists, err := a.SignerExists(ctx, signerID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("signer %d does not allow ACME enrollment", signerID)
+	}
+	req := &signv1.SignRequest{SignerId: signerID, Csr: csr}
+	if duration > 0 {
+		req.DurationHint = durationpb.New(duration)
+	}
+	cert, signer, err := a.s.issue(withProtocol(ctx, "acme"), req)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*x509.Certificate{cert}, intermediates(signer.TrustBundle())...), nil
+}
+
+// intermediates returns the certificates in a trust bundle that are not
+// self-signed roots.
+func intermediates(bundle []*x509.Certificate) []*x509.Certificate {
+	var out []*x509.Certificate
+	for _, cert := range bundle {
+		if bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil {
+			continue
+		}
+		out = append(out, cert)
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"crypto/x509/pkix"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/internal/server/profile"
+	"github.com/jakexks/northfoot/internal/util"
+)
+
+const (
+	defaultRootCAValidity         = time.Hour * 24 * 365 * 10
+	defaultIntermediateCAValidity = time.Hour * 24 * 365
+)
+
+// caOptions converts the CA configuration of a signer, applying the defaults
+// for a self-signed or intermediate CA.
+func caOptions(config *mgmtv1.CAConfig, intermediate bool) (util.CAOptions, error) {
+	opts := util.CAOptions{
+		Subject: pkix.Name{
+			Organization: []string{"Northfoot"},
+			CommonName:   "Northfoot Root CA",
+		},
+		Validity:   defaultRootCAValidity,
+		MaxPathLen: -1,
+	}
+	if intermediate {
+		opts.Subject.CommonName = "Northfoot Intermediate CA"
+		opts.Validity = defaultIntermediateCAValidity
+		opts.MaxPathLen = 0
+	}
+	if config == nil {
+		return opts, nil
+	}
+	if s := config.Subject; s != nil {
+		opts.Subject = pkix.Name{
+			CommonName:         s.CommonName,
+			Organization:       s.Organization,
+			OrganizationalUnit: s.OrganizationalUnit,
+			Country:            s.Country,
+			Province:           s.Province,
+			Locality:           s.Locality,
+		}
+	}
+	if config.Validity != nil {
+		opts.Validity = config.Validity.AsDuration()
+	}
+	if config.MaxPathLen != nil {
+		opts.MaxPathLen = int(*config.MaxPathLen)
+	}
+	if config.TrustDomain != nil {
+		td, err := spiffeid.TrustDomainFromString(*config.TrustDomain)
+		if err != nil {
+			return util.CAOptions{}, fmt.Errorf("invalid trust domain: %w", err)
+		}
+		opts.URIs = append(opts.URIs, td.ID().URL())
+	}
+	opts.DNSNames = config.DnsNames
+	for _, s := range config.IpAddresses {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return util.CAOptions{}, fmt.Errorf("invalid IP address %q", s)
+		}
+		opts.IPAddresses = append(opts.IPAddresses, ip)
+	}
+	for _, eku := range config.ExtKeyUsages {
+		usage, ok := profile.ExtKeyUsage(eku)
+		if !ok {
+			return util.CAOptions{}, fmt.Errorf("invalid extended key usage %s", eku)
+		}
+		opts.ExtKeyUsage = append(opts.ExtKeyUsage, usage)
+	}
+	nc, err := nameConstraints(config.NameConstraints)
+	if err != nil {
+		return util.CAOptions{}, err
+	}
+	opts.NameConstraints = nc
+	return opts, nil
+}
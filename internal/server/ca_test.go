@@ -0,0 +1,215 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/api/sign/v1/signv1connect"
+)
+
+// signerCA returns the CA certificate that the signer with the given ID
+// issues from.
+func signerCA(t *testing.T, s *Server, id int64) *x509.Certificate {
+	t.Helper()
+	signer, err := s.getSigner(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer.TrustBundle()[0]
+}
+
+func TestGeneratedCA(t *testing.T) {
+	s := newTestServer(t)
+	constraints := &mgmtv1.NameConstraints{
+		PermittedDnsDomains: []string{"edge.local"},
+		ExcludedDnsDomains:  []string{"admin.edge.local"},
+		PermittedIpRanges:   []string{"10.0.0.0/8"},
+		ExcludedIpRanges:    []string{"10.99.0.0/16"},
+		PermittedUriDomains: []string{"edge"},
+		ExcludedUriDomains:  []string{"other.edge"},
+	}
+
+	t.Run("default root", func(t *testing.T) {
+		createTestSigner(t, s, newTestSigner(1))
+		ca := signerCA(t, s, 1)
+		if ca.Subject.CommonName != "Northfoot Root CA" || !reflect.DeepEqual(ca.Subject.Organization, []string{"Northfoot"}) || len(ca.Subject.Country) != 0 {
+			t.Errorf("subject = %s", ca.Subject)
+		}
+		if len(ca.DNSNames) != 0 || len(ca.IPAddresses) != 0 || len(ca.URIs) != 0 || len(ca.ExtKeyUsage) != 0 {
+			t.Errorf("root CA has SANs %v %v %v or extended key usages %v", ca.DNSNames, ca.IPAddresses, ca.URIs, ca.ExtKeyUsage)
+		}
+		if !ca.IsCA || ca.MaxPathLen != -1 || ca.KeyUsage != x509.KeyUsageCertSign|x509.KeyUsageCRLSign|x509.KeyUsageDigitalSignature {
+			t.Errorf("CA = %v, path length %d, key usage %v", ca.IsCA, ca.MaxPathLen, ca.KeyUsage)
+		}
+		checkValidity(t, ca, defaultRootCAValidity)
+	})
+
+	t.Run("configured root", func(t *testing.T) {
+		signer := newTestSigner(2)
+		signer.GetInMem().Ca = &mgmtv1.CAConfig{
+			Subject: &mgmtv1.CASubject{
+				CommonName:         "Edge Root CA",
+				Organization:       []string{"Edge"},
+				OrganizationalUnit: []string{"Platform"},
+				Country:            []string{"NL"},
+			},
+			Validity:        durationpb.New(48 * time.Hour),
+			MaxPathLen:      proto.Int32(1),
+			TrustDomain:     proto.String("edge"),
+			DnsNames:        []string{"ca.edge.local"},
+			IpAddresses:     []string{"10.0.0.1"},
+			ExtKeyUsages:    []mgmtv1.ExtKeyUsage{mgmtv1.ExtKeyUsage_EXT_KEY_USAGE_SERVER_AUTH},
+			NameConstraints: constraints,
+		}
+		createTestSigner(t, s, signer)
+		ca := signerCA(t, s, 2)
+		if ca.Subject.CommonName != "Edge Root CA" || !reflect.DeepEqual(ca.Subject.Organization, []string{"Edge"}) ||
+			!reflect.DeepEqual(ca.Subject.OrganizationalUnit, []string{"Platform"}) || !reflect.DeepEqual(ca.Subject.Country, []string{"NL"}) {
+			t.Errorf("subject = %s", ca.Subject)
+		}
+		checkValidity(t, ca, 48*time.Hour)
+		if ca.MaxPathLen != 1 || ca.MaxPathLenZero {
+			t.Errorf("path length = %d, want 1", ca.MaxPathLen)
+		}
+		if len(ca.URIs) != 1 || ca.URIs[0].String() != "spiffe://edge" {
+			t.Errorf("URIs = %v, want [spiffe://edge]", ca.URIs)
+		}
+		if !reflect.DeepEqual(ca.DNSNames, []string{"ca.edge.local"}) || len(ca.IPAddresses) != 1 || !ca.IPAddresses[0].Equal(net.ParseIP("10.0.0.1")) {
+			t.Errorf("SANs = %v %v", ca.DNSNames, ca.IPAddresses)
+		}
+		if !reflect.DeepEqual(ca.ExtKeyUsage, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}) {
+			t.Errorf("extended key usages = %v", ca.ExtKeyUsage)
+		}
+		checkNameConstraintsEncoded(t, ca, constraints)
+	})
+
+	t.Run("file signer intermediate", func(t *testing.T) {
+		signer := newTestFileSigner(t, 3)
+		signer.GetFile().Ca = &mgmtv1.CAConfig{
+			Subject:         &mgmtv1.CASubject{CommonName: "Edge Issuing CA"},
+			Validity:        durationpb.New(24 * time.Hour),
+			NameConstraints: constraints,
+		}
+		createTestSigner(t, s, signer)
+		ca := signerCA(t, s, 3)
+		if ca.Subject.CommonName != "Edge Issuing CA" {
+			t.Errorf("subject = %s", ca.Subject)
+		}
+		checkValidity(t, ca, 24*time.Hour)
+		if !ca.MaxPathLenZero {
+			t.Errorf("path length = %d, want 0", ca.MaxPathLen)
+		}
+		checkNameConstraintsEncoded(t, ca, constraints)
+	})
+
+	t.Run("remote intermediate", func(t *testing.T) {
+		upstream := newTestServer(t)
+		issuer := newTestSigner(1)
+		allow := true
+		issuer.AllowCaIssuance = &allow
+		createTestSigner(t, upstream, issuer)
+		path, handler := signv1connect.NewSignServiceHandler(upstream)
+		mux := http.NewServeMux()
+		mux.Handle(path, handler)
+		hs := httptest.NewServer(mux)
+		defer hs.Close()
+
+		signer := newTestRemoteSigner(4, hs.URL)
+		signer.GetRemote().GetNorthfoot().Token = nil
+		signer.GetRemote().GetNorthfoot().Ca = &mgmtv1.CAConfig{NameConstraints: constraints}
+		createTestSigner(t, s, signer)
+		ca := signerCA(t, s, 4)
+		if ca.Subject.CommonName != "Northfoot Intermediate CA" || !ca.MaxPathLenZero {
+			t.Errorf("subject = %s, path length %d", ca.Subject, ca.MaxPathLen)
+		}
+		checkNameConstraintsEncoded(t, ca, constraints)
+	})
+
+	for name, config := range map[string]*mgmtv1.CAConfig{
+		"zero validity":           {Validity: durationpb.New(0)},
+		"negative path length":    {MaxPathLen: proto.Int32(-1)},
+		"invalid trust domain":    {TrustDomain: proto.String("Edge Domain")},
+		"invalid IP address":      {IpAddresses: []string{"10.0.0"}},
+		"invalid key usage":       {ExtKeyUsages: []mgmtv1.ExtKeyUsage{mgmtv1.ExtKeyUsage_EXT_KEY_USAGE_UNSPECIFIED}},
+		"wildcard DNS constraint": {NameConstraints: &mgmtv1.NameConstraints{PermittedDnsDomains: []string{"*.edge.local"}}},
+		"URI as URI constraint":   {NameConstraints: &mgmtv1.NameConstraints{PermittedUriDomains: []string{"spiffe://edge"}}},
+		"invalid IP constraint":   {NameConstraints: &mgmtv1.NameConstraints{ExcludedIpRanges: []string{"10.0.0.1"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			signer := newTestSigner(5)
+			signer.GetInMem().Ca = config
+			_, err := s.CreateSigner(context.Background(), connect.NewRequest(&mgmtv1.CreateSignerRequest{Signer: signer}))
+			if connect.CodeOf(err) != connect.CodeInvalidArgument {
+				t.Errorf("CreateSigner() = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+// checkNameConstraintsEncoded checks that ca carries the critical name
+// constraints of want.
+func checkNameConstraintsEncoded(t *testing.T, ca *x509.Certificate, want *mgmtv1.NameConstraints) {
+	t.Helper()
+	ranges := func(nets []*net.IPNet) []string {
+		var out []string
+		for _, n := range nets {
+			out = append(out, n.String())
+		}
+		return out
+	}
+	if !ca.PermittedDNSDomainsCritical {
+		t.Error("name constraints are not critical")
+	}
+	for _, c := range []struct {
+		name      string
+		got, want []string
+	}{
+		{"permitted DNS domains", ca.PermittedDNSDomains, want.PermittedDnsDomains},
+		{"excluded DNS domains", ca.ExcludedDNSDomains, want.ExcludedDnsDomains},
+		{"permitted IP ranges", ranges(ca.PermittedIPRanges), want.PermittedIpRanges},
+		{"excluded IP ranges", ranges(ca.ExcludedIPRanges), want.ExcludedIpRanges},
+		{"permitted URI domains", ca.PermittedURIDomains, want.PermittedUriDomains},
+		{"excluded URI domains", ca.ExcludedURIDomains, want.ExcludedUriDomains},
+	} {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+// checkValidity checks that ca is valid for want, allowing for its
+// validity period being truncated to seconds.
+func checkValidity(t *testing.T, ca *x509.Certificate, want time.Duration) {
+	t.Helper()
+	if validity := ca.NotAfter.Sub(ca.NotBefore); validity < want-time.Second || validity > want+time.Second {
+		t.Errorf("validity = %s, want %s", validity, want)
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/internal/authn"
+)
+
+const (
+	defaultListCertificatesLimit = 1000
+	selectCertificates           = `SELECT c.signer_id, c.requester, c.csr_sha256, c.cert, r.revoked_at, r.reason
+		FROM certificates c LEFT JOIN revocations r ON r.serial = c.serial`
+)
+
+// recordCertificate stores a certificate issued by issuer, one of the CAs of
+// a signer, in the datastore so that operators can see what a signer has
+// issued.
+func (s *Server) recordCertificate(ctx context.Context, signerID int64, issuer *x509.Certificate, csr []byte, cert *x509.Certificate) error {
+	requester, _ := authn.IdentityFromContext(ctx)
+	csrHash := sha256.Sum256(csr)
+	q, err := s.db.PrepareContext(ctx, `INSERT INTO certificates (serial, signer_id, subject, sans, not_before, not_after, requester, csr_sha256, cert, issuer_serial) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer q.Close()
+	_, err = q.ExecContext(ctx,
+		serialString(cert.SerialNumber),
+		signerID,
+		cert.Subject.String(),
+		strings.Join(certificateSANs(cert), "\n"),
+		cert.NotBefore.Unix(),
+		cert.NotAfter.Unix(),
+		requester,
+		hex.EncodeToString(csrHash[:]),
+		cert.Raw,
+		serialString(issuer.SerialNumber),
+	)
+	return err
+}
+
+func (s *Server) ListCertificates(ctx context.Context, req *connect.Request[mgmtv1.ListCertificatesRequest]) (*connect.Response[mgmtv1.ListCertificatesResponse], error) {
+	query := selectCertificates + " WHERE 1=1"
+	var args []any
+	if req.Msg.SignerId != nil {
+		query += " AND c.signer_id = ?"
+		args = append(args, *req.Msg.SignerId)
+	}
+	if req.Msg.ExpiringBefore != nil {
+		query += " AND c.not_after < ?"
+		args = append(args, req.Msg.ExpiringBefore.AsTime().Unix())
+	}
+	if req.Msg.San != nil {
+		query += ` AND c.sans LIKE ? ESCAPE '\'`
+		args = append(args, "%"+escapeLike(*req.Msg.San)+"%")
+	}
+	clause, clauseArgs := allowedSignersClause(ctx, "c.signer_id")
+	query += clause
+	args = append(args, clauseArgs...)
+	limit := int64(defaultListCertificatesLimit)
+	if req.Msg.Limit != nil && *req.Msg.Limit > 0 {
+		limit = *req.Msg.Limit
+	}
+	query += " ORDER BY c.not_after LIMIT ?"
+	args = append(args, limit)
+
+	q, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	defer q.Close()
+	rows, err := q.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	defer rows.Close()
+	var certs []*mgmtv1.Certificate
+	for rows.Next() {
+		cert, err := scanCertificate(rows)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		certs = append(certs, cert)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(&mgmtv1.ListCertificatesResponse{
+		Certificates: certs,
+	}), nil
+}
+
+func (s *Server) GetCertificate(ctx context.Context, req *connect.Request[mgmtv1.GetCertificateRequest]) (*connect.Response[mgmtv1.GetCertificateResponse], error) {
+	serial, err := parseSerial(req.Msg.Serial)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	q, err := s.db.PrepareContext(ctx, selectCertificates+" WHERE c.serial = ?")
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	defer q.Close()
+	cert, err := scanCertificate(q.QueryRowContext(ctx, serialString(serial)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("certificate with serial "+req.Msg.Serial+" not found"))
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(&mgmtv1.GetCertificateResponse{
+		Certificate: cert,
+	}), nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCertificate(row scanner) (*mgmtv1.Certificate, error) {
+	var (
+		signerID  int64
+		requester string
+		csrHash   string
+		der       []byte
+		revokedAt sql.NullInt64
+		reason    sql.NullInt32
+	)
+	if err := row.Scan(&signerID, &requester, &csrHash, &der, &revokedAt, &reason); err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	pb := &mgmtv1.Certificate{
+		Serial:         serialString(cert.SerialNumber),
+		SignerId:       signerID,
+		Subject:        cert.Subject.String(),
+		DnsNames:       cert.DNSNames,
+		EmailAddresses: cert.EmailAddresses,
+		NotBefore:      timestamppb.New(cert.NotBefore),
+		NotAfter:       timestamppb.New(cert.NotAfter),
+		Requester:      requester,
+		CsrSha256:      csrHash,
+		Cert:           der,
+	}
+	for _, ip := range cert.IPAddresses {
+		pb.IpAddresses = append(pb.IpAddresses, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		pb.Uris = append(pb.Uris, uri.String())
+	}
+	if revokedAt.Valid {
+		pb.RevokedAt = timestamppb.New(time.Unix(revokedAt.Int64, 0))
+		pb.RevocationReason = mgmtv1.RevocationReason(reason.Int32)
+	}
+	return pb, nil
+}
+
+// certificateSANs returns every subject alternative name in cert as a string.
+func certificateSANs(cert *x509.Certificate) []string {
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
+
+func serialString(serial *big.Int) string {
+	return hex.EncodeToString(serial.Bytes())
+}
+
+func parseSerial(serial string) (*big.Int, error) {
+	serial = strings.ReplaceAll(strings.ToLower(serial), ":", "")
+	n, ok := new(big.Int).SetString(serial, 16)
+	if !ok {
+		return nil, errors.New("serial must be hex encoded")
+	}
+	return n, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
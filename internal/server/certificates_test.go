@@ -0,0 +1,199 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+)
+
+// issueTestCertificates issues a certificate for each DNS name from the
+// signer it maps to, each expiring an hour after the previous one, and
+// returns their serials in order.
+func issueTestCertificates(t *testing.T, s *Server, names []string, signers map[string]int64) []string {
+	t.Helper()
+	var serials []string
+	for i, name := range names {
+		resp, err := s.Sign(context.Background(), connect.NewRequest(&signv1.SignRequest{
+			SignerId:     signers[name],
+			Csr:          newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: name}, DNSNames: []string{name}}),
+			DurationHint: durationpb.New(time.Duration(i+1) * time.Hour),
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert, err := x509.ParseCertificate(resp.Msg.Cert)
+		if err != nil {
+			t.Fatal(err)
+		}
+		serials = append(serials, serialString(cert.SerialNumber))
+	}
+	return serials
+}
+
+func TestListCertificates(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	for id := int64(1); id <= 3; id++ {
+		createTestSigner(t, s, newTestSigner(id))
+	}
+	names := []string{"a.example.com", "b.example.com", "c.example.com"}
+	serials := issueTestCertificates(t, s, names, map[string]int64{"a.example.com": 1, "b.example.com": 1, "c.example.com": 2})
+	if _, err := s.RevokeCertificate(ctx, connect.NewRequest(&mgmtv1.RevokeCertificateRequest{
+		Serial: serials[1],
+		Reason: mgmtv1.RevocationReason_REVOCATION_REASON_KEY_COMPROMISE,
+	})); err != nil {
+		t.Fatal(err)
+	}
+	client := newTestManagementClient(t, s, "admin")
+	for _, binding := range []*mgmtv1.RoleBinding{
+		{Identity: "auditor", Role: mgmtv1.Role_ROLE_AUDITOR, SignerIds: []int64{1}},
+		{Identity: "other-auditor", Role: mgmtv1.Role_ROLE_AUDITOR, SignerIds: []int64{3}},
+	} {
+		if _, err := client.CreateRoleBinding(ctx, requestAs("admin", &mgmtv1.CreateRoleBindingRequest{RoleBinding: binding})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		identity string
+		req      *mgmtv1.ListCertificatesRequest
+		want     []string
+		wantCode connect.Code
+	}{
+		{"all", "admin", &mgmtv1.ListCertificatesRequest{}, serials, 0},
+		{"by signer", "admin", &mgmtv1.ListCertificatesRequest{SignerId: proto.Int64(2)}, serials[2:], 0},
+		{"expiring before", "admin", &mgmtv1.ListCertificatesRequest{ExpiringBefore: timestamppb.New(time.Now().Add(150 * time.Minute))}, serials[:2], 0},
+		{"by SAN", "admin", &mgmtv1.ListCertificatesRequest{San: proto.String("b.example")}, serials[1:2], 0},
+		{"SAN wildcards are literal", "admin", &mgmtv1.ListCertificatesRequest{San: proto.String("a_example")}, nil, 0},
+		{"limit", "admin", &mgmtv1.ListCertificatesRequest{Limit: proto.Int64(2)}, serials[:2], 0},
+		{"filters combined", "admin", &mgmtv1.ListCertificatesRequest{SignerId: proto.Int64(1), San: proto.String("example.com"), Limit: proto.Int64(1)}, serials[:1], 0},
+		{"scoped auditor", "auditor", &mgmtv1.ListCertificatesRequest{}, serials[:2], 0},
+		{"scoped auditor filtering its signer", "auditor", &mgmtv1.ListCertificatesRequest{SignerId: proto.Int64(1)}, serials[:2], 0},
+		{"scoped auditor filtering another signer", "auditor", &mgmtv1.ListCertificatesRequest{SignerId: proto.Int64(2)}, nil, connect.CodePermissionDenied},
+		{"auditor of a signer without certificates", "other-auditor", &mgmtv1.ListCertificatesRequest{}, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.ListCertificates(ctx, requestAs(tt.identity, tt.req))
+			if tt.wantCode != 0 {
+				if connect.CodeOf(err) != tt.wantCode {
+					t.Fatalf("ListCertificates() error = %v, want %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, cert := range resp.Msg.Certificates {
+				got = append(got, cert.Serial)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("serials = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCertificate(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	for id := int64(1); id <= 2; id++ {
+		createTestSigner(t, s, newTestSigner(id))
+	}
+	serials := issueTestCertificates(t, s, []string{"a.example.com", "b.example.com"}, map[string]int64{"a.example.com": 1, "b.example.com": 2})
+	if _, err := s.RevokeCertificate(ctx, connect.NewRequest(&mgmtv1.RevokeCertificateRequest{
+		Serial: serials[0],
+		Reason: mgmtv1.RevocationReason_REVOCATION_REASON_KEY_COMPROMISE,
+	})); err != nil {
+		t.Fatal(err)
+	}
+	client := newTestManagementClient(t, s, "admin")
+	if _, err := client.CreateRoleBinding(ctx, requestAs("admin", &mgmtv1.CreateRoleBindingRequest{RoleBinding: &mgmtv1.RoleBinding{
+		Identity: "auditor", Role: mgmtv1.Role_ROLE_AUDITOR, SignerIds: []int64{1},
+	}})); err != nil {
+		t.Fatal(err)
+	}
+	// colon separated upper case, as printed by openssl
+	var colons []string
+	for i := 0; i < len(serials[0]); i += 2 {
+		colons = append(colons, strings.ToUpper(serials[0][i:i+2]))
+	}
+
+	tests := []struct {
+		name       string
+		identity   string
+		serial     string
+		wantSerial string
+		wantCode   connect.Code
+	}{
+		{"found", "admin", serials[1], serials[1], 0},
+		{"openssl formatted serial", "admin", strings.Join(colons, ":"), serials[0], 0},
+		{"malformed serial", "admin", "not-hex", "", connect.CodeInvalidArgument},
+		{"unknown serial", "admin", serialString(big.NewInt(42)), "", connect.CodeNotFound},
+		{"scoped auditor on its signer", "auditor", serials[0], serials[0], 0},
+		{"scoped auditor on another signer", "auditor", serials[1], "", connect.CodePermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.GetCertificate(ctx, requestAs(tt.identity, &mgmtv1.GetCertificateRequest{Serial: tt.serial}))
+			if tt.wantCode != 0 {
+				if connect.CodeOf(err) != tt.wantCode {
+					t.Fatalf("GetCertificate() error = %v, want %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			cert := resp.Msg.Certificate
+			if cert.Serial != tt.wantSerial {
+				t.Errorf("serial = %s, want %s", cert.Serial, tt.wantSerial)
+			}
+			revoked := tt.wantSerial == serials[0]
+			if (cert.RevokedAt != nil) != revoked {
+				t.Errorf("revoked = %v, want %v", cert.RevokedAt != nil, revoked)
+			}
+			if revoked && cert.RevocationReason != mgmtv1.RevocationReason_REVOCATION_REASON_KEY_COMPROMISE {
+				t.Errorf("revocation reason = %v, want key compromise", cert.RevocationReason)
+			}
+			parsed, err := x509.ParseCertificate(cert.Cert)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cert.DnsNames) != 1 || cert.DnsNames[0] != parsed.DNSNames[0] || cert.SignerId == 0 {
+				t.Errorf("certificate fields do not match the certificate: %v", cert)
+			}
+		})
+	}
+}
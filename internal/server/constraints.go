@@ -0,0 +1,178 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/internal/util"
+)
+
+// nameConstraints converts the name constraints of a CA configuration.
+func nameConstraints(config *mgmtv1.NameConstraints) (util.NameConstraints, error) {
+	var n util.NameConstraints
+	if config == nil {
+		return n, nil
+	}
+	parseRanges := func(ranges []string) ([]*net.IPNet, error) {
+		var out []*net.IPNet
+		for _, r := range ranges {
+			_, ipNet, err := net.ParseCIDR(r)
+			if err != nil {
+				return nil, fmt.Errorf("invalid name constraint IP range %q", r)
+			}
+			out = append(out, ipNet)
+		}
+		return out, nil
+	}
+	var err error
+	if n.PermittedIPRanges, err = parseRanges(config.PermittedIpRanges); err != nil {
+		return n, err
+	}
+	if n.ExcludedIPRanges, err = parseRanges(config.ExcludedIpRanges); err != nil {
+		return n, err
+	}
+	n.PermittedDNSDomains = config.PermittedDnsDomains
+	n.ExcludedDNSDomains = config.ExcludedDnsDomains
+	n.PermittedURIDomains = config.PermittedUriDomains
+	n.ExcludedURIDomains = config.ExcludedUriDomains
+	return n, nil
+}
+
+// signerNameConstraints returns the name constraints a signer must respect:
+// those of every CA in its trust bundle, and those configured for its CA in
+// case a remote parent did not encode them.
+func signerNameConstraints(config *mgmtv1.Signer, s signer) ([]util.NameConstraints, error) {
+	var out []util.NameConstraints
+	if rotated, ok := s.(*rotatedSigner); ok {
+		// retired CAs no longer issue
+		s = rotated.signer
+	}
+	for _, cert := range s.TrustBundle() {
+		if n := util.CertificateNameConstraints(cert); !n.Empty() {
+			out = append(out, n)
+		}
+	}
+	n, err := nameConstraints(signerCAConfig(config).GetNameConstraints())
+	if err != nil {
+		return nil, err
+	}
+	if !n.Empty() {
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+// signerCAConfig returns the configuration of the CA a signer generates, if
+// its type generates one.
+func signerCAConfig(s *mgmtv1.Signer) *mgmtv1.CAConfig {
+	switch {
+	case s.GetInMem() != nil:
+		return s.GetInMem().Ca
+	case s.GetFile() != nil:
+		return s.GetFile().Ca
+	case s.GetHsm() != nil:
+		return s.GetHsm().Ca
+	case s.GetRemote().GetNorthfoot() != nil:
+		return s.GetRemote().GetNorthfoot().Ca
+	}
+	return nil
+}
+
+// checkNameConstraints refuses CSRs with SANs outside of any of constraints,
+// so that requests fail before signing rather than at verification.
+func checkNameConstraints(constraints []util.NameConstraints, csr *x509.CertificateRequest) error {
+	for _, n := range constraints {
+		for _, name := range csr.DNSNames {
+			if err := checkConstraintDomain("DNS name", name, name, n.PermittedDNSDomains, n.ExcludedDNSDomains); err != nil {
+				return err
+			}
+		}
+		for _, ip := range csr.IPAddresses {
+			if err := checkConstraintIP(ip, n.PermittedIPRanges, n.ExcludedIPRanges); err != nil {
+				return err
+			}
+		}
+		for _, uri := range csr.URIs {
+			if len(n.PermittedURIDomains) == 0 && len(n.ExcludedURIDomains) == 0 {
+				break
+			}
+			host := uri.Hostname()
+			if host == "" || net.ParseIP(host) != nil {
+				return fmt.Errorf("URI %q has no domain to check against the CA's name constraints", uri)
+			}
+			if err := checkConstraintDomain("URI", uri.String(), host, n.PermittedURIDomains, n.ExcludedURIDomains); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkConstraintDomain(kind, name, domain string, permitted, excluded []string) error {
+	for _, c := range excluded {
+		if matchConstraintDomain(c, domain) {
+			return fmt.Errorf("%s %q is excluded by the CA's name constraint %q", kind, name, c)
+		}
+	}
+	if len(permitted) == 0 {
+		return nil
+	}
+	for _, c := range permitted {
+		if matchConstraintDomain(c, domain) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %q is not permitted by the CA's name constraints", kind, name)
+}
+
+func checkConstraintIP(ip net.IP, permitted, excluded []*net.IPNet) error {
+	for _, c := range excluded {
+		if c.Contains(ip) {
+			return fmt.Errorf("IP address %s is excluded by the CA's name constraint %s", ip, c)
+		}
+	}
+	if len(permitted) == 0 {
+		return nil
+	}
+	for _, c := range permitted {
+		if c.Contains(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("IP address %s is not permitted by the CA's name constraints", ip)
+}
+
+// matchConstraintDomain follows the rules of crypto/x509: a constraint
+// matches the domain and its subdomains, or only subdomains when it starts
+// with a dot.
+func matchConstraintDomain(constraint, domain string) bool {
+	constraint = strings.ToLower(constraint)
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if constraint == "" {
+		return true
+	}
+	if strings.HasPrefix(constraint, ".") {
+		return strings.HasSuffix(domain, constraint)
+	}
+	return domain == constraint || strings.HasSuffix(domain, "."+constraint)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"crypto/x509"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/jakexks/northfoot/internal/util"
+)
+
+func TestCheckNameConstraints(t *testing.T) {
+	cidr := func(s string) *net.IPNet {
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	uri := func(s string) *url.URL {
+		u, err := url.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u
+	}
+	site := util.NameConstraints{
+		PermittedDNSDomains: []string{"edge.example.com", ".svc"},
+		ExcludedDNSDomains:  []string{"admin.edge.example.com"},
+		PermittedIPRanges:   []*net.IPNet{cidr("10.0.0.0/8")},
+		ExcludedIPRanges:    []*net.IPNet{cidr("10.0.0.0/24")},
+		PermittedURIDomains: []string{"edge"},
+		ExcludedURIDomains:  []string{"admin.edge"},
+	}
+	for _, tt := range []struct {
+		name        string
+		constraints []util.NameConstraints
+		csr         *x509.CertificateRequest
+		wantErr     bool
+	}{
+		{"no constraints", nil, &x509.CertificateRequest{DNSNames: []string{"anything.example.org"}}, false},
+		{"permitted DNS name", []util.NameConstraints{site}, &x509.CertificateRequest{DNSNames: []string{"edge.example.com"}}, false},
+		{"permitted DNS subdomain", []util.NameConstraints{site}, &x509.CertificateRequest{DNSNames: []string{"a.Edge.Example.com."}}, false},
+		{"dot constraint matches subdomains", []util.NameConstraints{site}, &x509.CertificateRequest{DNSNames: []string{"api.ns.svc"}}, false},
+		{"dot constraint excludes itself", []util.NameConstraints{site}, &x509.CertificateRequest{DNSNames: []string{"svc"}}, true},
+		{"DNS name outside permitted", []util.NameConstraints{site}, &x509.CertificateRequest{DNSNames: []string{"example.com"}}, true},
+		{"DNS suffix without label boundary", []util.NameConstraints{site}, &x509.CertificateRequest{DNSNames: []string{"badedge.example.com"}}, true},
+		{"excluded DNS name", []util.NameConstraints{site}, &x509.CertificateRequest{DNSNames: []string{"x.admin.edge.example.com"}}, true},
+		{"one DNS name outside permitted", []util.NameConstraints{site}, &x509.CertificateRequest{DNSNames: []string{"edge.example.com", "example.org"}}, true},
+		{"only excluded DNS names", []util.NameConstraints{{ExcludedDNSDomains: []string{"example.org"}}}, &x509.CertificateRequest{DNSNames: []string{"example.com"}}, false},
+		{"permitted IP", []util.NameConstraints{site}, &x509.CertificateRequest{IPAddresses: []net.IP{net.ParseIP("10.1.2.3")}}, false},
+		{"IP outside permitted", []util.NameConstraints{site}, &x509.CertificateRequest{IPAddresses: []net.IP{net.ParseIP("192.168.1.1")}}, true},
+		{"excluded IP", []util.NameConstraints{site}, &x509.CertificateRequest{IPAddresses: []net.IP{net.ParseIP("10.0.0.5")}}, true},
+		{"permitted URI", []util.NameConstraints{site}, &x509.CertificateRequest{URIs: []*url.URL{uri("spiffe://edge/ns/a/sa/b")}}, false},
+		{"URI outside permitted", []util.NameConstraints{site}, &x509.CertificateRequest{URIs: []*url.URL{uri("spiffe://other/ns/a/sa/b")}}, true},
+		{"excluded URI", []util.NameConstraints{site}, &x509.CertificateRequest{URIs: []*url.URL{uri("https://admin.edge/")}}, true},
+		{"URI without host", []util.NameConstraints{site}, &x509.CertificateRequest{URIs: []*url.URL{uri("urn:uuid:1234")}}, true},
+		{"URI with IP host", []util.NameConstraints{site}, &x509.CertificateRequest{URIs: []*url.URL{uri("https://10.1.2.3/")}}, true},
+		{"URI without URI constraints", []util.NameConstraints{{PermittedDNSDomains: []string{"edge.example.com"}}}, &x509.CertificateRequest{URIs: []*url.URL{uri("urn:uuid:1234")}}, false},
+		{"permitted by every CA", []util.NameConstraints{site, {PermittedDNSDomains: []string{"example.com"}}}, &x509.CertificateRequest{DNSNames: []string{"a.edge.example.com"}}, false},
+		{"refused by one CA", []util.NameConstraints{{PermittedDNSDomains: []string{"example.com"}}, site}, &x509.CertificateRequest{DNSNames: []string{"www.example.com"}}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkNameConstraints(tt.constraints, tt.csr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkNameConstraints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
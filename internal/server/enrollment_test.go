@@ -0,0 +1,56 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnrollmentRequiresOptIn(t *testing.T) {
+	s := newTestServer(t)
+	createTestSigner(t, s, newTestSigner(1))
+	enabled := newTestSigner(2)
+	allow := true
+	enabled.AllowAcme = &allow
+	enabled.AllowEst = &allow
+	createTestSigner(t, s, enabled)
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		path    string
+		want    int
+	}{
+		{"acme disabled", s.ACMEHandler(), "/acme/1/directory", http.StatusNotFound},
+		{"acme enabled", s.ACMEHandler(), "/acme/2/directory", http.StatusOK},
+		{"est disabled", s.ESTHandler(), "/.well-known/est/1/cacerts", http.StatusNotFound},
+		{"est enabled", s.ESTHandler(), "/.well-known/est/2/cacerts", http.StatusOK},
+		{"scep without challenge password", s.SCEPHandler(), "/scep/2?operation=GetCACaps", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.want {
+				t.Errorf("GET %s = %d, want %d", tt.path, rec.Code, tt.want)
+			}
+		})
+	}
+}
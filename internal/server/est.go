@@ -0,0 +1,300 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+	"go.mozilla.org/pkcs7"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ocsp"
+
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+	"github.com/jakexks/northfoot/internal/authn"
+)
+
+const maxESTRequestSize = 1 << 16
+
+var (
+	oidSHA256WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidEd25519         = asn1.ObjectIdentifier{1, 3, 101, 112}
+
+	errUnauthenticated = errors.New("client is not authenticated")
+)
+
+// ESTHandler serves RFC 7030 enrollment for each signer that allows EST at
+// /.well-known/est/{id}/. Clients enroll with a client certificate issued by
+// the signer or HTTP basic credentials, and re-enroll with the certificate
+// being renewed.
+func (s *Server) ESTHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/.well-known/est/"), "/")
+		if len(parts) != 2 {
+			http.NotFound(w, r)
+			return
+		}
+		id, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		signer, err := s.getSigner(r.Context(), id)
+		if errors.Is(err, errSignerNotFound) || err == nil && !signer.config.GetAllowEst() {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			s.log.Error("failed to get signer", zap.Int64("signer_id", id), zap.Error(err))
+			http.Error(w, "failed to get signer", http.StatusInternalServerError)
+			return
+		}
+
+		method := http.MethodPost
+		if parts[1] == "cacerts" || parts[1] == "csrattrs" {
+			method = http.MethodGet
+		}
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		switch parts[1] {
+		case "cacerts":
+			s.writeESTCertificates(w, signer.TrustBundle())
+		case "csrattrs":
+			s.writeESTCSRAttributes(w, signer)
+		case "simpleenroll":
+			s.estEnroll(w, r, id, signer, false)
+		case "simplereenroll":
+			s.estEnroll(w, r, id, signer, true)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func (s *Server) estEnroll(w http.ResponseWriter, r *http.Request, signerID int64, signer *cachedSigner, reenroll bool) {
+	ctx := r.Context()
+	cert, err := s.estClientCertificate(ctx, r, signerID, signer, reenroll)
+	if err != nil {
+		s.log.Info("rejected EST client certificate", zap.Int64("signer_id", signerID), zap.Error(err))
+		http.Error(w, "invalid client certificate", http.StatusForbidden)
+		return
+	}
+	var identity string
+	switch {
+	case cert != nil:
+		identity = authn.X509Identity(cert)
+	case !reenroll && s.basicAuth != nil:
+		username, password, ok := r.BasicAuth()
+		if ok {
+			identity, ok = s.basicAuth(username, password)
+		}
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Basic realm="northfoot-est"`)
+			http.Error(w, errUnauthenticated.Error(), http.StatusUnauthorized)
+			return
+		}
+	default:
+		http.Error(w, errUnauthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxESTRequestSize))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	der := decodeESTBody(body)
+	if reenroll {
+		if err := checkReenrollCSR(der, cert); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	issued, _, err := s.issue(withProtocol(authn.WithIdentity(ctx, identity), "est"), &signv1.SignRequest{SignerId: signerID, Csr: der})
+	if err != nil {
+		http.Error(w, err.Error(), httpStatus(err))
+		return
+	}
+	s.log.Info("issued EST certificate", zap.Int64("signer_id", signerID), zap.String("identity", identity), zap.Bool("reenroll", reenroll))
+	s.writeESTCertificates(w, []*x509.Certificate{issued})
+}
+
+// estClientCertificate returns the client certificate of an EST request, if
+// it chains to the signer and has not been revoked. Enrollment with any other
+// certificate falls back to basic credentials, while re-enrollment is
+// refused.
+func (s *Server) estClientCertificate(ctx context.Context, r *http.Request, signerID int64, signer *cachedSigner, reenroll bool) (*x509.Certificate, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil, nil
+	}
+	cert := r.TLS.PeerCertificates[0]
+	if err := s.verifyIssuedCertificate(ctx, signerID, signer, cert, r.TLS.PeerCertificates[1:]); err != nil {
+		if !reenroll {
+			s.log.Info("ignoring EST client certificate", zap.Int64("signer_id", signerID), zap.Error(err))
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cert, nil
+}
+
+// verifyIssuedCertificate checks that a client certificate chains to a
+// signer and has not been revoked.
+func (s *Server) verifyIssuedCertificate(ctx context.Context, signerID int64, signer *cachedSigner, cert *x509.Certificate, chain []*x509.Certificate) error {
+	roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+	for _, c := range signer.TrustBundle() {
+		roots.AddCert(c)
+	}
+	for _, c := range chain {
+		intermediates.AddCert(c)
+	}
+	chains, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return err
+	}
+	// a CA presented as a client certificate is its own issuer
+	issuer := cert
+	if len(chains[0]) > 1 {
+		issuer = chains[0][1]
+	}
+	var status ocsp.Response
+	if err := s.certificateStatus(ctx, signerID, issuer, cert.SerialNumber, &status); err != nil {
+		return err
+	}
+	if status.Status == ocsp.Revoked {
+		return fmt.Errorf("certificate %s is revoked", serialString(cert.SerialNumber))
+	}
+	return nil
+}
+
+// checkReenrollCSR checks that a re-enrollment CSR requests the same subject
+// and SANs as the certificate being renewed, as RFC 7030 requires.
+func checkReenrollCSR(der []byte, cert *x509.Certificate) error {
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return fmt.Errorf("failed to parse CSR: %w", err)
+	}
+	if !bytes.Equal(csr.RawSubject, cert.RawSubject) {
+		return errors.New("CSR subject does not match the certificate being renewed")
+	}
+	if !reflect.DeepEqual(certificateSANs(&x509.Certificate{
+		DNSNames:       csr.DNSNames,
+		IPAddresses:    csr.IPAddresses,
+		EmailAddresses: csr.EmailAddresses,
+		URIs:           csr.URIs,
+	}), certificateSANs(cert)) {
+		return errors.New("CSR SANs do not match the certificate being renewed")
+	}
+	return nil
+}
+
+// decodeESTBody decodes a base64 request body, falling back to raw DER for
+// clients that do not encode their requests.
+func decodeESTBody(body []byte) []byte {
+	der, err := base64.StdEncoding.DecodeString(string(bytes.Join(bytes.Fields(body), nil)))
+	if err != nil {
+		return body
+	}
+	return der
+}
+
+func (s *Server) writeESTCertificates(w http.ResponseWriter, certs []*x509.Certificate) {
+	var raw []byte
+	for _, cert := range certs {
+		raw = append(raw, cert.Raw...)
+	}
+	p7, err := pkcs7.DegenerateCertificate(raw)
+	if err != nil {
+		s.log.Error("failed to encode PKCS#7 certificates", zap.Error(err))
+		http.Error(w, "failed to encode certificates", http.StatusInternalServerError)
+		return
+	}
+	writeESTBase64(w, "application/pkcs7-mime; smime-type=certs-only", p7)
+}
+
+// writeESTCSRAttributes asks clients to sign their CSRs with the algorithm
+// matching the CA key.
+func (s *Server) writeESTCSRAttributes(w http.ResponseWriter, signer *cachedSigner) {
+	var oid asn1.ObjectIdentifier
+	switch signer.TrustBundle()[0].PublicKey.(type) {
+	case *rsa.PublicKey:
+		oid = oidSHA256WithRSA
+	case *ecdsa.PublicKey:
+		oid = oidECDSAWithSHA256
+	case ed25519.PublicKey:
+		oid = oidEd25519
+	default:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	attrs, err := asn1.Marshal([]asn1.ObjectIdentifier{oid})
+	if err != nil {
+		http.Error(w, "failed to encode CSR attributes", http.StatusInternalServerError)
+		return
+	}
+	writeESTBase64(w, "application/csrattrs", attrs)
+}
+
+func writeESTBase64(w http.ResponseWriter, contentType string, der []byte) {
+	encoded := base64.StdEncoding.EncodeToString(der)
+	var body strings.Builder
+	for len(encoded) > 76 {
+		body.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	body.WriteString(encoded + "\r\n")
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Transfer-Encoding", "base64")
+	io.WriteString(w, body.String())
+}
+
+// httpStatus maps an issuance error to an HTTP status code.
+func httpStatus(err error) int {
+	switch connect.CodeOf(err) {
+	case connect.CodeInvalidArgument:
+		return http.StatusBadRequest
+	case connect.CodePermissionDenied:
+		return http.StatusForbidden
+	case connect.CodeNotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+	"github.com/jakexks/northfoot/internal/authn"
+)
+
+// issueTestClientCertificate issues a certificate for cn from a signer, with
+// its key.
+func issueTestClientCertificate(t *testing.T, s *Server, signerID int64, cn string) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: cn}}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.Sign(context.Background(), connect.NewRequest(&signv1.SignRequest{SignerId: signerID, Csr: csr}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(resp.Msg.Cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{resp.Msg.Cert}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestESTEnroll(t *testing.T) {
+	s := newTestServer(t, WithBasicAuth(authn.StaticBasicAuth("device", "secret")))
+	allow := true
+	for id := int64(1); id <= 2; id++ {
+		signer := newTestSigner(id)
+		signer.AllowEst = &allow
+		createTestSigner(t, s, signer)
+	}
+	device := issueTestClientCertificate(t, s, 1, "device")
+	other := issueTestClientCertificate(t, s, 2, "device")
+	revoked := issueTestClientCertificate(t, s, 1, "device")
+	if _, err := s.RevokeCertificate(context.Background(), connect.NewRequest(&mgmtv1.RevokeCertificateRequest{
+		Serial: serialString(revoked.Leaf.SerialNumber),
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	hs := httptest.NewUnstartedServer(s.ESTHandler())
+	// as in production, the TLS server verifies client certificates from
+	// any signer
+	clientCAs := x509.NewCertPool()
+	for id := int64(1); id <= 2; id++ {
+		cached, err := s.getSigner(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clientCAs.AddCert(cached.TrustBundle()[0])
+	}
+	hs.TLS = &tls.Config{ClientAuth: tls.VerifyClientCertIfGiven, ClientCAs: clientCAs}
+	hs.Config.ErrorLog = log.New(io.Discard, "", 0)
+	hs.StartTLS()
+	defer hs.Close()
+	client := func(cert *tls.Certificate) *http.Client {
+		transport := hs.Client().Transport.(*http.Transport).Clone()
+		if cert != nil {
+			transport.TLSClientConfig.Certificates = []tls.Certificate{*cert}
+		}
+		return &http.Client{Transport: transport}
+	}
+
+	deviceCSR := newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "device"}})
+	tests := []struct {
+		name      string
+		cert      *tls.Certificate
+		basicAuth bool
+		operation string
+		csr       []byte
+		want      int
+	}{
+		{"enroll with signer certificate", &device, false, "simpleenroll", deviceCSR, http.StatusOK},
+		{"enroll with basic credentials", nil, true, "simpleenroll", deviceCSR, http.StatusOK},
+		{"enroll without credentials", nil, false, "simpleenroll", deviceCSR, http.StatusUnauthorized},
+		{"enroll with another signer's certificate", &other, false, "simpleenroll", deviceCSR, http.StatusUnauthorized},
+		{"enroll with another signer's certificate and basic credentials", &other, true, "simpleenroll", deviceCSR, http.StatusOK},
+		{"enroll with revoked certificate", &revoked, false, "simpleenroll", deviceCSR, http.StatusUnauthorized},
+		{"reenroll", &device, false, "simplereenroll", deviceCSR, http.StatusOK},
+		{"reenroll with a different subject", &device, false, "simplereenroll", newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "other"}}), http.StatusBadRequest},
+		{"reenroll without certificate", nil, true, "simplereenroll", deviceCSR, http.StatusUnauthorized},
+		{"reenroll with another signer's certificate", &other, false, "simplereenroll", deviceCSR, http.StatusForbidden},
+		{"reenroll with revoked certificate", &revoked, false, "simplereenroll", deviceCSR, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := base64.StdEncoding.EncodeToString(tt.csr)
+			req, err := http.NewRequest(http.MethodPost, hs.URL+"/.well-known/est/1/"+tt.operation, bytes.NewBufferString(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/pkcs10")
+			if tt.basicAuth {
+				req.SetBasicAuth("device", "secret")
+			}
+			resp, err := client(tt.cert).Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				msg, _ := io.ReadAll(resp.Body)
+				t.Fatalf("POST %s = %d %s, want %d", tt.operation, resp.StatusCode, msg, tt.want)
+			}
+		})
+	}
+}
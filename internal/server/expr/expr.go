@@ -0,0 +1,179 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package expr compiles and evaluates the CEL expressions signers use as
+// custom issuance rules.
+package expr
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/google/cel-go/cel"
+)
+
+// costLimit bounds the work a single rule may do per evaluation.
+const costLimit = 1000000
+
+// env declares the variables available to rules:
+//
+//	subject              map(string, list(string)), keyed by common_name, organization,
+//	                     organizational_unit, country, province, locality and serial_number.
+//	                     Subjects with other attributes, or more than one common name or
+//	                     serial number, are refused before rules are evaluated.
+//	dns_names            list(string)
+//	ip_addresses         list(string)
+//	email_addresses      list(string)
+//	uris                 list(string)
+//	public_key_algorithm string, one of RSA, ECDSA or Ed25519
+//	public_key_bits      int
+//	requester            string, the authenticated identity of the caller or ""
+//	metadata             map(string, string), with the protocol, signer_id and profile of
+//	                     the request
+//	duration             duration, the requested lifetime
+//	ca                   bool, whether an intermediate CA was requested
+var env *cel.Env
+
+func init() {
+	var err error
+	env, err = cel.NewEnv(
+		cel.Variable("subject", cel.MapType(cel.StringType, cel.ListType(cel.StringType))),
+		cel.Variable("dns_names", cel.ListType(cel.StringType)),
+		cel.Variable("ip_addresses", cel.ListType(cel.StringType)),
+		cel.Variable("email_addresses", cel.ListType(cel.StringType)),
+		cel.Variable("uris", cel.ListType(cel.StringType)),
+		cel.Variable("public_key_algorithm", cel.StringType),
+		cel.Variable("public_key_bits", cel.IntType),
+		cel.Variable("requester", cel.StringType),
+		cel.Variable("metadata", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("duration", cel.DurationType),
+		cel.Variable("ca", cel.BoolType),
+	)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Rule is a compiled CEL expression that must evaluate to true for a
+// certificate to be issued.
+type Rule struct {
+	Name    string
+	program cel.Program
+}
+
+// Compile parses and type-checks expression, which must evaluate to a bool.
+func Compile(name, expression string) (*Rule, error) {
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("rule %q: %w", name, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("rule %q must evaluate to a bool, not %s", name, ast.OutputType())
+	}
+	program, err := env.Program(ast, cel.CostLimit(costLimit))
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %w", name, err)
+	}
+	return &Rule{Name: name, program: program}, nil
+}
+
+// Input is what a rule is evaluated against.
+type Input struct {
+	CSR       *x509.CertificateRequest
+	Requester string
+	Metadata  map[string]string
+	Duration  time.Duration
+	CA        bool
+}
+
+// Allows evaluates the rule against in.
+func (r *Rule) Allows(in *Input) (bool, error) {
+	out, _, err := r.program.Eval(in.activation())
+	if err != nil {
+		return false, fmt.Errorf("rule %q: %w", r.Name, err)
+	}
+	allowed, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("rule %q evaluated to %v, not a bool", r.Name, out)
+	}
+	return allowed, nil
+}
+
+func (in *Input) activation() map[string]any {
+	csr := in.CSR
+	subject := map[string][]string{
+		"organization":        csr.Subject.Organization,
+		"organizational_unit": csr.Subject.OrganizationalUnit,
+		"country":             csr.Subject.Country,
+		"province":            csr.Subject.Province,
+		"locality":            csr.Subject.Locality,
+	}
+	if csr.Subject.CommonName != "" {
+		subject["common_name"] = []string{csr.Subject.CommonName}
+	}
+	if csr.Subject.SerialNumber != "" {
+		subject["serial_number"] = []string{csr.Subject.SerialNumber}
+	}
+	ips := make([]string, 0, len(csr.IPAddresses))
+	for _, ip := range csr.IPAddresses {
+		ips = append(ips, ip.String())
+	}
+	uris := make([]string, 0, len(csr.URIs))
+	for _, uri := range csr.URIs {
+		uris = append(uris, uri.String())
+	}
+	metadata := in.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	return map[string]any{
+		"subject":              subject,
+		"dns_names":            nonNil(csr.DNSNames),
+		"ip_addresses":         ips,
+		"email_addresses":      nonNil(csr.EmailAddresses),
+		"uris":                 uris,
+		"public_key_algorithm": csr.PublicKeyAlgorithm.String(),
+		"public_key_bits":      publicKeyBits(csr.PublicKey),
+		"requester":            in.Requester,
+		"metadata":             metadata,
+		"duration":             in.Duration,
+		"ca":                   in.CA,
+	}
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func publicKeyBits(key any) int {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return k.N.BitLen()
+	case *ecdsa.PublicKey:
+		return k.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return 256
+	}
+	return 0
+}
@@ -0,0 +1,116 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package expr
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCSR(t *testing.T, template *x509.CertificateRequest) *x509.CertificateRequest {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return csr
+}
+
+func TestRule(t *testing.T) {
+	in := &Input{
+		CSR: newTestCSR(t, &x509.CertificateRequest{
+			Subject:  pkix.Name{CommonName: "web.edge.local", Organization: []string{"Edge"}},
+			DNSNames: []string{"web.edge.local"},
+		}),
+		Requester: "spiffe://edge/ns/team/sa/web",
+		Metadata:  map[string]string{"protocol": "connect", "signer_id": "1", "profile": "server"},
+		Duration:  time.Hour,
+	}
+	for _, tt := range []struct {
+		expression string
+		allowed    bool
+	}{
+		{`dns_names.all(n, n.endsWith(".edge.local"))`, true},
+		{`dns_names.exists(n, n == "db.edge.local")`, false},
+		{`subject["common_name"][0] == "web.edge.local" && "Edge" in subject["organization"]`, true},
+		{`public_key_algorithm == "ECDSA" && public_key_bits >= 256`, true},
+		{`public_key_algorithm == "RSA"`, false},
+		{`requester.startsWith("spiffe://edge/ns/team/")`, true},
+		{`metadata["profile"] == "server" && metadata["protocol"] == "connect"`, true},
+		{`duration <= duration("1h") && !ca`, true},
+		{`duration < duration("30m")`, false},
+		{`size(ip_addresses) == 0 && size(email_addresses) == 0 && size(uris) == 0`, true},
+	} {
+		t.Run(tt.expression, func(t *testing.T) {
+			rule, err := Compile("test", tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			allowed, err := rule.Allows(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if allowed != tt.allowed {
+				t.Errorf("Allows() = %v, want %v", allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	for _, expression := range []string{
+		`dns_names.all(n, `,
+		`unknown_variable == 1`,
+		`size(dns_names)`,
+		`public_key_bits == "256"`,
+	} {
+		if _, err := Compile("test", expression); err == nil {
+			t.Errorf("Compile(%q) accepted an invalid rule", expression)
+		}
+	}
+}
+
+func TestRuleCostLimit(t *testing.T) {
+	names := make([]string, 1000)
+	for i := range names {
+		names[i] = fmt.Sprintf("host%d.edge.local", i)
+	}
+	rule, err := Compile("quadratic", `dns_names.all(a, dns_names.all(b, a == b || a != b))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = rule.Allows(&Input{CSR: newTestCSR(t, &x509.CertificateRequest{DNSNames: names})})
+	if err == nil || !strings.Contains(err.Error(), "cost limit") {
+		t.Errorf("Allows() error = %v, want the cost limit to be exceeded", err)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/internal/util"
+)
+
+// newFileSigner loads a parent CA from the filesystem, then uses it to sign
+// an intermediate CA whose key is generated and held in memory. Leaves are
+// issued by the intermediate, the parent key is not retained.
+func newFileSigner(keys *keyPool, config *mgmtv1.SignerFileConfig) (signer, error) {
+	certPEM, err := os.ReadFile(config.TlsCertFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parent certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(config.TlsKeyFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parent key: %w", err)
+	}
+	parents, err := util.ParseCertificatesPEM(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load parent certificate: %w", err)
+	}
+	parentKey, err := util.ParsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load parent key: %w", err)
+	}
+	parent := parents[0]
+	if !parent.IsCA {
+		return nil, errors.New("parent certificate is not a CA")
+	}
+	match, err := util.KeyMatchesCertificate(parentKey, parent)
+	if err != nil {
+		return nil, err
+	}
+	if !match {
+		return nil, errors.New("parent key does not match parent certificate")
+	}
+
+	opts, err := caOptions(config.Ca, true)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keys.generate(config.Key, config.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	csrDER, err := util.CreateCACertificateRequest(key, opts)
+	if err != nil {
+		return nil, err
+	}
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate request: %w", err)
+	}
+	cert, err := util.SignIntermediateCA(csr, parent, parentKey, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &inMemSigner{
+		key:   key,
+		cert:  cert,
+		chain: parents,
+	}, nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"go.uber.org/zap"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+	"github.com/jakexks/northfoot/internal/util"
+)
+
+func TestFileSigner(t *testing.T) {
+	root, rootKey := newTestCA(t)
+	// an intermediate below root, which may itself issue intermediates
+	parentKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := caOptions(nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.MaxPathLen = -1
+	csrDER, err := util.CreateCACertificateRequest(parentKey, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent, err := util.SignIntermediateCA(csr, root, rootKey, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, root, rootKey.Public(), rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notCA, err := x509.ParseCertificate(leaf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		key       crypto.Signer
+		certs     []*x509.Certificate
+		configure func(config *mgmtv1.SignerFileConfig)
+		wantChain []*x509.Certificate
+		wantErr   string
+	}{
+		{name: "root parent", key: rootKey, certs: []*x509.Certificate{root}, wantChain: []*x509.Certificate{root}},
+		{name: "intermediate parent with its chain", key: parentKey, certs: []*x509.Certificate{parent, root}, wantChain: []*x509.Certificate{parent, root}},
+		{name: "parent key mismatch", key: otherKey, certs: []*x509.Certificate{root}, wantErr: "parent key does not match parent certificate"},
+		{name: "parent not a CA", key: rootKey, certs: []*x509.Certificate{notCA}, wantErr: "parent certificate is not a CA"},
+		{name: "no parent certificate", key: rootKey, wantErr: "failed to load parent certificate"},
+		{name: "missing certificate file", key: rootKey, certs: []*x509.Certificate{root}, wantErr: "failed to read parent certificate", configure: func(config *mgmtv1.SignerFileConfig) {
+			config.TlsCertFilePath += ".missing"
+		}},
+		{name: "missing key file", key: rootKey, certs: []*x509.Certificate{root}, wantErr: "failed to read parent key", configure: func(config *mgmtv1.SignerFileConfig) {
+			config.TlsKeyFilePath += ".missing"
+		}},
+		{name: "key file holding a certificate", key: rootKey, certs: []*x509.Certificate{root}, wantErr: "failed to load parent key", configure: func(config *mgmtv1.SignerFileConfig) {
+			config.TlsKeyFilePath = config.TlsCertFilePath
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			certPath, keyPath := writeTestPEM(t, t.TempDir(), tt.key, tt.certs...)
+			config := &mgmtv1.SignerFileConfig{
+				TlsCertFilePath: certPath,
+				TlsKeyFilePath:  keyPath,
+				Key:             mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC,
+			}
+			if tt.configure != nil {
+				tt.configure(config)
+			}
+			signer, err := newFileSigner(newKeyPool(context.Background(), zap.NewNop(), 0), config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("newFileSigner() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			bundle := signer.TrustBundle()
+			if len(bundle) != len(tt.wantChain)+1 {
+				t.Fatalf("trust bundle has %d certificates, want %d", len(bundle), len(tt.wantChain)+1)
+			}
+			for i, cert := range tt.wantChain {
+				if !bundle[i+1].Equal(cert) {
+					t.Errorf("trust bundle certificate %d is %s, want %s", i+1, bundle[i+1].Subject, cert.Subject)
+				}
+			}
+			ca := bundle[0]
+			if !ca.IsCA || !ca.MaxPathLenZero || ca.CheckSignatureFrom(tt.wantChain[0]) != nil {
+				t.Errorf("intermediate CA = %v, path length zero %v, signed by %s", ca.IsCA, ca.MaxPathLenZero, ca.Issuer)
+			}
+			if match, err := util.KeyMatchesCertificate(tt.key, ca); err != nil || match {
+				t.Error("intermediate CA reuses the parent key")
+			}
+		})
+	}
+}
+
+func TestSignFileSigner(t *testing.T) {
+	s := newTestServer(t)
+	config := newTestFileSigner(t, 1)
+	createTestSigner(t, s, config)
+	resp, err := s.Sign(context.Background(), connect.NewRequest(&signv1.SignRequest{
+		SignerId: 1,
+		Csr:      newTestCSR(t, &x509.CertificateRequest{DNSNames: []string{"leaf.edge.local"}}),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(resp.Msg.Cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM, err := os.ReadFile(config.GetFile().TlsCertFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(certPEM)
+	signer, err := s.getSigner(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(signer.TrustBundle()[0])
+	if _, err := leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, DNSName: "leaf.edge.local"}); err != nil {
+		t.Errorf("leaf does not chain to the parent on disk: %v", err)
+	}
+}
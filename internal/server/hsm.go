@@ -0,0 +1,132 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+
+	"github.com/ThalesIgnite/crypto11"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/internal/server/validation"
+	"github.com/jakexks/northfoot/internal/util"
+)
+
+const defaultHSMKeyLabel = "northfoot-ca"
+
+// hsmSigner issues leaf certificates from a CA whose key never leaves a
+// PKCS#11 token.
+type hsmSigner struct {
+	*inMemSigner
+	ctx *crypto11.Context
+}
+
+func newHSMSigner(config *mgmtv1.SignerHSMConfig) (signer, error) {
+	ctx, err := crypto11.Configure(&crypto11.Config{
+		Path:        config.HsmLibraryPath,
+		TokenSerial: config.GetHsmTokenSerial(),
+		TokenLabel:  config.GetHsmTokenLabel(),
+		Pin:         config.GetHsmTokenPin(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PKCS#11 module: %w", err)
+	}
+	s, err := loadHSMSigner(ctx, config)
+	if err != nil {
+		ctx.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func loadHSMSigner(ctx *crypto11.Context, config *mgmtv1.SignerHSMConfig) (*hsmSigner, error) {
+	label := []byte(defaultHSMKeyLabel)
+	if config.HsmKeyLabel != nil {
+		label = []byte(*config.HsmKeyLabel)
+	}
+	var key crypto.Signer
+	found, err := ctx.FindKeyPair(nil, label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find key pair on token: %w", err)
+	}
+	if found != nil {
+		key = found
+	} else {
+		key, err = generateHSMKey(ctx, label, config.Key, config.KeySize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate key pair on token: %w", err)
+		}
+	}
+	cert, err := ctx.FindCertificate(nil, label, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find certificate on token: %w", err)
+	}
+	if cert == nil {
+		opts, err := caOptions(config.Ca, false)
+		if err != nil {
+			return nil, err
+		}
+		cert, err = util.GenerateSelfSignedCA(key, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := ctx.ImportCertificateWithLabel(label, label, cert); err != nil {
+			return nil, fmt.Errorf("failed to import certificate to token: %w", err)
+		}
+	}
+	match, err := util.KeyMatchesCertificate(key, cert)
+	if err != nil {
+		return nil, err
+	}
+	if !match {
+		return nil, errors.New("certificate on token does not match key pair")
+	}
+	return &hsmSigner{
+		inMemSigner: &inMemSigner{
+			key:  key,
+			cert: cert,
+		},
+		ctx: ctx,
+	}, nil
+}
+
+func generateHSMKey(ctx *crypto11.Context, label []byte, keyType mgmtv1.PrivateKeyType, keySize *int64) (crypto.Signer, error) {
+	switch keyType {
+	case mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_RSA:
+		bits := 2048
+		if keySize != nil {
+			bits = int(*keySize)
+		}
+		return ctx.GenerateRSAKeyPairWithLabel(label, label, bits)
+	case mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC:
+		curve, err := ellipticCurve(keySize)
+		if err != nil {
+			return nil, err
+		}
+		return ctx.GenerateECDSAKeyPairWithLabel(label, label, curve)
+	default:
+		return nil, validation.ErrHSMKeyType
+	}
+}
+
+// Close releases the PKCS#11 sessions held by the signer.
+func (h *hsmSigner) Close() error {
+	return h.ctx.Close()
+}
@@ -0,0 +1,307 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ThalesIgnite/crypto11"
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/internal/util"
+)
+
+// softHSMLibraries are the usual install locations of the SoftHSMv2 module.
+var softHSMLibraries = []string{
+	"/usr/lib/softhsm/libsofthsm2.so",
+	"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+	"/usr/lib64/softhsm/libsofthsm2.so",
+	"/usr/local/lib/softhsm/libsofthsm2.so",
+	"/opt/homebrew/lib/softhsm/libsofthsm2.so",
+}
+
+// newTestSoftHSM initialises a SoftHSMv2 token in a temporary directory and
+// returns the config of an EC signer using it, skipping the test if SoftHSM
+// is not installed.
+func newTestSoftHSM(t *testing.T) *mgmtv1.SignerHSMConfig {
+	t.Helper()
+	tool, err := exec.LookPath("softhsm2-util")
+	if err != nil {
+		t.Skip("softhsm2-util is not installed")
+	}
+	library := os.Getenv("SOFTHSM2_LIB")
+	for _, path := range softHSMLibraries {
+		if library != "" {
+			break
+		}
+		if _, err := os.Stat(path); err == nil {
+			library = path
+		}
+	}
+	if library == "" {
+		t.Skip("SoftHSM module not found, set SOFTHSM2_LIB")
+	}
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "tokens"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	conf := filepath.Join(dir, "softhsm2.conf")
+	if err := os.WriteFile(conf, []byte("directories.tokendir = "+filepath.Join(dir, "tokens")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SOFTHSM2_CONF", conf)
+	if out, err := exec.Command(tool, "--init-token", "--free", "--label", "northfoot", "--pin", "1234", "--so-pin", "5678").CombinedOutput(); err != nil {
+		t.Fatalf("failed to initialise token: %v: %s", err, out)
+	}
+	return &mgmtv1.SignerHSMConfig{
+		HsmLibraryPath: library,
+		HsmTokenLabel:  proto.String("northfoot"),
+		HsmTokenPin:    proto.String("1234"),
+		Key:            mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC,
+	}
+}
+
+func TestHSMSigner(t *testing.T) {
+	base := newTestSoftHSM(t)
+	config := func(label string, key mgmtv1.PrivateKeyType) *mgmtv1.SignerHSMConfig {
+		c := proto.Clone(base).(*mgmtv1.SignerHSMConfig)
+		c.HsmKeyLabel = proto.String(label)
+		c.Key = key
+		return c
+	}
+
+	// a key pair on the token whose certificate belongs to another key
+	ctx, err := crypto11.Configure(&crypto11.Config{Path: base.HsmLibraryPath, TokenLabel: base.GetHsmTokenLabel(), Pin: base.GetHsmTokenPin()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctx.GenerateECDSAKeyPairWithLabel([]byte("mismatch"), []byte("mismatch"), elliptic.P256()); err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := caOptions(nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCA, err := util.GenerateSelfSignedCA(other, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.ImportCertificateWithLabel([]byte("mismatch"), []byte("mismatch"), otherCA); err != nil {
+		t.Fatal(err)
+	}
+	ctx.Close()
+
+	var first *x509.Certificate
+	tests := []struct {
+		name    string
+		config  *mgmtv1.SignerHSMConfig
+		wantErr bool
+		check   func(t *testing.T, ca *x509.Certificate)
+	}{
+		{
+			name:   "creates an EC key pair and CA",
+			config: config("ec", mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC),
+			check: func(t *testing.T, ca *x509.Certificate) {
+				if _, ok := ca.PublicKey.(*ecdsa.PublicKey); !ok {
+					t.Errorf("CA key is %T, want ECDSA", ca.PublicKey)
+				}
+				first = ca
+			},
+		},
+		{
+			name:   "reuses the key pair and CA on the token",
+			config: config("ec", mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC),
+			check: func(t *testing.T, ca *x509.Certificate) {
+				if !ca.Equal(first) {
+					t.Error("CA was replaced")
+				}
+			},
+		},
+		{
+			name:   "creates an RSA key pair",
+			config: config("rsa", mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_RSA),
+			check: func(t *testing.T, ca *x509.Certificate) {
+				if _, ok := ca.PublicKey.(*rsa.PublicKey); !ok {
+					t.Errorf("CA key is %T, want RSA", ca.PublicKey)
+				}
+			},
+		},
+		{
+			name:    "certificate does not match the key pair",
+			config:  config("mismatch", mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC),
+			wantErr: true,
+		},
+		{
+			name: "wrong PIN",
+			config: func() *mgmtv1.SignerHSMConfig {
+				c := config("ec", mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC)
+				c.HsmTokenPin = proto.String("0000")
+				return c
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "unknown token",
+			config: func() *mgmtv1.SignerHSMConfig {
+				c := config("ec", mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC)
+				c.HsmTokenLabel = proto.String("missing")
+				return c
+			}(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := newHSMSigner(tt.config)
+			if tt.wantErr {
+				if err == nil {
+					s.(*hsmSigner).Close()
+					t.Fatal("newHSMSigner() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.(*hsmSigner).Close()
+			ca := s.TrustBundle()[0]
+			tt.check(t, ca)
+
+			csr, err := x509.ParseCertificateRequest(newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "leaf.example.com"}, DNSNames: []string{"leaf.example.com"}}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			leaf, err := s.Sign(context.Background(), csr, signOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := leaf.CheckSignatureFrom(ca); err != nil {
+				t.Errorf("leaf is not signed by the token's CA: %v", err)
+			}
+		})
+	}
+}
+
+func TestHSMSignerSecrets(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	signer := &mgmtv1.Signer{
+		Id:   proto.Int64(1),
+		Type: mgmtv1.SignerType_SIGNER_TYPE_HSM,
+		SignerConfig: &mgmtv1.Signer_Hsm{Hsm: &mgmtv1.SignerHSMConfig{
+			HsmLibraryPath: "/nonexistent/libpkcs11.so",
+			HsmTokenLabel:  proto.String("northfoot"),
+			HsmTokenPin:    proto.String("1234"),
+			Key:            mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC,
+		}},
+	}
+	// the signer is stored even though its module cannot be loaded
+	createTestSigner(t, s, signer)
+	storedPIN := func() string {
+		var raw string
+		if err := s.db.QueryRow("SELECT signer FROM signers").Scan(&raw); err != nil {
+			t.Fatal(err)
+		}
+		stored := &mgmtv1.Signer{}
+		if err := protojson.Unmarshal([]byte(raw), stored); err != nil {
+			t.Fatal(err)
+		}
+		return stored.GetHsm().GetHsmTokenPin()
+	}
+
+	got, err := s.GetSigner(ctx, connect.NewRequest(&mgmtv1.GetSignerRequest{Id: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Msg.Signer.GetHsm().HsmTokenPin != nil {
+		t.Error("GetSigner() returned the PIN")
+	}
+	list, err := s.ListSigners(ctx, connect.NewRequest(&emptypb.Empty{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Msg.Signers.GetSigners()[0].GetHsm().HsmTokenPin != nil {
+		t.Error("ListSigners() returned the PIN")
+	}
+
+	got, err = s.GetSigner(ctx, connect.NewRequest(&mgmtv1.GetSignerRequest{Id: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Msg.Signer.Name = proto.String("hsm")
+	resp, err := s.UpdateSigner(ctx, connect.NewRequest(&mgmtv1.UpdateSignerRequest{
+		Signer:     got.Msg.Signer,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name", "hsm"}},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Msg.Signer.GetHsm().HsmTokenPin != nil {
+		t.Error("UpdateSigner() returned the PIN")
+	}
+	if pin := storedPIN(); pin != "1234" {
+		t.Errorf("stored PIN after writing back the redacted signer = %q, want 1234", pin)
+	}
+}
+
+func TestCreateHSMSignerKeyType(t *testing.T) {
+	s := newTestServer(t)
+	for i, tt := range []struct {
+		key      mgmtv1.PrivateKeyType
+		wantCode connect.Code
+	}{
+		{mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_RSA, 0},
+		{mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC, 0},
+		{mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_ED25519, connect.CodeInvalidArgument},
+		{mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_UNSPECIFIED, connect.CodeInvalidArgument},
+	} {
+		t.Run(tt.key.String(), func(t *testing.T) {
+			_, err := s.CreateSigner(context.Background(), connect.NewRequest(&mgmtv1.CreateSignerRequest{Signer: &mgmtv1.Signer{
+				Id:   proto.Int64(int64(i + 1)),
+				Type: mgmtv1.SignerType_SIGNER_TYPE_HSM,
+				SignerConfig: &mgmtv1.Signer_Hsm{Hsm: &mgmtv1.SignerHSMConfig{
+					HsmLibraryPath: "/nonexistent/libpkcs11.so",
+					HsmTokenLabel:  proto.String("northfoot"),
+					Key:            tt.key,
+				}},
+			}}))
+			if tt.wantCode == 0 && err != nil || tt.wantCode != 0 && connect.CodeOf(err) != tt.wantCode {
+				t.Errorf("CreateSigner() error = %v, want %v", err, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto"
+	"sync"
+
+	"go.uber.org/zap"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+)
+
+const defaultKeyPoolSize = 2
+
+// keyPool generates CA keys in the background, so that creating or rotating
+// a signer does not wait for key generation. A nil keyPool generates keys on
+// demand. Keys stop being generated once ctx is done.
+type keyPool struct {
+	ctx  context.Context
+	log  *zap.Logger
+	size int
+
+	lock    sync.Mutex
+	pools   map[keySpec]chan crypto.Signer
+	fillers sync.WaitGroup
+}
+
+type keySpec struct {
+	keyType mgmtv1.PrivateKeyType
+	// always set for key types with a size, so that requests for the
+	// default size and for the same size explicitly share a pool
+	keySize int64
+}
+
+func newKeyPool(ctx context.Context, log *zap.Logger, size int) *keyPool {
+	return &keyPool{
+		ctx:   ctx,
+		log:   log,
+		size:  size,
+		pools: make(map[keySpec]chan crypto.Signer),
+	}
+}
+
+// newKeySpec returns the pool of keys of the given type and size.
+func newKeySpec(keyType mgmtv1.PrivateKeyType, keySize *int64) keySpec {
+	spec := keySpec{keyType: keyType}
+	switch {
+	case keyType == mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_ED25519:
+		// Ed25519 keys have a single size
+	case keySize != nil:
+		spec.keySize = *keySize
+	case keyType == mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_RSA:
+		spec.keySize = defaultRSAKeySize
+	case keyType == mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC:
+		spec.keySize = defaultECKeySize
+	}
+	return spec
+}
+
+// generate returns a pre-generated key of the given type and size if there is
+// one, or generates one. Either way, the pool for the type and size is then
+// refilled in the background.
+func (p *keyPool) generate(keyType mgmtv1.PrivateKeyType, keySize *int64) (crypto.Signer, error) {
+	if p == nil {
+		return generatePrivateKey(keyType, keySize)
+	}
+	select {
+	case key := <-p.pool(keyType, keySize):
+		return key, nil
+	default:
+		return generatePrivateKey(keyType, keySize)
+	}
+}
+
+// pool returns the keys of the given type and size, starting to generate
+// them if this is the first time they are asked for.
+func (p *keyPool) pool(keyType mgmtv1.PrivateKeyType, keySize *int64) chan crypto.Signer {
+	spec := newKeySpec(keyType, keySize)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	keys, found := p.pools[spec]
+	if !found {
+		keys = make(chan crypto.Signer, p.size)
+		p.pools[spec] = keys
+		p.fillers.Add(1)
+		go p.fill(spec, keys)
+	}
+	return keys
+}
+
+func (p *keyPool) fill(spec keySpec, keys chan crypto.Signer) {
+	defer p.fillers.Done()
+	var keySize *int64
+	if spec.keySize != 0 {
+		keySize = &spec.keySize
+	}
+	for {
+		key, err := generatePrivateKey(spec.keyType, keySize)
+		if err != nil {
+			p.log.Error("failed to pre-generate key", zap.String("key_type", spec.keyType.String()), zap.Int64("key_size", spec.keySize), zap.Error(err))
+			return
+		}
+		select {
+		case keys <- key:
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// wait blocks until every pool has stopped generating keys, after its
+// context is done.
+func (p *keyPool) wait() {
+	if p == nil {
+		return
+	}
+	p.fillers.Wait()
+}
+
+// warm starts generating keys for a signer, if it generates its CA key in
+// memory.
+func (p *keyPool) warm(config *mgmtv1.Signer) {
+	if p == nil {
+		return
+	}
+	switch {
+	case config.GetInMem() != nil:
+		p.pool(config.GetInMem().Key, config.GetInMem().KeySize)
+	case config.GetFile() != nil:
+		p.pool(config.GetFile().Key, config.GetFile().KeySize)
+	case config.GetRemote().GetNorthfoot() != nil:
+		p.pool(config.GetRemote().GetNorthfoot().Key, config.GetRemote().GetNorthfoot().KeySize)
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+)
+
+func TestNewKeySpec(t *testing.T) {
+	size := func(n int64) *int64 { return &n }
+	for _, tt := range []struct {
+		name    string
+		keyType mgmtv1.PrivateKeyType
+		a, b    *int64
+		same    bool
+	}{
+		{"RSA default and 2048", mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_RSA, nil, size(2048), true},
+		{"RSA 2048 and 4096", mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_RSA, size(2048), size(4096), false},
+		{"EC default and 256", mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC, nil, size(256), true},
+		{"EC default and 384", mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC, nil, size(384), false},
+		{"Ed25519 ignores size", mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_ED25519, nil, size(256), true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := newKeySpec(tt.keyType, tt.a) == newKeySpec(tt.keyType, tt.b); same != tt.same {
+				t.Errorf("sizes share a pool = %v, want %v", same, tt.same)
+			}
+		})
+	}
+}
+
+func TestCloseStopsKeyPool(t *testing.T) {
+	s := newTestServer(t, WithKeyPoolSize(1))
+	createTestSigner(t, s, newTestSigner(1))
+	if len(s.keys.pools) == 0 {
+		t.Fatal("creating a signer did not start its key pool")
+	}
+	closed := make(chan error)
+	go func() { closed <- s.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Close did not stop the key pool and background work")
+	}
+}
@@ -0,0 +1,326 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/x509"
+	"database/sql"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/argon2"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/internal/util"
+)
+
+// KeyEncryptionKeySize is the size in bytes of the AES-256 key that encrypts
+// persisted CA keys.
+const KeyEncryptionKeySize = 32
+
+var errNoKeyEncryptionKey = errors.New("persisting CA keys requires the server to have a key encryption key")
+
+// initKeyEncryptionKey derives the key encryption key from the passphrase,
+// if one was given, with a salt that is generated once per datastore.
+func (s *Server) initKeyEncryptionKey() error {
+	if s.kekPassphrase == "" {
+		return nil
+	}
+	var salt []byte
+	err := s.db.QueryRow(`SELECT salt FROM key_encryption`).Scan(&salt)
+	if errors.Is(err, sql.ErrNoRows) {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+		_, err = s.db.Exec(`INSERT INTO key_encryption (salt) VALUES (?)`, salt)
+	}
+	if err != nil {
+		return err
+	}
+	s.kek = argon2.IDKey([]byte(s.kekPassphrase), salt, 1, 64*1024, 4, KeyEncryptionKeySize)
+	return nil
+}
+
+// createSigner creates the signer for config, reusing its persisted CA key if
+// it has one, or persisting the new key if config asks for it.
+func (s *Server) createSigner(ctx context.Context, signerID int64, config *mgmtv1.Signer) (signer, error) {
+	if !config.GetPersistCaKey() {
+		return newSigner(ctx, s.log, s.keys, config)
+	}
+	if stored, err := s.loadSignerKey(ctx, signerID); err != nil || stored != nil {
+		return stored, err
+	}
+	si, err := newSigner(ctx, s.log, s.keys, config)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.storeSignerKey(ctx, signerID, si, false)
+	if err != nil {
+		closeSigner(s.log, signerID, si)
+		return nil, err
+	}
+	if !stored {
+		// another caller persisted a key first
+		closeSigner(s.log, signerID, si)
+		stored, err := s.loadSignerKey(ctx, signerID)
+		if err == nil && stored == nil {
+			err = errors.New("persisted CA key was removed while the signer was created")
+		}
+		return stored, err
+	}
+	return si, nil
+}
+
+// syncSignerKey persists the current and retired CA keys of si, or removes
+// the persisted keys if config no longer asks for them.
+func (s *Server) syncSignerKey(ctx context.Context, signerID int64, config *mgmtv1.Signer, si signer) error {
+	if !config.GetPersistCaKey() {
+		return s.deleteSignerKeys(ctx, signerID)
+	}
+	if si == nil {
+		return nil
+	}
+	_, err := s.storeSignerKey(ctx, signerID, si, true)
+	return err
+}
+
+// deleteSignerKeys removes the persisted CA keys of a signer.
+func (s *Server) deleteSignerKeys(ctx context.Context, signerID int64) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM signer_keys WHERE signer_id = ?`, signerID); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, `DELETE FROM signer_retired_keys WHERE signer_id = ?`, signerID)
+	return err
+}
+
+// storeSignerKey persists the CA key and certificates of si, and of the
+// retired CAs still in its trust bundle, reporting whether it did. Existing
+// keys are only overwritten if replace is set.
+func (s *Server) storeSignerKey(ctx context.Context, signerID int64, si signer, replace bool) (bool, error) {
+	var retired []retiredCA
+	var crossSigned *x509.Certificate
+	if rotated, ok := si.(*rotatedSigner); ok {
+		si = rotated.signer
+		retired = rotated.retired
+		crossSigned = rotated.crossSigned
+	}
+	sealed, certs, err := s.marshalSignerKey(signerID, si)
+	if err != nil {
+		return false, err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	q := `INSERT INTO signer_keys (signer_id, key, certs) VALUES (?, ?, ?) ON CONFLICT (signer_id) DO NOTHING`
+	if replace {
+		q = `INSERT OR REPLACE INTO signer_keys (signer_id, key, certs) VALUES (?, ?, ?)`
+	}
+	result, err := tx.ExecContext(ctx, q, signerID, sealed, certs)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM signer_retired_keys WHERE signer_id = ?`, signerID); err != nil {
+		return false, err
+	}
+	now := time.Now()
+	for i, old := range retired {
+		if !now.Before(old.until) {
+			continue
+		}
+		sealed, certs, err := s.marshalSignerKey(signerID, old.signer)
+		if err != nil {
+			return false, fmt.Errorf("failed to persist retired CA: %w", err)
+		}
+		// the current CA is cross-signed by the CA it replaced, which is the
+		// last one retired
+		var cross []byte
+		if i == len(retired)-1 && crossSigned != nil {
+			cross = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: crossSigned.Raw})
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO signer_retired_keys (signer_id, serial, key, certs, retire_at, cross_signed) VALUES (?, ?, ?, ?, ?, ?)`,
+			signerID, serialString(old.TrustBundle()[0].SerialNumber), sealed, certs, old.until.UnixNano(), cross); err != nil {
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	s.log.Info("persisted signer CA key", zap.Int64("signer_id", signerID), zap.Int("retired", len(retired)))
+	return true, nil
+}
+
+// marshalSignerKey returns the sealed CA key and PEM encoded certificates of
+// si.
+func (s *Server) marshalSignerKey(signerID int64, si signer) ([]byte, []byte, error) {
+	ca, ok := si.(*inMemSigner)
+	if !ok {
+		return nil, nil, errors.New("signer does not hold its CA key in memory")
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(ca.key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal CA key: %w", err)
+	}
+	sealed, err := s.sealKey(signerID, der)
+	if err != nil {
+		return nil, nil, err
+	}
+	var certs []byte
+	for _, cert := range ca.TrustBundle() {
+		certs = append(certs, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	return sealed, certs, nil
+}
+
+// loadSignerKey returns the signer for a persisted CA key, together with the
+// retired CAs that are still in its trust bundle, or nil if there is none.
+func (s *Server) loadSignerKey(ctx context.Context, signerID int64) (signer, error) {
+	var sealed, certsPEM []byte
+	err := s.db.QueryRowContext(ctx, `SELECT key, certs FROM signer_keys WHERE signer_id = ?`, signerID).Scan(&sealed, &certsPEM)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.parseSignerKey(signerID, sealed, certsPEM)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT key, certs, retire_at, cross_signed FROM signer_retired_keys
+		WHERE signer_id = ? AND retire_at > ? ORDER BY rowid`, signerID, time.Now().UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	rotated := &rotatedSigner{signer: current}
+	for rows.Next() {
+		var (
+			retireAt int64
+			cross    []byte
+		)
+		if err := rows.Scan(&sealed, &certsPEM, &retireAt, &cross); err != nil {
+			return nil, err
+		}
+		old, err := s.parseSignerKey(signerID, sealed, certsPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load retired CA: %w", err)
+		}
+		rotated.retired = append(rotated.retired, retiredCA{signer: old, until: time.Unix(0, retireAt)})
+		if len(cross) > 0 {
+			certs, err := util.ParseCertificatesPEM(cross)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse persisted cross-signed CA certificate: %w", err)
+			}
+			rotated.crossSigned = certs[0]
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(rotated.retired) == 0 && rotated.crossSigned == nil {
+		return current, nil
+	}
+	return rotated, nil
+}
+
+// parseSignerKey opens a sealed CA key and checks that it matches its PEM
+// encoded certificates.
+func (s *Server) parseSignerKey(signerID int64, sealed, certsPEM []byte) (*inMemSigner, error) {
+	der, err := s.openKey(signerID, sealed)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse persisted CA key: %w", err)
+	}
+	key, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("persisted CA key cannot sign")
+	}
+	certs, err := util.ParseCertificatesPEM(certsPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse persisted CA certificates: %w", err)
+	}
+	match, err := util.KeyMatchesCertificate(key, certs[0])
+	if err != nil {
+		return nil, err
+	}
+	if !match {
+		return nil, errors.New("persisted CA key does not match its certificate")
+	}
+	return &inMemSigner{key: key, cert: certs[0], chain: certs[1:]}, nil
+}
+
+// sealKey encrypts a CA key with AES-GCM, bound to the signer it belongs to.
+func (s *Server) sealKey(signerID int64, plaintext []byte) ([]byte, error) {
+	aead, err := s.keyAEAD()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, []byte(strconv.FormatInt(signerID, 10))), nil
+}
+
+func (s *Server) openKey(signerID int64, sealed []byte) ([]byte, error) {
+	aead, err := s.keyAEAD()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("persisted CA key is truncated")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(strconv.FormatInt(signerID, 10)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt persisted CA key, is the key encryption key correct?: %w", err)
+	}
+	return plaintext, nil
+}
+
+func (s *Server) keyAEAD() (cipher.AEAD, error) {
+	if s.kek == nil {
+		return nil, errNoKeyEncryptionKey
+	}
+	block, err := aes.NewCipher(s.kek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+)
+
+func TestSealKey(t *testing.T) {
+	kek := bytes.Repeat([]byte{1}, KeyEncryptionKeySize)
+	otherKEK := bytes.Repeat([]byte{2}, KeyEncryptionKeySize)
+	plaintext := []byte("CA key")
+	sealer := &Server{kek: kek}
+	sealed, err := sealer.sealKey(1, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, plaintext) {
+		t.Fatal("sealed key contains the plaintext")
+	}
+
+	for _, tt := range []struct {
+		name     string
+		kek      []byte
+		signerID int64
+		sealed   []byte
+		wantErr  bool
+	}{
+		{"same signer and key", kek, 1, sealed, false},
+		{"other signer", kek, 2, sealed, true},
+		{"other key encryption key", otherKEK, 1, sealed, true},
+		{"no key encryption key", nil, 1, sealed, true},
+		{"truncated", kek, 1, sealed[:4], true},
+		{"tampered", kek, 1, append(append([]byte{}, sealed[:len(sealed)-1]...), sealed[len(sealed)-1]^1), true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opened, err := (&Server{kek: tt.kek}).openKey(tt.signerID, tt.sealed)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("opened the key")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(opened, plaintext) {
+				t.Errorf("opened %q, want %q", opened, plaintext)
+			}
+		})
+	}
+}
+
+func TestPersistedRotationSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	datastore := WithDatastore(filepath.Join(t.TempDir(), "northfoot.db"))
+	kek := WithKeyEncryptionKey(bytes.Repeat([]byte{1}, KeyEncryptionKeySize))
+	s := newTestServer(t, datastore, kek)
+	config := newTestSigner(1)
+	persist := true
+	config.PersistCaKey = &persist
+	createTestSigner(t, s, config)
+	crossSign := true
+	if _, err := s.RotateSigner(ctx, connect.NewRequest(&mgmtv1.RotateSignerRequest{Id: 1, Overlap: durationpb.New(time.Hour), CrossSign: &crossSign})); err != nil {
+		t.Fatal(err)
+	}
+	before, err := s.getSigner(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := newTestServer(t, datastore, kek)
+	after, err := restarted.getSigner(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, got := before.TrustBundle(), after.TrustBundle()
+	if len(got) != len(want) {
+		t.Fatalf("trust bundle after restart has %d certificates, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("trust bundle certificate %d changed on restart", i)
+		}
+	}
+	if cas := caSigners(after.signer); len(cas) != 2 {
+		t.Errorf("restarted signer has %d CAs, want the current and retired CA", len(cas))
+	}
+}
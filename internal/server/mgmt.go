@@ -18,11 +18,18 @@ package server
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"io"
 	"strconv"
+	"strings"
 
 	"github.com/bufbuild/connect-go"
+	"go.uber.org/zap"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
@@ -62,7 +69,7 @@ func (s *Server) GetSigner(ctx context.Context, req *connect.Request[mgmtv1.GetS
 		return nil, connect.NewError(connect.CodeInternal, errors.New("multiple signers with id "+strconv.Itoa(int(req.Msg.Id))+" found"))
 	}
 	return connect.NewResponse(&mgmtv1.GetSignerResponse{
-		Signer: signers[0],
+		Signer: redactSigner(signers[0]),
 	}), nil
 }
 
@@ -87,7 +94,10 @@ func (s *Server) ListSigners(ctx context.Context, req *connect.Request[emptypb.E
 		if err := protojson.Unmarshal([]byte(raw), signer); err != nil {
 			return nil, connect.NewError(connect.CodeInternal, err)
 		}
-		signers = append(signers, signer)
+		if !signerAllowed(ctx, signer.GetId()) {
+			continue
+		}
+		signers = append(signers, redactSigner(signer))
 	}
 	if err := rows.Err(); err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
@@ -103,11 +113,15 @@ func (s *Server) CreateSigner(ctx context.Context, req *connect.Request[mgmtv1.C
 	if err := validation.Signer(req.Msg.Signer); err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
+	if req.Msg.Signer.GetPersistCaKey() && s.kek == nil {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, errNoKeyEncryptionKey)
+	}
 	q, err := s.db.PrepareContext(ctx, "INSERT INTO signers (signer) VALUES (json(?))")
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 	defer q.Close()
+	req.Msg.Signer.Version = proto.Int64(1)
 	raw, err := protojson.Marshal(req.Msg.Signer)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
@@ -115,19 +129,211 @@ func (s *Server) CreateSigner(ctx context.Context, req *connect.Request[mgmtv1.C
 	if _, err := q.ExecContext(ctx, raw); err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
+	// create the CA now rather than on the signer's first request
+	s.keys.warm(req.Msg.Signer)
+	if _, err := s.populateSigner(ctx, req.Msg.Signer.GetId()); err != nil {
+		s.log.Warn("failed to create signer, retrying on first request", zap.Int64("signer_id", req.Msg.Signer.GetId()), zap.Error(err))
+	}
 	return &connect.Response[emptypb.Empty]{}, nil
 }
 
+// keySignerFields are the fields of a signer that determine its CA. Changing
+// them creates a new CA, while other fields are applied to the cached signer.
+var keySignerFields = map[string]bool{
+	"type":   true,
+	"in_mem": true,
+	"file":   true,
+	"hsm":    true,
+	"remote": true,
+}
+
+func (s *Server) UpdateSigner(ctx context.Context, req *connect.Request[mgmtv1.UpdateSignerRequest]) (*connect.Response[mgmtv1.UpdateSignerResponse], error) {
+	if req.Msg.Signer == nil || req.Msg.Signer.Id == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, validation.ErrMissingID)
+	}
+	id := *req.Msg.Signer.Id
+	paths := req.Msg.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("update mask is missing"))
+	}
+	if !req.Msg.UpdateMask.IsValid(&mgmtv1.Signer{}) {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("update mask has unknown fields"))
+	}
+	for _, path := range paths {
+		if field := strings.SplitN(path, ".", 2)[0]; field == "id" || field == "version" {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("signer "+field+" cannot be updated"))
+		}
+	}
+
+	var rowID int64
+	var raw string
+	err := s.db.QueryRowContext(ctx, "SELECT signers.rowid, signers.signer FROM signers, json_each(signers.signer) WHERE json_each.key = 'id' AND json_each.value = ?", strconv.Itoa(int(id))).Scan(&rowID, &raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("signer with id "+strconv.Itoa(int(id))+" not found"))
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	current := &mgmtv1.Signer{}
+	if err := protojson.Unmarshal([]byte(raw), current); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if req.Msg.Version != nil && *req.Msg.Version != current.GetVersion() {
+		return nil, connect.NewError(connect.CodeAborted, fmt.Errorf("signer has been modified, its version is %d", current.GetVersion()))
+	}
+
+	updated := proto.Clone(current).(*mgmtv1.Signer)
+	source := proto.Clone(req.Msg.Signer).(*mgmtv1.Signer)
+	for _, path := range paths {
+		applyFieldPath(updated.ProtoReflect(), source.ProtoReflect(), strings.Split(path, "."))
+	}
+	restoreSecrets(updated, current)
+	updated.Version = proto.Int64(current.GetVersion() + 1)
+	if err := validation.Signer(updated); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if updated.GetPersistCaKey() && s.kek == nil {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, errNoKeyEncryptionKey)
+	}
+	newKey := !proto.Equal(signerKeyConfig(current), signerKeyConfig(updated))
+	if newKey && !req.Msg.AllowNewKey {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, errors.New("update changes the signer's CA, set allow_new_key to replace it"))
+	}
+
+	rules, err := compileRules(updated.GetPolicy().GetCelRules())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	cached, found := s.signerCache.Load().(signerCache)[id]
+	var si signer
+	switch {
+	case newKey:
+		if si, err = newSigner(ctx, s.log, s.keys, updated); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("error creating signer: %w", err))
+		}
+	case found:
+		si = cached.signer
+	}
+
+	updatedRaw, err := protojson.Marshal(updated)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	committed := false
+	if newKey {
+		defer func() {
+			if !committed {
+				closeSigner(s.log, id, si)
+			}
+		}()
+	}
+	// the stored signer must not have changed since it was read
+	result, err := s.db.ExecContext(ctx, "UPDATE signers SET signer = json(?) WHERE rowid = ? AND signer = ?", updatedRaw, rowID, raw)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if n, err := result.RowsAffected(); err != nil || n != 1 {
+		return nil, connect.NewError(connect.CodeAborted, errors.New("signer was modified concurrently"))
+	}
+	committed = true
+
+	if si != nil {
+		constraints, err := signerNameConstraints(updated, si)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		s.lock.Lock()
+		oldCache := s.signerCache.Load().(signerCache)
+		newCache := make(signerCache)
+		for k, v := range oldCache {
+			newCache[k] = v
+		}
+		newCache[id] = &cachedSigner{config: updated, rules: rules, constraints: constraints, signer: si}
+		s.storeSignerCache(newCache)
+		s.lock.Unlock()
+	}
+	if newKey {
+		if found {
+			closeSigner(s.log, id, cached.signer)
+		}
+		// CRLs and OCSP responders belong to the old CA, SCEP RAs notice the
+		// new CA themselves
+		s.dropCRLs(id)
+		s.dropOCSPResponders(id)
+	}
+	if err := s.syncSignerKey(ctx, id, updated, si); err != nil {
+		s.log.Error("failed to persist signer CA key", zap.Int64("signer_id", id), zap.Error(err))
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("signer was updated but its CA key was not persisted: %w", err))
+	}
+	s.log.Info("updated signer", zap.Int64("id", id), zap.Int64("version", updated.GetVersion()), zap.Strings("fields", paths), zap.Bool("new_key", newKey))
+	return connect.NewResponse(&mgmtv1.UpdateSignerResponse{
+		Signer: redactSigner(updated),
+		NewKey: newKey,
+	}), nil
+}
+
+// applyFieldPath copies the field at path from src to dst, clearing it in
+// dst if it is unset in src.
+func applyFieldPath(dst, src protoreflect.Message, path []string) {
+	fd := dst.Descriptor().Fields().ByName(protoreflect.Name(path[0]))
+	if len(path) == 1 {
+		if src.Has(fd) {
+			dst.Set(fd, src.Get(fd))
+		} else {
+			dst.Clear(fd)
+		}
+		return
+	}
+	if !src.Has(fd) && !dst.Has(fd) {
+		return
+	}
+	applyFieldPath(dst.Mutable(fd).Message(), src.Get(fd).Message(), path[1:])
+}
+
+// restoreSecrets copies the secrets that redactSigner removes from current to
+// updated where the update left them empty, so that a signer read from the
+// API can be written back without losing them.
+func restoreSecrets(updated, current *mgmtv1.Signer) {
+	if updated.GetScepChallengePassword() == "" {
+		updated.ScepChallengePassword = current.ScepChallengePassword
+	}
+	// the PIN and token are only kept for the token and endpoint they belong to
+	hsm, old := updated.GetHsm(), current.GetHsm()
+	if hsm != nil && old != nil && hsm.GetHsmTokenPin() == "" && hsm.HsmLibraryPath == old.GetHsmLibraryPath() &&
+		hsm.GetHsmTokenSerial() == old.GetHsmTokenSerial() && hsm.GetHsmTokenLabel() == old.GetHsmTokenLabel() {
+		hsm.HsmTokenPin = old.HsmTokenPin
+	}
+	northfoot, oldNorthfoot := updated.GetRemote().GetNorthfoot(), current.GetRemote().GetNorthfoot()
+	if northfoot != nil && oldNorthfoot != nil && northfoot.GetToken() == "" && northfoot.Endpoint == oldNorthfoot.Endpoint {
+		northfoot.Token = oldNorthfoot.Token
+	}
+}
+
+// signerKeyConfig returns the fields of signer that determine its CA.
+func signerKeyConfig(signer *mgmtv1.Signer) *mgmtv1.Signer {
+	keyConfig := &mgmtv1.Signer{}
+	for field := range keySignerFields {
+		fd := keyConfig.ProtoReflect().Descriptor().Fields().ByName(protoreflect.Name(field))
+		if signer.ProtoReflect().Has(fd) {
+			keyConfig.ProtoReflect().Set(fd, signer.ProtoReflect().Get(fd))
+		}
+	}
+	return keyConfig
+}
+
 func (s *Server) DeleteSigner(ctx context.Context, req *connect.Request[mgmtv1.DeleteSignerRequest]) (*connect.Response[emptypb.Empty], error) {
-	q, err := s.db.PrepareContext(ctx, "DELETE FROM signers WHERE EXISTS (SELECT * FROM signers, json_each(signers.signer) WHERE json_each.key = 'id' AND json_each.value = ?)")
+	q, err := s.db.PrepareContext(ctx, "DELETE FROM signers WHERE json_extract(signer, '$.id') = ?")
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 	defer q.Close()
-	_, err = q.ExecContext(ctx, strconv.Itoa(int(req.Msg.Id)))
+	result, err := q.ExecContext(ctx, strconv.Itoa(int(req.Msg.Id)))
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("signer with id "+strconv.Itoa(int(req.Msg.Id))+" not found"))
+	}
 	s.lock.Lock()
 	oldCache := s.signerCache.Load().(signerCache)
 	newCache := make(signerCache)
@@ -136,7 +342,41 @@ func (s *Server) DeleteSigner(ctx context.Context, req *connect.Request[mgmtv1.D
 			newCache[k] = v
 		}
 	}
-	s.signerCache.Store(newCache)
+	s.storeSignerCache(newCache)
 	s.lock.Unlock()
+	if cached, found := oldCache[req.Msg.Id]; found {
+		closeSigner(s.log, req.Msg.Id, cached.signer)
+	}
+	s.dropCRLs(req.Msg.Id)
+	s.dropOCSPResponders(req.Msg.Id)
+	if err := s.deleteSignerKeys(ctx, req.Msg.Id); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
 	return &connect.Response[emptypb.Empty]{}, nil
 }
+
+// closeSigner releases any resources held by a signer that is no longer
+// cached.
+func closeSigner(log *zap.Logger, id int64, signer signer) {
+	closer, ok := signer.(io.Closer)
+	if !ok {
+		return
+	}
+	if err := closer.Close(); err != nil {
+		log.Warn("failed to close signer", zap.Int64("id", id), zap.Error(err))
+	}
+}
+
+// redactSigner returns a copy of signer with secrets removed, suitable for
+// returning to API clients.
+func redactSigner(signer *mgmtv1.Signer) *mgmtv1.Signer {
+	redacted := proto.Clone(signer).(*mgmtv1.Signer)
+	redacted.ScepChallengePassword = nil
+	if hsm := redacted.GetHsm(); hsm != nil {
+		hsm.HsmTokenPin = nil
+	}
+	if northfoot := redacted.GetRemote().GetNorthfoot(); northfoot != nil {
+		northfoot.Token = nil
+	}
+	return redacted
+}
@@ -0,0 +1,290 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/jakexks/northfoot/internal/authn"
+	"github.com/jakexks/northfoot/internal/server/profile"
+)
+
+const (
+	ocspResponseValidity  = time.Hour
+	ocspResponderValidity = 7 * 24 * time.Hour
+	maxOCSPRequestSize    = 1 << 14
+	// ocspResponderIdentity is recorded as the requester of delegated
+	// responder certificates that Northfoot issues to itself.
+	ocspResponderIdentity = "northfoot-ocsp-responder"
+)
+
+var ocspSigningProfile, _ = profile.Builtin(profile.OCSPSigning)
+
+// ocspResponder is a delegated OCSP responder certificate and key, issued by
+// one of the CAs of a signer.
+type ocspResponder struct {
+	key    crypto.Signer
+	cert   *x509.Certificate
+	issuer *x509.Certificate
+}
+
+// OCSPHandler serves RFC 6960 OCSP requests for each signer at
+// /ocsp/{id}, using either POST or GET with a base64 encoded request.
+func (s *Server) OCSPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/ocsp/"), "/", 2)
+		id, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		var der []byte
+		switch r.Method {
+		case http.MethodPost:
+			der, err = io.ReadAll(io.LimitReader(r.Body, maxOCSPRequestSize))
+		case http.MethodGet:
+			if len(parts) != 2 {
+				http.NotFound(w, r)
+				return
+			}
+			der, err = decodeOCSPGetRequest(parts[1])
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		if err != nil {
+			w.Write(ocsp.MalformedRequestErrorResponse)
+			return
+		}
+		req, err := ocsp.ParseRequest(der)
+		if err != nil {
+			w.Write(ocsp.MalformedRequestErrorResponse)
+			return
+		}
+		resp, err := s.ocspResponse(r.Context(), id, req)
+		if errors.Is(err, errSignerNotFound) || errors.Is(err, errWrongIssuer) {
+			w.Write(ocsp.UnauthorizedErrorResponse)
+			return
+		}
+		if err != nil {
+			s.log.Error("failed to create OCSP response", zap.Int64("signer_id", id), zap.Error(err))
+			w.Write(ocsp.InternalErrorErrorResponse)
+			return
+		}
+		w.Write(resp)
+	})
+}
+
+func decodeOCSPGetRequest(encoded string) ([]byte, error) {
+	unescaped, err := url.PathUnescape(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(unescaped)
+}
+
+func (s *Server) ocspServers(signerID int64) []string {
+	if s.baseURL == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s/ocsp/%d", s.baseURL, signerID)}
+}
+
+var errWrongIssuer = errors.New("OCSP request is for a different issuer")
+
+func (s *Server) ocspResponse(ctx context.Context, signerID int64, req *ocsp.Request) ([]byte, error) {
+	cached, err := s.getSigner(ctx, signerID)
+	if err != nil {
+		return nil, err
+	}
+	// certificates of retired CAs are answered by those CAs while they are
+	// still trusted
+	var ca signer
+	for _, candidate := range caSigners(cached.signer) {
+		if err := checkOCSPIssuer(req, candidate.TrustBundle()[0]); err == nil {
+			ca = candidate
+			break
+		} else if !errors.Is(err, errWrongIssuer) {
+			return nil, err
+		}
+	}
+	if ca == nil {
+		return nil, errWrongIssuer
+	}
+	issuer := ca.TrustBundle()[0]
+	now := time.Now()
+	template := ocsp.Response{
+		SerialNumber: req.SerialNumber,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(ocspResponseValidity),
+		IssuerHash:   req.HashAlgorithm,
+	}
+	if err := s.certificateStatus(ctx, signerID, issuer, req.SerialNumber, &template); err != nil {
+		return nil, err
+	}
+	if !cached.config.GetOcspDelegatedResponder() && issuer.PublicKeyAlgorithm != x509.Ed25519 {
+		return ca.OCSPResponse(template)
+	}
+	responder, err := s.getOCSPResponder(ctx, signerID, ca)
+	if err != nil {
+		return nil, err
+	}
+	template.Certificate = responder.cert
+	return ocsp.CreateResponse(issuer, responder.cert, template, responder.key)
+}
+
+func checkOCSPIssuer(req *ocsp.Request, issuer *x509.Certificate) error {
+	if !req.HashAlgorithm.Available() {
+		return errWrongIssuer
+	}
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(issuer.RawSubjectPublicKeyInfo, &spki); err != nil {
+		return err
+	}
+	h := req.HashAlgorithm.New()
+	h.Write(spki.PublicKey.RightAlign())
+	keyHash := h.Sum(nil)
+	h.Reset()
+	h.Write(issuer.RawSubject)
+	nameHash := h.Sum(nil)
+	if !bytes.Equal(keyHash, req.IssuerKeyHash) || !bytes.Equal(nameHash, req.IssuerNameHash) {
+		return errWrongIssuer
+	}
+	return nil
+}
+
+// certificateStatus fills in the status of serial in template from the
+// certificate inventory. Certificates issued by another CA of the signer are
+// unknown.
+func (s *Server) certificateStatus(ctx context.Context, signerID int64, issuer *x509.Certificate, serial *big.Int, template *ocsp.Response) error {
+	var (
+		issuerSerial string
+		raw          []byte
+		revokedAt    sql.NullInt64
+		reason       sql.NullInt32
+	)
+	err := s.db.QueryRowContext(ctx, `SELECT c.issuer_serial, c.cert, r.revoked_at, r.reason FROM certificates c
+		LEFT JOIN revocations r ON r.serial = c.serial
+		WHERE c.serial = ? AND c.signer_id = ?`, serialString(serial), signerID).Scan(&issuerSerial, &raw, &revokedAt, &reason)
+	switch {
+	case errors.Is(err, sql.ErrNoRows) || err == nil && !issuedBy(issuerSerial, raw, issuer):
+		template.Status = ocsp.Unknown
+	case err != nil:
+		return err
+	case revokedAt.Valid:
+		template.Status = ocsp.Revoked
+		template.RevokedAt = time.Unix(revokedAt.Int64, 0).UTC()
+		template.RevocationReason = int(reason.Int32)
+	default:
+		template.Status = ocsp.Good
+	}
+	return nil
+}
+
+// issuedBy reports whether a recorded certificate was issued by ca.
+// Certificates recorded without their issuer's serial are checked by
+// signature.
+func issuedBy(issuerSerial string, raw []byte, ca *x509.Certificate) bool {
+	if issuerSerial != "" {
+		return issuerSerial == serialString(ca.SerialNumber)
+	}
+	cert, err := x509.ParseCertificate(raw)
+	return err == nil && cert.CheckSignatureFrom(ca) == nil
+}
+
+// getOCSPResponder returns the delegated responder of one of the CAs of a
+// signer, issuing a new one if it is missing or half way to expiry.
+func (s *Server) getOCSPResponder(ctx context.Context, signerID int64, ca signer) (*ocspResponder, error) {
+	s.ocspLock.Lock()
+	defer s.ocspLock.Unlock()
+	issuer := ca.TrustBundle()[0]
+	id := caKey{signerID: signerID, serial: serialString(issuer.SerialNumber)}
+	if responder, found := s.responders[id]; found &&
+		responder.issuer.Equal(issuer) &&
+		time.Until(responder.cert.NotAfter) > ocspResponderValidity/2 {
+		return responder, nil
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			Organization: []string{"Northfoot"},
+			CommonName:   "Northfoot OCSP Responder",
+		},
+	}, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OCSP responder CSR: %w", err)
+	}
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := ca.Sign(ctx, csr, signOptions{
+		durationHint: ocspResponderValidity,
+		profile:      ocspSigningProfile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue OCSP responder certificate: %w", err)
+	}
+	if err := s.recordCertificate(authn.WithIdentity(ctx, ocspResponderIdentity), signerID, issuer, csrDER, cert); err != nil {
+		return nil, err
+	}
+	responder := &ocspResponder{key: key, cert: cert, issuer: issuer}
+	s.responders[id] = responder
+	return responder, nil
+}
+
+// dropOCSPResponders forgets the delegated responders of every CA of a
+// signer.
+func (s *Server) dropOCSPResponders(signerID int64) {
+	s.ocspLock.Lock()
+	defer s.ocspLock.Unlock()
+	for key := range s.responders {
+		if key.signerID == signerID {
+			delete(s.responders, key)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"golang.org/x/crypto/ocsp"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+)
+
+// issueTestLeaf issues a leaf from a signer and returns it with the CA that
+// issued it.
+func issueTestLeaf(t *testing.T, s *Server, signerID int64) (*x509.Certificate, *x509.Certificate) {
+	t.Helper()
+	resp, err := s.Sign(context.Background(), connect.NewRequest(&signv1.SignRequest{
+		SignerId: signerID,
+		Csr:      newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "leaf"}}),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(resp.Msg.Cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := s.getSigner(context.Background(), signerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf, cached.TrustBundle()[0]
+}
+
+func TestOCSPHandler(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	createTestSigner(t, s, newTestSigner(1))
+	delegated := newTestSigner(2)
+	allow := true
+	delegated.OcspDelegatedResponder = &allow
+	createTestSigner(t, s, delegated)
+
+	oldLeaf, oldCA := issueTestLeaf(t, s, 1)
+	revoked, _ := issueTestLeaf(t, s, 1)
+	if _, err := s.RevokeCertificate(ctx, connect.NewRequest(&mgmtv1.RevokeCertificateRequest{
+		Serial: serialString(revoked.SerialNumber),
+		Reason: mgmtv1.RevocationReason_REVOCATION_REASON_KEY_COMPROMISE,
+	})); err != nil {
+		t.Fatal(err)
+	}
+	legacy, _ := issueTestLeaf(t, s, 1)
+	// certificates recorded before issuers were are matched by signature
+	if _, err := s.db.Exec(`UPDATE certificates SET issuer_serial = '' WHERE serial = ?`, serialString(legacy.SerialNumber)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RotateSigner(ctx, connect.NewRequest(&mgmtv1.RotateSignerRequest{Id: 1, Overlap: durationpb.New(time.Hour)})); err != nil {
+		t.Fatal(err)
+	}
+	newLeaf, newCA := issueTestLeaf(t, s, 1)
+	delegatedLeaf, delegatedCA := issueTestLeaf(t, s, 2)
+	unrecorded := &x509.Certificate{SerialNumber: big.NewInt(42)}
+
+	tests := []struct {
+		name     string
+		signerID int64
+		leaf     *x509.Certificate
+		ca       *x509.Certificate
+		get      bool
+		// status is -1 for an unauthorized response
+		status int
+	}{
+		{"good", 1, newLeaf, newCA, false, ocsp.Good},
+		{"good over GET", 1, newLeaf, newCA, true, ocsp.Good},
+		{"retired CA", 1, oldLeaf, oldCA, false, ocsp.Good},
+		{"revoked", 1, revoked, oldCA, false, ocsp.Revoked},
+		{"unknown serial", 1, unrecorded, newCA, false, ocsp.Unknown},
+		{"issued by the retired CA, asked of the current CA", 1, oldLeaf, newCA, false, ocsp.Unknown},
+		{"issued by the current CA, asked of the retired CA", 1, newLeaf, oldCA, false, ocsp.Unknown},
+		{"legacy record", 1, legacy, oldCA, false, ocsp.Good},
+		{"legacy record asked of another CA", 1, legacy, newCA, false, ocsp.Unknown},
+		{"delegated responder", 2, delegatedLeaf, delegatedCA, false, ocsp.Good},
+		{"issuer of another signer", 1, delegatedLeaf, delegatedCA, false, -1},
+		{"unknown signer", 9, newLeaf, newCA, false, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			der, err := ocsp.CreateRequest(tt.leaf, tt.ca, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			target := "/ocsp/" + strconv.FormatInt(tt.signerID, 10)
+			req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(der))
+			if tt.get {
+				req = httptest.NewRequest(http.MethodGet, target+"/"+url.PathEscape(base64.StdEncoding.EncodeToString(der)), nil)
+			}
+			rec := httptest.NewRecorder()
+			s.OCSPHandler().ServeHTTP(rec, req)
+			if tt.status == -1 {
+				if !bytes.Equal(rec.Body.Bytes(), ocsp.UnauthorizedErrorResponse) {
+					t.Errorf("response = %x, want unauthorized", rec.Body.Bytes())
+				}
+				return
+			}
+			resp, err := ocsp.ParseResponseForCert(rec.Body.Bytes(), tt.leaf, tt.ca)
+			if err != nil {
+				t.Fatalf("OCSP response is not signed for the CA: %v", err)
+			}
+			if resp.Status != tt.status {
+				t.Errorf("status = %d, want %d", resp.Status, tt.status)
+			}
+			if tt.status == ocsp.Revoked && resp.RevocationReason != ocsp.KeyCompromise {
+				t.Errorf("revocation reason = %d, want %d", resp.RevocationReason, ocsp.KeyCompromise)
+			}
+		})
+	}
+}
@@ -17,7 +17,14 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
 package server
 
-import "go.uber.org/zap"
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/jakexks/northfoot/internal/authn"
+)
 
 type ServerOption func(*Server) error
 
@@ -34,3 +41,54 @@ func WithLogger(logger *zap.Logger) ServerOption {
 		return nil
 	}
 }
+
+// WithBaseURL sets the externally reachable URL of the server, used to stamp
+// CRL distribution points and OCSP responder URLs onto issued certificates.
+func WithBaseURL(baseURL string) ServerOption {
+	return func(s *Server) error {
+		s.baseURL = strings.TrimSuffix(baseURL, "/")
+		return nil
+	}
+}
+
+// WithBasicAuth sets the HTTP basic credentials accepted by the EST
+// endpoints, in addition to client certificates.
+func WithBasicAuth(auth authn.BasicAuthFunc) ServerOption {
+	return func(s *Server) error {
+		s.basicAuth = auth
+		return nil
+	}
+}
+
+// WithKeyEncryptionKey sets the AES-256 key that encrypts the CA keys of
+// signers with persist_ca_key set.
+func WithKeyEncryptionKey(kek []byte) ServerOption {
+	return func(s *Server) error {
+		if len(kek) != KeyEncryptionKeySize {
+			return fmt.Errorf("key encryption key must be %d bytes, got %d", KeyEncryptionKeySize, len(kek))
+		}
+		s.kek = kek
+		return nil
+	}
+}
+
+// WithKeyEncryptionPassphrase derives the key encryption key from a
+// passphrase with Argon2id, instead of WithKeyEncryptionKey.
+func WithKeyEncryptionPassphrase(passphrase string) ServerOption {
+	return func(s *Server) error {
+		s.kekPassphrase = passphrase
+		return nil
+	}
+}
+
+// WithKeyPoolSize sets how many CA keys of each type and size in use are
+// generated ahead of time, 0 to generate keys on demand.
+func WithKeyPoolSize(size int) ServerOption {
+	return func(s *Server) error {
+		if size < 0 {
+			return fmt.Errorf("key pool size must not be negative, got %d", size)
+		}
+		s.keyPoolSize = size
+		return nil
+	}
+}
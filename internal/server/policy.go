@@ -0,0 +1,364 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"go.uber.org/zap"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+	"github.com/jakexks/northfoot/internal/authn"
+	"github.com/jakexks/northfoot/internal/server/expr"
+	"github.com/jakexks/northfoot/internal/server/profile"
+)
+
+// checkPolicy returns an error naming the first rule of policy that csr
+// breaks, otherwise the lifetime to issue the certificate with. identity is
+// the authenticated caller, or empty if there is none.
+func checkPolicy(policy *mgmtv1.IssuancePolicy, identity string, csr *x509.CertificateRequest, lifetime time.Duration) (time.Duration, error) {
+	if policy == nil {
+		return lifetime, nil
+	}
+	if err := checkSubjectAttributes(csr.RawSubject); err != nil {
+		return 0, err
+	}
+	if lifetime == 0 {
+		lifetime = defaultLifetime
+		if policy.DefaultLifetime != nil {
+			lifetime = policy.DefaultLifetime.AsDuration()
+		} else if policy.MaxLifetime != nil && lifetime > policy.MaxLifetime.AsDuration() {
+			lifetime = policy.MaxLifetime.AsDuration()
+		}
+	}
+	if policy.MaxLifetime != nil && lifetime > policy.MaxLifetime.AsDuration() {
+		return 0, fmt.Errorf("requested lifetime %s exceeds the maximum of %s", lifetime, policy.MaxLifetime.AsDuration())
+	}
+	restricted := len(policy.AllowedDnsNames) > 0 || len(policy.AllowedIpRanges) > 0 ||
+		len(policy.AllowedUris) > 0 || len(policy.AllowedEmailDomains) > 0
+	for _, name := range csr.DNSNames {
+		if err := checkSAN("DNS name", name, restricted, policy.AllowedDnsNames, policy.DeniedDnsNames, matchDomain); err != nil {
+			return 0, err
+		}
+	}
+	// clients that fall back to the common name treat it as a DNS name,
+	// unless it repeats one of the other SANs checked below
+	if cn := csr.Subject.CommonName; cn != "" && !commonNameIsSAN(csr, cn) {
+		if err := checkSAN("subject common name", cn, restricted, policy.AllowedDnsNames, policy.DeniedDnsNames, matchDomain); err != nil {
+			return 0, err
+		}
+	}
+	for _, ip := range csr.IPAddresses {
+		if err := checkSAN("IP address", ip.String(), restricted, policy.AllowedIpRanges, policy.DeniedIpRanges, matchCIDR); err != nil {
+			return 0, err
+		}
+	}
+	for _, uri := range csr.URIs {
+		if err := checkSAN("URI", uri.String(), restricted, policy.AllowedUris, policy.DeniedUris, matchGlob); err != nil {
+			return 0, err
+		}
+	}
+	for _, email := range csr.EmailAddresses {
+		domain := email[strings.LastIndex(email, "@")+1:]
+		if err := checkSAN("email address", email, restricted, policy.AllowedEmailDomains, policy.DeniedEmailDomains, func(pattern, _ string) bool {
+			return matchDomain(pattern, domain)
+		}); err != nil {
+			return 0, err
+		}
+	}
+	for _, rule := range policy.SubjectRules {
+		if err := checkSubjectRule(rule, csr.Subject); err != nil {
+			return 0, err
+		}
+	}
+	if len(policy.IdentityRules) > 0 {
+		if err := checkIdentityRules(policy.IdentityRules, identity, csr); err != nil {
+			return 0, err
+		}
+	}
+	return lifetime, nil
+}
+
+func compileRules(rules []*mgmtv1.CELRule) ([]*expr.Rule, error) {
+	compiled := make([]*expr.Rule, 0, len(rules))
+	for _, rule := range rules {
+		c, err := expr.Compile(rule.Name, rule.Expression)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled, nil
+}
+
+// checkRules evaluates the CEL rules of signer against a request, logging
+// the rule that denied it.
+func (s *Server) checkRules(ctx context.Context, signer *cachedSigner, req *signv1.SignRequest, p *profile.Profile, csr *x509.CertificateRequest, identity string, lifetime time.Duration) error {
+	if len(signer.rules) == 0 {
+		return nil
+	}
+	in := &expr.Input{
+		CSR:       csr,
+		Requester: identity,
+		Metadata: map[string]string{
+			"protocol":  protocolFromContext(ctx),
+			"signer_id": strconv.FormatInt(req.SignerId, 10),
+			"profile":   p.Name,
+		},
+		Duration: lifetime,
+		CA:       p.CA,
+	}
+	for _, rule := range signer.rules {
+		allowed, err := rule.Allows(in)
+		if err != nil {
+			s.log.Warn("failed to evaluate issuance rule", zap.Int64("signer_id", req.SignerId), zap.String("rule", rule.Name), zap.String("requester", identity), zap.Error(err))
+			return connect.NewError(connect.CodePermissionDenied, err)
+		}
+		if !allowed {
+			s.log.Info("issuance rule denied request", zap.Int64("signer_id", req.SignerId), zap.String("rule", rule.Name), zap.String("requester", identity))
+			return connect.NewError(connect.CodePermissionDenied, fmt.Errorf("denied by rule %q", rule.Name))
+		}
+	}
+	return nil
+}
+
+// checkIdentityRules checks csr against the first of rules that matches
+// identity.
+func checkIdentityRules(rules []*mgmtv1.IdentityRule, identity string, csr *x509.CertificateRequest) error {
+	if identity == "" {
+		return errors.New("identity rules require an authenticated caller")
+	}
+	for _, rule := range rules {
+		template, err := authn.ParseIdentityTemplate(rule.Identity)
+		if err != nil {
+			return err
+		}
+		if vars, ok := template.Match(identity); ok {
+			return checkIdentityRule(rule, identity, vars, csr)
+		}
+	}
+	return fmt.Errorf("identity %q does not match any identity rule", identity)
+}
+
+func checkIdentityRule(rule *mgmtv1.IdentityRule, identity string, vars map[string]string, csr *x509.CertificateRequest) error {
+	// a variable containing dots could otherwise let one identity claim
+	// the names of another, e.g. {sa}.{ns} for sa "a.b", ns "c" and for
+	// sa "a", ns "b.c"
+	labels := make(map[string]string, len(vars))
+	for name, value := range vars {
+		if !strings.Contains(value, ".") {
+			labels[name] = value
+		}
+	}
+	dnsNames := expandTemplates(rule.DnsNames, labels)
+	for _, name := range csr.DNSNames {
+		if !containsFold(dnsNames, name) {
+			return fmt.Errorf("DNS name %q is not allowed for %s", name, identity)
+		}
+	}
+	uris := expandTemplates(rule.Uris, vars)
+	for _, uri := range csr.URIs {
+		if !containsFold(uris, uri.String()) {
+			return fmt.Errorf("URI %q is not allowed for %s", uri, identity)
+		}
+	}
+	emails := expandTemplates(rule.EmailAddresses, labels)
+	for _, email := range csr.EmailAddresses {
+		if !containsFold(emails, email) {
+			return fmt.Errorf("email address %q is not allowed for %s", email, identity)
+		}
+	}
+	if cn := csr.Subject.CommonName; cn != "" && !containsFold(append(expandTemplates(rule.CommonNames, labels), dnsNames...), cn) {
+		return fmt.Errorf("subject common name %q is not allowed for %s", cn, identity)
+	}
+	return nil
+}
+
+// expandTemplates expands each of templates that only uses vars.
+func expandTemplates(templates []string, vars map[string]string) []string {
+	var expanded []string
+	for _, template := range templates {
+		if value, err := authn.ExpandTemplate(template, vars); err == nil {
+			expanded = append(expanded, value)
+		}
+	}
+	return expanded
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// commonNameIsSAN reports whether cn is one of the IP address, URI or email
+// SANs of csr.
+func commonNameIsSAN(csr *x509.CertificateRequest, cn string) bool {
+	for _, ip := range csr.IPAddresses {
+		if ip.String() == cn {
+			return true
+		}
+	}
+	for _, uri := range csr.URIs {
+		if uri.String() == cn {
+			return true
+		}
+	}
+	return containsFold(csr.EmailAddresses, cn)
+}
+
+func checkSAN(kind, value string, restricted bool, allowed, denied []string, match func(pattern, value string) bool) error {
+	for _, pattern := range denied {
+		if match(pattern, value) {
+			return fmt.Errorf("%s %q is denied by %q", kind, value, pattern)
+		}
+	}
+	if !restricted {
+		return nil
+	}
+	for _, pattern := range allowed {
+		if match(pattern, value) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %q is not allowed by policy", kind, value)
+}
+
+// matchDomain matches name exactly, or beneath the domain of a "*." pattern.
+func matchDomain(pattern, name string) bool {
+	pattern, name = strings.ToLower(pattern), strings.ToLower(name)
+	if strings.HasPrefix(pattern, "*.") {
+		return strings.HasSuffix(name, pattern[1:])
+	}
+	return name == pattern
+}
+
+func matchCIDR(pattern, ip string) bool {
+	_, ipNet, err := net.ParseCIDR(pattern)
+	return err == nil && ipNet.Contains(net.ParseIP(ip))
+}
+
+func matchGlob(pattern, value string) bool {
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
+
+// subjectAttributes are the subject attributes that policies and CEL rules
+// see, by OID, and whether each may be repeated. Only the last of repeated
+// single valued attributes is parsed.
+var subjectAttributes = map[string]struct {
+	name       string
+	repeatable bool
+}{
+	"2.5.4.3":  {"common name", false},
+	"2.5.4.5":  {"serial number", false},
+	"2.5.4.6":  {"country", true},
+	"2.5.4.7":  {"locality", true},
+	"2.5.4.8":  {"province", true},
+	"2.5.4.10": {"organization", true},
+	"2.5.4.11": {"organizational unit", true},
+}
+
+// checkSubjectAttributes refuses subjects with attributes that policies
+// cannot see, since the subject is copied to the certificate as requested.
+func checkSubjectAttributes(rawSubject []byte) error {
+	var rdns pkix.RDNSequence
+	if rest, err := asn1.Unmarshal(rawSubject, &rdns); err != nil || len(rest) > 0 {
+		return errors.New("subject is malformed")
+	}
+	seen := make(map[string]bool)
+	for _, rdn := range rdns {
+		for _, attr := range rdn {
+			oid := attr.Type.String()
+			attribute, known := subjectAttributes[oid]
+			if !known {
+				return fmt.Errorf("subject attribute %s is not supported by policy", oid)
+			}
+			if seen[oid] && !attribute.repeatable {
+				return fmt.Errorf("subject %s must not be repeated", attribute.name)
+			}
+			seen[oid] = true
+		}
+	}
+	return nil
+}
+
+func checkSubjectRule(rule *mgmtv1.SubjectRule, subject pkix.Name) error {
+	values := subjectField(subject, rule.Field)
+	if rule.Required && len(values) == 0 {
+		return fmt.Errorf("subject %s is required", subjectFieldName(rule.Field))
+	}
+	for _, value := range values {
+		allowed := false
+		for _, pattern := range rule.AllowedValues {
+			if matchGlob(pattern, value) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("subject %s %q is not allowed by policy", subjectFieldName(rule.Field), value)
+		}
+	}
+	return nil
+}
+
+func subjectField(subject pkix.Name, field mgmtv1.SubjectField) []string {
+	switch field {
+	case mgmtv1.SubjectField_SUBJECT_FIELD_COMMON_NAME:
+		if subject.CommonName == "" {
+			return nil
+		}
+		return []string{subject.CommonName}
+	case mgmtv1.SubjectField_SUBJECT_FIELD_ORGANIZATION:
+		return subject.Organization
+	case mgmtv1.SubjectField_SUBJECT_FIELD_ORGANIZATIONAL_UNIT:
+		return subject.OrganizationalUnit
+	case mgmtv1.SubjectField_SUBJECT_FIELD_COUNTRY:
+		return subject.Country
+	case mgmtv1.SubjectField_SUBJECT_FIELD_PROVINCE:
+		return subject.Province
+	case mgmtv1.SubjectField_SUBJECT_FIELD_LOCALITY:
+		return subject.Locality
+	case mgmtv1.SubjectField_SUBJECT_FIELD_SERIAL_NUMBER:
+		if subject.SerialNumber == "" {
+			return nil
+		}
+		return []string{subject.SerialNumber}
+	}
+	return nil
+}
+
+func subjectFieldName(field mgmtv1.SubjectField) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(field.String(), "SUBJECT_FIELD_"), "_", " "))
+}
@@ -0,0 +1,250 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+	"github.com/jakexks/northfoot/internal/authn"
+)
+
+func TestCheckPolicy(t *testing.T) {
+	spiffe := func(s string) []*url.URL {
+		u, err := url.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []*url.URL{u}
+	}
+	oidCommonName := asn1.ObjectIdentifier{2, 5, 4, 3}
+	oidEmailAddress := asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}
+	names := &mgmtv1.IssuancePolicy{
+		AllowedDnsNames:     []string{"*.edge.local", "edge.local"},
+		DeniedDnsNames:      []string{"admin.edge.local"},
+		AllowedIpRanges:     []string{"10.0.0.0/8"},
+		AllowedUris:         []string{"spiffe://edge/*"},
+		AllowedEmailDomains: []string{"edge.local"},
+	}
+	subject := &mgmtv1.IssuancePolicy{
+		SubjectRules: []*mgmtv1.SubjectRule{
+			{Field: mgmtv1.SubjectField_SUBJECT_FIELD_COMMON_NAME, AllowedValues: []string{"allowed.edge.local"}},
+			{Field: mgmtv1.SubjectField_SUBJECT_FIELD_ORGANIZATION, AllowedValues: []string{"Edge"}, Required: true},
+		},
+	}
+	lifetimes := &mgmtv1.IssuancePolicy{
+		DefaultLifetime: durationpb.New(time.Hour),
+		MaxLifetime:     durationpb.New(24 * time.Hour),
+	}
+	identity := &mgmtv1.IssuancePolicy{
+		IdentityRules: []*mgmtv1.IdentityRule{{
+			Identity: "spiffe://edge/ns/{ns}/sa/{sa}",
+			DnsNames: []string{"{sa}.{ns}.svc"},
+			Uris:     []string{"spiffe://edge/ns/{ns}/sa/{sa}"},
+		}},
+	}
+
+	tests := []struct {
+		name         string
+		policy       *mgmtv1.IssuancePolicy
+		identity     string
+		csr          *x509.CertificateRequest
+		lifetime     time.Duration
+		wantLifetime time.Duration
+		wantErr      bool
+	}{
+		{name: "no policy", csr: &x509.CertificateRequest{DNSNames: []string{"anything"}}, lifetime: time.Minute, wantLifetime: time.Minute},
+		{name: "allowed names", policy: names, lifetime: time.Minute, wantLifetime: time.Minute, csr: &x509.CertificateRequest{
+			DNSNames:       []string{"a.edge.local", "edge.local"},
+			IPAddresses:    []net.IP{net.ParseIP("10.1.2.3")},
+			URIs:           spiffe("spiffe://edge/workload"),
+			EmailAddresses: []string{"ops@edge.local"},
+		}},
+		{name: "DNS name outside allow list", policy: names, csr: &x509.CertificateRequest{DNSNames: []string{"example.com"}}, wantErr: true},
+		{name: "denied DNS name", policy: names, csr: &x509.CertificateRequest{DNSNames: []string{"admin.edge.local"}}, wantErr: true},
+		{name: "IP address outside range", policy: names, csr: &x509.CertificateRequest{IPAddresses: []net.IP{net.ParseIP("192.168.0.1")}}, wantErr: true},
+		{name: "URI outside allow list", policy: names, csr: &x509.CertificateRequest{URIs: spiffe("spiffe://other/workload")}, wantErr: true},
+		{name: "email outside allowed domains", policy: names, csr: &x509.CertificateRequest{EmailAddresses: []string{"ops@example.com"}}, wantErr: true},
+		{name: "common name outside allow list", policy: names, csr: &x509.CertificateRequest{Subject: pkix.Name{CommonName: "google.com"}}, wantErr: true},
+		{name: "denied common name", policy: names, csr: &x509.CertificateRequest{Subject: pkix.Name{CommonName: "admin.edge.local"}, DNSNames: []string{"a.edge.local"}}, wantErr: true},
+		{name: "allowed common name", policy: names, wantLifetime: defaultLifetime, csr: &x509.CertificateRequest{Subject: pkix.Name{CommonName: "a.edge.local"}}},
+		{name: "common name repeating an IP address", policy: names, wantLifetime: defaultLifetime, csr: &x509.CertificateRequest{
+			Subject:     pkix.Name{CommonName: "10.1.2.3"},
+			IPAddresses: []net.IP{net.ParseIP("10.1.2.3")},
+		}},
+		{name: "common name repeating an email address", policy: names, wantLifetime: defaultLifetime, csr: &x509.CertificateRequest{
+			Subject:        pkix.Name{CommonName: "ops@edge.local"},
+			EmailAddresses: []string{"ops@edge.local"},
+		}},
+		{name: "allowed subject", policy: subject, wantLifetime: defaultLifetime, csr: &x509.CertificateRequest{Subject: pkix.Name{CommonName: "allowed.edge.local", Organization: []string{"Edge"}}}},
+		{name: "subject not allowed", policy: subject, csr: &x509.CertificateRequest{Subject: pkix.Name{CommonName: "other", Organization: []string{"Edge"}}}, wantErr: true},
+		{name: "required subject field missing", policy: subject, csr: &x509.CertificateRequest{Subject: pkix.Name{CommonName: "allowed.edge.local"}}, wantErr: true},
+		{name: "repeated common name", policy: subject, wantErr: true, csr: &x509.CertificateRequest{Subject: pkix.Name{
+			Organization: []string{"Edge"},
+			ExtraNames: []pkix.AttributeTypeAndValue{
+				{Type: oidCommonName, Value: "other"},
+				{Type: oidCommonName, Value: "allowed.edge.local"},
+			},
+		}}},
+		{name: "unsupported subject attribute", policy: subject, wantErr: true, csr: &x509.CertificateRequest{Subject: pkix.Name{
+			CommonName:   "allowed.edge.local",
+			Organization: []string{"Edge"},
+			ExtraNames:   []pkix.AttributeTypeAndValue{{Type: oidEmailAddress, Value: "ops@edge.local"}},
+		}}},
+		{name: "default lifetime", policy: lifetimes, csr: &x509.CertificateRequest{}, wantLifetime: time.Hour},
+		{name: "requested lifetime", policy: lifetimes, csr: &x509.CertificateRequest{}, lifetime: 2 * time.Hour, wantLifetime: 2 * time.Hour},
+		{name: "lifetime above maximum", policy: lifetimes, csr: &x509.CertificateRequest{}, lifetime: 48 * time.Hour, wantErr: true},
+		{name: "identity rule", policy: identity, identity: "spiffe://edge/ns/team/sa/web", wantLifetime: defaultLifetime, csr: &x509.CertificateRequest{
+			DNSNames: []string{"web.team.svc"},
+			URIs:     spiffe("spiffe://edge/ns/team/sa/web"),
+		}},
+		{name: "identity claiming another workload", policy: identity, identity: "spiffe://edge/ns/team/sa/web", csr: &x509.CertificateRequest{
+			URIs: spiffe("spiffe://edge/ns/team/sa/db"),
+		}, wantErr: true},
+		{name: "identity rule without caller", policy: identity, csr: &x509.CertificateRequest{DNSNames: []string{"web.team.svc"}}, wantErr: true},
+		{name: "identity matching no rule", policy: identity, identity: "spiffe://other/x", csr: &x509.CertificateRequest{}, wantErr: true},
+		{name: "identity rule common name", policy: identity, identity: "spiffe://edge/ns/team/sa/web", wantLifetime: defaultLifetime, csr: &x509.CertificateRequest{
+			Subject: pkix.Name{CommonName: "web.team.svc"},
+		}},
+		{name: "identity rule other common name", policy: identity, identity: "spiffe://edge/ns/team/sa/web", csr: &x509.CertificateRequest{
+			Subject: pkix.Name{CommonName: "db.team.svc"},
+		}, wantErr: true},
+		{name: "identity variable containing a dot", policy: identity, identity: "spiffe://edge/ns/team/sa/web.other", csr: &x509.CertificateRequest{
+			DNSNames: []string{"web.other.team.svc"},
+		}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csr, err := x509.ParseCertificateRequest(newTestCSR(t, tt.csr))
+			if err != nil {
+				t.Fatal(err)
+			}
+			lifetime, err := checkPolicy(tt.policy, tt.identity, csr, tt.lifetime)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && lifetime != tt.wantLifetime {
+				t.Errorf("checkPolicy() lifetime = %s, want %s", lifetime, tt.wantLifetime)
+			}
+		})
+	}
+}
+
+func TestSignIdentityRules(t *testing.T) {
+	s := newTestServer(t)
+	signer := newTestSigner(1)
+	signer.Policy = &mgmtv1.IssuancePolicy{
+		IdentityRules: []*mgmtv1.IdentityRule{{
+			Identity: "spiffe://edge/ns/{ns}/sa/{sa}",
+			DnsNames: []string{"{sa}.{ns}.svc"},
+		}},
+	}
+	createTestSigner(t, s, signer)
+	sign := func(ctx context.Context, dnsName string) error {
+		_, err := s.Sign(ctx, connect.NewRequest(&signv1.SignRequest{
+			SignerId: 1,
+			Csr:      newTestCSR(t, &x509.CertificateRequest{DNSNames: []string{dnsName}}),
+		}))
+		return err
+	}
+	web := authn.WithIdentity(context.Background(), "spiffe://edge/ns/team/sa/web")
+	if err := sign(web, "web.team.svc"); err != nil {
+		t.Errorf("Sign() for the caller's own name: %v", err)
+	}
+	if err := sign(web, "db.team.svc"); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("Sign() for another workload's name = %v, want PermissionDenied", err)
+	}
+	if err := sign(context.Background(), "web.team.svc"); connect.CodeOf(err) != connect.CodePermissionDenied {
+		t.Errorf("Sign() without an identity = %v, want PermissionDenied", err)
+	}
+
+	for name, rule := range map[string]*mgmtv1.IdentityRule{
+		"missing identity":     {DnsNames: []string{"web.svc"}},
+		"repeated variable":    {Identity: "spiffe://edge/ns/{ns}/sa/{ns}"},
+		"unterminated":         {Identity: "spiffe://edge/ns/{ns"},
+		"uncaptured variable":  {Identity: "spiffe://edge/ns/{ns}", DnsNames: []string{"{sa}.{ns}.svc"}},
+		"uncaptured in a URI":  {Identity: "spiffe://edge/ns/{ns}", Uris: []string{"spiffe://edge/ns/{ns}/sa/{sa}"}},
+		"uncaptured in a name": {Identity: "spiffe://edge/ns/{ns}", CommonNames: []string{"{sa}"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			signer := newTestSigner(2)
+			signer.Policy = &mgmtv1.IssuancePolicy{IdentityRules: []*mgmtv1.IdentityRule{rule}}
+			_, err := s.CreateSigner(context.Background(), connect.NewRequest(&mgmtv1.CreateSignerRequest{Signer: signer}))
+			if connect.CodeOf(err) != connect.CodeInvalidArgument {
+				t.Errorf("CreateSigner() = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestSignCELRules(t *testing.T) {
+	s := newTestServer(t)
+	signer := newTestSigner(1)
+	signer.Policy = &mgmtv1.IssuancePolicy{
+		CelRules: []*mgmtv1.CELRule{
+			{Name: "edge-only", Expression: `dns_names.all(n, n.endsWith(".edge.local"))`},
+			{Name: "short-lived", Expression: `duration <= duration("24h")`},
+		},
+	}
+	createTestSigner(t, s, signer)
+	sign := func(dnsName string, lifetime time.Duration) error {
+		_, err := s.Sign(context.Background(), connect.NewRequest(&signv1.SignRequest{
+			SignerId:     1,
+			Csr:          newTestCSR(t, &x509.CertificateRequest{DNSNames: []string{dnsName}}),
+			DurationHint: durationpb.New(lifetime),
+		}))
+		return err
+	}
+	if err := sign("web.edge.local", time.Hour); err != nil {
+		t.Errorf("Sign() allowed by every rule: %v", err)
+	}
+	if err := sign("example.com", time.Hour); connect.CodeOf(err) != connect.CodePermissionDenied || !strings.Contains(err.Error(), "edge-only") {
+		t.Errorf("Sign() outside the domain = %v, want PermissionDenied naming edge-only", err)
+	}
+	if err := sign("web.edge.local", 48*time.Hour); connect.CodeOf(err) != connect.CodePermissionDenied || !strings.Contains(err.Error(), "short-lived") {
+		t.Errorf("Sign() for too long = %v, want PermissionDenied naming short-lived", err)
+	}
+
+	for name, rules := range map[string][]*mgmtv1.CELRule{
+		"syntax error":   {{Name: "broken", Expression: `dns_names.all(n, `}},
+		"not a bool":     {{Name: "size", Expression: `size(dns_names)`}},
+		"missing name":   {{Expression: `true`}},
+		"duplicate name": {{Name: "a", Expression: `true`}, {Name: "a", Expression: `false`}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			signer := newTestSigner(2)
+			signer.Policy = &mgmtv1.IssuancePolicy{CelRules: rules}
+			_, err := s.CreateSigner(context.Background(), connect.NewRequest(&mgmtv1.CreateSignerRequest{Signer: signer}))
+			if connect.CodeOf(err) != connect.CodeInvalidArgument {
+				t.Errorf("CreateSigner() = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
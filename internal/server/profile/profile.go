@@ -0,0 +1,217 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package profile resolves the named sets of extensions that signers stamp
+// into the certificates they issue.
+package profile
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"strconv"
+	"strings"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+)
+
+// Names of the built-in profiles.
+const (
+	Server         = "server"
+	Client         = "client"
+	CodeSigning    = "code-signing"
+	Email          = "email"
+	OCSPSigning    = "ocsp-signing"
+	IntermediateCA = "intermediate-ca"
+)
+
+var oidExtensionOCSPNoCheck = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 5}
+
+// Profile is the set of extensions stamped into an issued certificate.
+type Profile struct {
+	Name               string
+	KeyUsage           x509.KeyUsage
+	ExtKeyUsage        []x509.ExtKeyUsage
+	UnknownExtKeyUsage []asn1.ObjectIdentifier
+	CA                 bool
+	// MaxPathLen constrains intermediate CAs, or is -1 for no constraint.
+	MaxPathLen int
+	Extensions []pkix.Extension
+}
+
+// Default is used when neither a request nor its signer names a profile.
+var Default = &Profile{
+	Name:        "default",
+	KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+	ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	MaxPathLen:  -1,
+}
+
+var builtin = map[string]*Profile{
+	Server: {
+		Name:        Server,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		MaxPathLen:  -1,
+	},
+	Client: {
+		Name:        Client,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		MaxPathLen:  -1,
+	},
+	CodeSigning: {
+		Name:        CodeSigning,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		MaxPathLen:  -1,
+	},
+	Email: {
+		Name:        Email,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageEmailProtection},
+		MaxPathLen:  -1,
+	},
+	OCSPSigning: {
+		Name:        OCSPSigning,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning},
+		MaxPathLen:  -1,
+		Extensions:  []pkix.Extension{{Id: oidExtensionOCSPNoCheck, Value: asn1.NullBytes}},
+	},
+	IntermediateCA: {
+		Name:       IntermediateCA,
+		KeyUsage:   x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		CA:         true,
+		MaxPathLen: 0,
+	},
+}
+
+// Builtin returns the built-in profile with the given name, if there is one.
+func Builtin(name string) (*Profile, bool) {
+	p, ok := builtin[name]
+	return p, ok
+}
+
+// Lookup returns the named profile from custom, falling back to the
+// built-in profiles.
+func Lookup(name string, custom []*mgmtv1.Profile) (*Profile, error) {
+	for _, p := range custom {
+		if p.Name == name {
+			return Parse(p)
+		}
+	}
+	if p, ok := builtin[name]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("profile %q not found", name)
+}
+
+var keyUsages = map[mgmtv1.KeyUsage]x509.KeyUsage{
+	mgmtv1.KeyUsage_KEY_USAGE_DIGITAL_SIGNATURE:  x509.KeyUsageDigitalSignature,
+	mgmtv1.KeyUsage_KEY_USAGE_CONTENT_COMMITMENT: x509.KeyUsageContentCommitment,
+	mgmtv1.KeyUsage_KEY_USAGE_KEY_ENCIPHERMENT:   x509.KeyUsageKeyEncipherment,
+	mgmtv1.KeyUsage_KEY_USAGE_DATA_ENCIPHERMENT:  x509.KeyUsageDataEncipherment,
+	mgmtv1.KeyUsage_KEY_USAGE_KEY_AGREEMENT:      x509.KeyUsageKeyAgreement,
+	mgmtv1.KeyUsage_KEY_USAGE_CERT_SIGN:          x509.KeyUsageCertSign,
+	mgmtv1.KeyUsage_KEY_USAGE_CRL_SIGN:           x509.KeyUsageCRLSign,
+}
+
+var extKeyUsages = map[mgmtv1.ExtKeyUsage]x509.ExtKeyUsage{
+	mgmtv1.ExtKeyUsage_EXT_KEY_USAGE_SERVER_AUTH:      x509.ExtKeyUsageServerAuth,
+	mgmtv1.ExtKeyUsage_EXT_KEY_USAGE_CLIENT_AUTH:      x509.ExtKeyUsageClientAuth,
+	mgmtv1.ExtKeyUsage_EXT_KEY_USAGE_CODE_SIGNING:     x509.ExtKeyUsageCodeSigning,
+	mgmtv1.ExtKeyUsage_EXT_KEY_USAGE_EMAIL_PROTECTION: x509.ExtKeyUsageEmailProtection,
+	mgmtv1.ExtKeyUsage_EXT_KEY_USAGE_TIME_STAMPING:    x509.ExtKeyUsageTimeStamping,
+	mgmtv1.ExtKeyUsage_EXT_KEY_USAGE_OCSP_SIGNING:     x509.ExtKeyUsageOCSPSigning,
+}
+
+// ExtKeyUsage converts an extended key usage from a signer's configuration.
+func ExtKeyUsage(eku mgmtv1.ExtKeyUsage) (x509.ExtKeyUsage, bool) {
+	usage, ok := extKeyUsages[eku]
+	return usage, ok
+}
+
+// Parse converts a profile from a signer's configuration.
+func Parse(p *mgmtv1.Profile) (*Profile, error) {
+	if p.Name == "" {
+		return nil, fmt.Errorf("profile name is missing")
+	}
+	out := &Profile{Name: p.Name, CA: p.Ca, MaxPathLen: -1}
+	for _, ku := range p.KeyUsages {
+		usage, ok := keyUsages[ku]
+		if !ok {
+			return nil, fmt.Errorf("profile %q: invalid key usage %s", p.Name, ku)
+		}
+		out.KeyUsage |= usage
+	}
+	for _, eku := range p.ExtKeyUsages {
+		usage, ok := extKeyUsages[eku]
+		if !ok {
+			return nil, fmt.Errorf("profile %q: invalid extended key usage %s", p.Name, eku)
+		}
+		out.ExtKeyUsage = append(out.ExtKeyUsage, usage)
+	}
+	for _, s := range p.ExtKeyUsageOids {
+		oid, err := parseOID(s)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", p.Name, err)
+		}
+		out.UnknownExtKeyUsage = append(out.UnknownExtKeyUsage, oid)
+	}
+	if p.MaxPathLen != nil {
+		if !p.Ca || *p.MaxPathLen < 0 {
+			return nil, fmt.Errorf("profile %q: max path length must be non-negative and only set for CAs", p.Name)
+		}
+		out.MaxPathLen = int(*p.MaxPathLen)
+	}
+	for _, ext := range p.Extensions {
+		oid, err := parseOID(ext.Oid)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", p.Name, err)
+		}
+		if len(oid) >= 3 && oid[0] == 2 && oid[1] == 5 && oid[2] == 29 {
+			return nil, fmt.Errorf("profile %q: extension %s cannot be set directly", p.Name, ext.Oid)
+		}
+		var v asn1.RawValue
+		if rest, err := asn1.Unmarshal(ext.Value, &v); err != nil || len(rest) > 0 {
+			return nil, fmt.Errorf("profile %q: extension %s value is not DER", p.Name, ext.Oid)
+		}
+		out.Extensions = append(out.Extensions, pkix.Extension{Id: oid, Critical: ext.Critical, Value: ext.Value})
+	}
+	if out.CA && out.KeyUsage == 0 {
+		out.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+	}
+	return out, nil
+}
+
+func parseOID(s string) (asn1.ObjectIdentifier, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid OID %q", s)
+	}
+	oid := make(asn1.ObjectIdentifier, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid OID %q", s)
+		}
+		oid[i] = n
+	}
+	return oid, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package profile
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+)
+
+func TestParse(t *testing.T) {
+	der, err := asn1.Marshal("northfoot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Parse(&mgmtv1.Profile{
+		Name:            "custom",
+		KeyUsages:       []mgmtv1.KeyUsage{mgmtv1.KeyUsage_KEY_USAGE_DIGITAL_SIGNATURE},
+		ExtKeyUsages:    []mgmtv1.ExtKeyUsage{mgmtv1.ExtKeyUsage_EXT_KEY_USAGE_CLIENT_AUTH},
+		ExtKeyUsageOids: []string{"1.3.6.1.4.1.99999.1"},
+		Extensions:      []*mgmtv1.Extension{{Oid: "1.3.6.1.4.1.99999.2", Critical: true, Value: der}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.KeyUsage != x509.KeyUsageDigitalSignature || len(p.ExtKeyUsage) != 1 || p.ExtKeyUsage[0] != x509.ExtKeyUsageClientAuth {
+		t.Errorf("Parse() usages = %v %v", p.KeyUsage, p.ExtKeyUsage)
+	}
+	if len(p.UnknownExtKeyUsage) != 1 || !p.UnknownExtKeyUsage[0].Equal(asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1}) {
+		t.Errorf("Parse() unknown extended key usages = %v", p.UnknownExtKeyUsage)
+	}
+	if len(p.Extensions) != 1 || !p.Extensions[0].Critical || string(p.Extensions[0].Value) != string(der) {
+		t.Errorf("Parse() extensions = %v", p.Extensions)
+	}
+	if p.CA || p.MaxPathLen != -1 {
+		t.Errorf("Parse() CA = %v, max path length %d", p.CA, p.MaxPathLen)
+	}
+
+	ca, err := Parse(&mgmtv1.Profile{Name: "ca", Ca: true, MaxPathLen: proto.Int32(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ca.CA || ca.MaxPathLen != 1 || ca.KeyUsage != x509.KeyUsageCertSign|x509.KeyUsageCRLSign|x509.KeyUsageDigitalSignature {
+		t.Errorf("Parse() CA profile = %+v", ca)
+	}
+
+	for name, p := range map[string]*mgmtv1.Profile{
+		"missing name":                {},
+		"invalid key usage":           {Name: "p", KeyUsages: []mgmtv1.KeyUsage{mgmtv1.KeyUsage_KEY_USAGE_UNSPECIFIED}},
+		"invalid extended key usage":  {Name: "p", ExtKeyUsages: []mgmtv1.ExtKeyUsage{mgmtv1.ExtKeyUsage_EXT_KEY_USAGE_UNSPECIFIED}},
+		"invalid OID":                 {Name: "p", ExtKeyUsageOids: []string{"1.x"}},
+		"path length on a leaf":       {Name: "p", MaxPathLen: proto.Int32(0)},
+		"negative path length":        {Name: "p", Ca: true, MaxPathLen: proto.Int32(-1)},
+		"basic constraints extension": {Name: "p", Extensions: []*mgmtv1.Extension{{Oid: "2.5.29.19", Value: der}}},
+		"name constraints extension":  {Name: "p", Extensions: []*mgmtv1.Extension{{Oid: "2.5.29.30", Value: der}}},
+		"extension value not DER":     {Name: "p", Extensions: []*mgmtv1.Extension{{Oid: "1.3.6.1.4.1.99999.2", Value: []byte("northfoot")}}},
+		"trailing data after DER":     {Name: "p", Extensions: []*mgmtv1.Extension{{Oid: "1.3.6.1.4.1.99999.2", Value: append(der, 0)}}},
+	} {
+		if _, err := Parse(p); err == nil {
+			t.Errorf("%s: Parse() accepted an invalid profile", name)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	custom := []*mgmtv1.Profile{{Name: Server, KeyUsages: []mgmtv1.KeyUsage{mgmtv1.KeyUsage_KEY_USAGE_DIGITAL_SIGNATURE}}}
+	p, err := Lookup(Server, custom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.ExtKeyUsage) != 0 {
+		t.Errorf("Lookup() returned the built-in profile rather than the custom one")
+	}
+	if p, err := Lookup(IntermediateCA, custom); err != nil || !p.CA || p.MaxPathLen != 0 {
+		t.Errorf("Lookup(%q) = %+v, %v", IntermediateCA, p, err)
+	}
+	if _, err := Lookup("unknown", custom); err == nil {
+		t.Error("Lookup() found an unknown profile")
+	}
+}
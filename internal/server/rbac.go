@@ -0,0 +1,316 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+	"github.com/jakexks/northfoot/internal/authn"
+	"github.com/jakexks/northfoot/internal/server/validation"
+)
+
+const (
+	procSign              = "/api.sign.v1.SignService/Sign"
+	procTrustBundle       = "/api.sign.v1.SignService/TrustBundle"
+	procGetCRL            = "/api.sign.v1.SignService/GetCRL"
+	procGetSigner         = "/api.mgmt.v1.ManagementService/GetSigner"
+	procListSigners       = "/api.mgmt.v1.ManagementService/ListSigners"
+	procCreateSigner      = "/api.mgmt.v1.ManagementService/CreateSigner"
+	procUpdateSigner      = "/api.mgmt.v1.ManagementService/UpdateSigner"
+	procRotateSigner      = "/api.mgmt.v1.ManagementService/RotateSigner"
+	procDeleteSigner      = "/api.mgmt.v1.ManagementService/DeleteSigner"
+	procListCertificates  = "/api.mgmt.v1.ManagementService/ListCertificates"
+	procGetCertificate    = "/api.mgmt.v1.ManagementService/GetCertificate"
+	procRevokeCertificate = "/api.mgmt.v1.ManagementService/RevokeCertificate"
+	procListRoleBindings  = "/api.mgmt.v1.ManagementService/ListRoleBindings"
+	procCreateRoleBinding = "/api.mgmt.v1.ManagementService/CreateRoleBinding"
+	procDeleteRoleBinding = "/api.mgmt.v1.ManagementService/DeleteRoleBinding"
+)
+
+var (
+	auditorProcedures = []string{
+		procTrustBundle, procGetCRL, procGetSigner, procListSigners,
+		procListCertificates, procGetCertificate, procListRoleBindings,
+	}
+	// rolePermissions maps each role to the procedures it may call. Admins
+	// may call anything.
+	rolePermissions = map[mgmtv1.Role]map[string]bool{
+		mgmtv1.Role_ROLE_OPERATOR: permissions(append([]string{procCreateSigner, procUpdateSigner, procRotateSigner, procDeleteSigner, procRevokeCertificate}, auditorProcedures...)...),
+		mgmtv1.Role_ROLE_ISSUER:   permissions(procSign, procTrustBundle, procGetCRL),
+		mgmtv1.Role_ROLE_AUDITOR:  permissions(auditorProcedures...),
+	}
+	// roleBindingProcedures may only be called by admins of every signer.
+	roleBindingProcedures = permissions(procCreateRoleBinding, procDeleteRoleBinding)
+)
+
+func permissions(procedures ...string) map[string]bool {
+	m := make(map[string]bool, len(procedures))
+	for _, p := range procedures {
+		m[p] = true
+	}
+	return m
+}
+
+type grantKey struct{}
+
+// grant is the set of signers the caller of an RPC may act on.
+type grant struct {
+	all     bool
+	signers map[int64]bool
+}
+
+func (g *grant) allows(signerID int64) bool {
+	return g.all || g.signers[signerID]
+}
+
+// signerAllowed reports whether the caller of the current RPC may act on a
+// signer. It is always true when authorization is disabled.
+func signerAllowed(ctx context.Context, signerID int64) bool {
+	g, ok := ctx.Value(grantKey{}).(*grant)
+	return !ok || g.allows(signerID)
+}
+
+// AuthorizationInterceptor only allows callers to make the RPCs granted by
+// their role bindings, on the signers the bindings cover. admins are
+// granted the admin role on every signer, to bootstrap the role bindings.
+// It must be installed after an authentication interceptor or middleware.
+func (s *Server) AuthorizationInterceptor(admins ...string) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return connect.UnaryFunc(func(
+			ctx context.Context,
+			req connect.AnyRequest,
+		) (connect.AnyResponse, error) {
+			identity, ok := authn.IdentityFromContext(ctx)
+			if !ok {
+				return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("caller is not authenticated"))
+			}
+			procedure := req.Spec().Procedure
+			g, err := s.grantFor(ctx, identity, procedure, admins)
+			if err != nil {
+				return nil, connect.NewError(connect.CodeInternal, err)
+			}
+			if g == nil || roleBindingProcedures[procedure] && !g.all {
+				return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("%s may not call %s", identity, procedure))
+			}
+			signerID, scoped, err := s.requestSigner(ctx, req.Any())
+			if err != nil {
+				return nil, connect.NewError(connect.CodeInternal, err)
+			}
+			if scoped && !g.allows(signerID) {
+				return nil, connect.NewError(connect.CodePermissionDenied, fmt.Errorf("%s may not call %s for signer %d", identity, procedure, signerID))
+			}
+			return next(context.WithValue(ctx, grantKey{}, g), req)
+		})
+	}
+}
+
+// grantFor merges the role bindings of identity that allow procedure,
+// returning nil if there are none.
+func (s *Server) grantFor(ctx context.Context, identity, procedure string, admins []string) (*grant, error) {
+	for _, admin := range admins {
+		if identity == admin {
+			return &grant{all: true}, nil
+		}
+	}
+	bindings, err := s.roleBindings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var g *grant
+	for _, binding := range bindings {
+		if binding.Identity != identity {
+			if ok, _ := path.Match(binding.Identity, identity); !ok {
+				continue
+			}
+		}
+		if binding.Role != mgmtv1.Role_ROLE_ADMIN && !rolePermissions[binding.Role][procedure] {
+			continue
+		}
+		if g == nil {
+			g = &grant{signers: make(map[int64]bool)}
+		}
+		if len(binding.SignerIds) == 0 {
+			g.all = true
+		}
+		for _, id := range binding.SignerIds {
+			g.signers[id] = true
+		}
+	}
+	return g, nil
+}
+
+// requestSigner returns the signer an RPC acts on, if it acts on a single
+// signer.
+func (s *Server) requestSigner(ctx context.Context, msg any) (int64, bool, error) {
+	switch m := msg.(type) {
+	case *signv1.SignRequest:
+		return m.SignerId, true, nil
+	case *signv1.TrustBundleRequest:
+		return m.SignerId, true, nil
+	case *signv1.GetCRLRequest:
+		return m.SignerId, true, nil
+	case *mgmtv1.GetSignerRequest:
+		return m.Id, true, nil
+	case *mgmtv1.DeleteSignerRequest:
+		return m.Id, true, nil
+	case *mgmtv1.CreateSignerRequest:
+		return m.GetSigner().GetId(), true, nil
+	case *mgmtv1.UpdateSignerRequest:
+		return m.GetSigner().GetId(), true, nil
+	case *mgmtv1.RotateSignerRequest:
+		return m.Id, true, nil
+	case *mgmtv1.ListCertificatesRequest:
+		return m.GetSignerId(), m.SignerId != nil, nil
+	case *mgmtv1.GetCertificateRequest:
+		return s.certificateSigner(ctx, m.Serial)
+	case *mgmtv1.RevokeCertificateRequest:
+		return s.certificateSigner(ctx, m.Serial)
+	}
+	return 0, false, nil
+}
+
+// certificateSigner returns the signer that issued a certificate. Unknown or
+// malformed serials are left for the RPC to reject.
+func (s *Server) certificateSigner(ctx context.Context, serial string) (int64, bool, error) {
+	parsed, err := parseSerial(serial)
+	if err != nil {
+		return 0, false, nil
+	}
+	var signerID int64
+	err = s.db.QueryRowContext(ctx, "SELECT signer_id FROM certificates WHERE serial = ?", serialString(parsed)).Scan(&signerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return signerID, true, nil
+}
+
+func (s *Server) roleBindings(ctx context.Context) ([]*mgmtv1.RoleBinding, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT binding FROM role_bindings ORDER BY identity, role")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var bindings []*mgmtv1.RoleBinding
+	for rows.Next() {
+		raw := ""
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		binding := &mgmtv1.RoleBinding{}
+		if err := protojson.Unmarshal([]byte(raw), binding); err != nil {
+			return nil, fmt.Errorf("error unmarshalling role binding: %w", err)
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings, rows.Err()
+}
+
+func (s *Server) ListRoleBindings(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[mgmtv1.ListRoleBindingsResponse], error) {
+	bindings, err := s.roleBindings(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if g, ok := ctx.Value(grantKey{}).(*grant); ok && !g.all {
+		bindings = scopeRoleBindings(bindings, g)
+	}
+	return connect.NewResponse(&mgmtv1.ListRoleBindingsResponse{
+		RoleBindings: bindings,
+	}), nil
+}
+
+// scopeRoleBindings limits bindings to the signers of g, dropping bindings
+// for every signer and for none of g's.
+func scopeRoleBindings(bindings []*mgmtv1.RoleBinding, g *grant) []*mgmtv1.RoleBinding {
+	var scoped []*mgmtv1.RoleBinding
+	for _, binding := range bindings {
+		var signerIDs []int64
+		for _, id := range binding.SignerIds {
+			if g.allows(id) {
+				signerIDs = append(signerIDs, id)
+			}
+		}
+		if len(signerIDs) == 0 {
+			continue
+		}
+		scoped = append(scoped, &mgmtv1.RoleBinding{Identity: binding.Identity, Role: binding.Role, SignerIds: signerIDs})
+	}
+	return scoped
+}
+
+func (s *Server) CreateRoleBinding(ctx context.Context, req *connect.Request[mgmtv1.CreateRoleBindingRequest]) (*connect.Response[emptypb.Empty], error) {
+	if err := validation.RoleBinding(req.Msg.RoleBinding); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	raw, err := protojson.Marshal(req.Msg.RoleBinding)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	res, err := s.db.ExecContext(ctx, "INSERT OR IGNORE INTO role_bindings (identity, role, binding) VALUES (?, ?, json(?))",
+		req.Msg.RoleBinding.Identity, int32(req.Msg.RoleBinding.Role), raw)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return nil, connect.NewError(connect.CodeAlreadyExists, errors.New("role binding already exists"))
+	}
+	return &connect.Response[emptypb.Empty]{}, nil
+}
+
+func (s *Server) DeleteRoleBinding(ctx context.Context, req *connect.Request[mgmtv1.DeleteRoleBindingRequest]) (*connect.Response[emptypb.Empty], error) {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM role_bindings WHERE identity = ? AND role = ?", req.Msg.Identity, int32(req.Msg.Role))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("role binding not found"))
+	}
+	return &connect.Response[emptypb.Empty]{}, nil
+}
+
+// allowedSignersClause restricts a query on signer_id to the signers the
+// caller may act on.
+func allowedSignersClause(ctx context.Context, column string) (string, []any) {
+	g, ok := ctx.Value(grantKey{}).(*grant)
+	if !ok || g.all {
+		return "", nil
+	}
+	if len(g.signers) == 0 {
+		return " AND 0", nil
+	}
+	placeholders := make([]string, 0, len(g.signers))
+	args := make([]any, 0, len(g.signers))
+	for id := range g.signers {
+		placeholders = append(placeholders, "?")
+		args = append(args, id)
+	}
+	return " AND " + column + " IN (" + strings.Join(placeholders, ", ") + ")", args
+}
@@ -0,0 +1,172 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/api/mgmt/v1/mgmtv1connect"
+	"github.com/jakexks/northfoot/internal/authn"
+)
+
+// newTestManagementClient serves the management API of s with role based
+// authorization, identifying callers by their Authorization header.
+func newTestManagementClient(t *testing.T, s *Server, admins ...string) mgmtv1connect.ManagementServiceClient {
+	t.Helper()
+	identify := authn.AuthenticationInterceptor(func(_ context.Context, authorization string) (string, error) {
+		return authorization, nil
+	})
+	_, handler := mgmtv1connect.NewManagementServiceHandler(s, connect.WithInterceptors(identify, s.AuthorizationInterceptor(admins...)))
+	mux := httptest.NewServer(handler)
+	t.Cleanup(mux.Close)
+	return mgmtv1connect.NewManagementServiceClient(mux.Client(), mux.URL)
+}
+
+// requestAs returns a request made by identity.
+func requestAs[T any](identity string, msg *T) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	req.Header().Set("Authorization", identity)
+	return req
+}
+
+func TestAuthorizationInterceptor(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	for id := int64(1); id <= 2; id++ {
+		createTestSigner(t, s, newTestSigner(id))
+	}
+	client := newTestManagementClient(t, s, "admin")
+	for _, binding := range []*mgmtv1.RoleBinding{
+		{Identity: "operator", Role: mgmtv1.Role_ROLE_OPERATOR, SignerIds: []int64{1}},
+		{Identity: "spiffe://edge/ns/*/sa/auditor", Role: mgmtv1.Role_ROLE_AUDITOR, SignerIds: []int64{2}},
+		{Identity: "issuer", Role: mgmtv1.Role_ROLE_ISSUER},
+	} {
+		if _, err := client.CreateRoleBinding(ctx, requestAs("admin", &mgmtv1.CreateRoleBindingRequest{RoleBinding: binding})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantCode connect.Code
+	}{
+		{"admin", func() error {
+			_, err := client.GetSigner(ctx, requestAs("admin", &mgmtv1.GetSignerRequest{Id: 2}))
+			return err
+		}, 0},
+		{"unknown identity", func() error {
+			_, err := client.GetSigner(ctx, requestAs("stranger", &mgmtv1.GetSignerRequest{Id: 1}))
+			return err
+		}, connect.CodePermissionDenied},
+		{"operator on its signer", func() error {
+			_, err := client.RotateSigner(ctx, requestAs("operator", &mgmtv1.RotateSignerRequest{Id: 1}))
+			return err
+		}, 0},
+		{"operator on another signer", func() error {
+			_, err := client.RotateSigner(ctx, requestAs("operator", &mgmtv1.RotateSignerRequest{Id: 2}))
+			return err
+		}, connect.CodePermissionDenied},
+		{"operator creating a role binding", func() error {
+			_, err := client.CreateRoleBinding(ctx, requestAs("operator", &mgmtv1.CreateRoleBindingRequest{RoleBinding: &mgmtv1.RoleBinding{Identity: "operator", Role: mgmtv1.Role_ROLE_ADMIN}}))
+			return err
+		}, connect.CodePermissionDenied},
+		{"auditor matching a glob", func() error {
+			_, err := client.GetSigner(ctx, requestAs("spiffe://edge/ns/team/sa/auditor", &mgmtv1.GetSignerRequest{Id: 2}))
+			return err
+		}, 0},
+		{"auditor deleting a signer", func() error {
+			_, err := client.DeleteSigner(ctx, requestAs("spiffe://edge/ns/team/sa/auditor", &mgmtv1.DeleteSignerRequest{Id: 2}))
+			return err
+		}, connect.CodePermissionDenied},
+		{"issuer calling the management API", func() error {
+			_, err := client.ListSigners(ctx, requestAs("issuer", &emptypb.Empty{}))
+			return err
+		}, connect.CodePermissionDenied},
+		{"unauthenticated", func() error {
+			_, err := client.ListSigners(ctx, connect.NewRequest(&emptypb.Empty{}))
+			return err
+		}, connect.CodeUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if tt.wantCode == 0 && err != nil || tt.wantCode != 0 && connect.CodeOf(err) != tt.wantCode {
+				t.Errorf("error = %v, want %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestListRoleBindingsScoped(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	client := newTestManagementClient(t, s, "admin")
+	for _, binding := range []*mgmtv1.RoleBinding{
+		{Identity: "auditor", Role: mgmtv1.Role_ROLE_AUDITOR, SignerIds: []int64{1}},
+		{Identity: "team-a", Role: mgmtv1.Role_ROLE_ISSUER, SignerIds: []int64{1, 2}},
+		{Identity: "team-b", Role: mgmtv1.Role_ROLE_OPERATOR, SignerIds: []int64{2}},
+		{Identity: "global", Role: mgmtv1.Role_ROLE_AUDITOR},
+	} {
+		if _, err := client.CreateRoleBinding(ctx, requestAs("admin", &mgmtv1.CreateRoleBindingRequest{RoleBinding: binding})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		identity string
+		want     map[string][]int64
+	}{
+		{"admin", map[string][]int64{"auditor": {1}, "team-a": {1, 2}, "team-b": {2}, "global": nil}},
+		{"global", map[string][]int64{"auditor": {1}, "team-a": {1, 2}, "team-b": {2}, "global": nil}},
+		{"auditor", map[string][]int64{"auditor": {1}, "team-a": {1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.identity, func(t *testing.T) {
+			resp, err := client.ListRoleBindings(ctx, requestAs(tt.identity, &emptypb.Empty{}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string][]int64)
+			for _, binding := range resp.Msg.RoleBindings {
+				got[binding.Identity] = binding.SignerIds
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("bindings = %v, want %v", got, tt.want)
+			}
+			for identity, want := range tt.want {
+				ids, ok := got[identity]
+				if !ok || len(ids) != len(want) {
+					t.Errorf("signers of %s = %v, want %v", identity, ids, want)
+					continue
+				}
+				for i := range want {
+					if ids[i] != want[i] {
+						t.Errorf("signers of %s = %v, want %v", identity, ids, want)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+	"github.com/jakexks/northfoot/api/sign/v1/signv1connect"
+	"github.com/jakexks/northfoot/internal/util"
+)
+
+func newRemoteSigner(ctx context.Context, log *zap.Logger, keys *keyPool, config *mgmtv1.SignerRemoteConfig) (signer, error) {
+	switch config.RemoteType {
+	case mgmtv1.RemoteType_REMOTE_TYPE_UNSPECIFIED:
+		return nil, errors.New("remote type is missing")
+	case mgmtv1.RemoteType_REMOTE_TYPE_NORTHFOOT:
+		if config.GetNorthfoot() == nil {
+			return nil, errors.New("remote northfoot config is nil")
+		}
+		return newRemoteNorthfootSigner(ctx, keys, config.GetNorthfoot())
+	case mgmtv1.RemoteType_REMOTE_TYPE_VERBATIM_HTTPS:
+		if config.GetVerbatimHttps() == nil {
+			return nil, errors.New("remote verbatim https config is nil")
+		}
+		return newVerbatimSigner(ctx, config.GetVerbatimHttps(), log)
+	default:
+		return nil, errors.New("remote type not implemented")
+	}
+}
+
+// newRemoteNorthfootSigner generates an intermediate CA key in memory and has
+// its CSR signed by an upstream Northfoot instance. Once created, leaves are
+// issued locally without contacting the upstream.
+func newRemoteNorthfootSigner(ctx context.Context, keys *keyPool, config *mgmtv1.RemoteNorthfootConfig) (signer, error) {
+	opts, err := caOptions(config.Ca, true)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keys.generate(config.Key, config.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	csr, err := util.CreateCACertificateRequest(key, opts)
+	if err != nil {
+		return nil, err
+	}
+	duration := opts.Validity
+	if config.Duration != nil && config.GetCa().GetValidity() == nil {
+		duration = config.Duration.AsDuration()
+	}
+
+	httpClient, err := newUpstreamClient(config.CaFilePath, config.ClientCertFilePath, config.ClientKeyFilePath)
+	if err != nil {
+		return nil, err
+	}
+	client := signv1connect.NewSignServiceClient(httpClient, config.Endpoint)
+	signReq := connect.NewRequest(&signv1.SignRequest{
+		SignerId:     config.SignerId,
+		Csr:          csr,
+		DurationHint: durationpb.New(duration),
+		Ca:           true,
+	})
+	bundleReq := connect.NewRequest(&signv1.TrustBundleRequest{
+		SignerId: config.SignerId,
+	})
+	if config.Token != nil {
+		signReq.Header().Set("Authorization", *config.Token)
+		bundleReq.Header().Set("Authorization", *config.Token)
+	}
+	signResp, err := client.Sign(ctx, signReq)
+	if err != nil {
+		return nil, fmt.Errorf("upstream failed to sign intermediate CA: %w", err)
+	}
+	bundleResp, err := client.TrustBundle(ctx, bundleReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch upstream trust bundle: %w", err)
+	}
+
+	cert, err := x509.ParseCertificate(signResp.Msg.Cert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse intermediate CA from upstream: %w", err)
+	}
+	if !cert.IsCA {
+		return nil, errors.New("upstream returned a certificate that is not a CA")
+	}
+	match, err := util.KeyMatchesCertificate(key, cert)
+	if err != nil {
+		return nil, err
+	}
+	if !match {
+		return nil, errors.New("upstream returned a certificate for a different key")
+	}
+	var chain []*x509.Certificate
+	for _, der := range bundleResp.Msg.Certs {
+		c, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse upstream trust bundle: %w", err)
+		}
+		chain = append(chain, c)
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("upstream trust bundle is empty")
+	}
+	if err := cert.CheckSignatureFrom(chain[0]); err != nil {
+		return nil, fmt.Errorf("intermediate CA is not signed by upstream signer: %w", err)
+	}
+	return &inMemSigner{
+		key:   key,
+		cert:  cert,
+		chain: chain,
+	}, nil
+}
+
+// newUpstreamClient returns an HTTP client for a remote signer that trusts the
+// CAs in caFilePath, or the system roots if it is nil, and presents the
+// client certificate and key if they are set.
+func newUpstreamClient(caFilePath, clientCertFilePath, clientKeyFilePath *string) (*http.Client, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFilePath != nil {
+		caPEM, err := os.ReadFile(*caFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("no certificates found in CA file")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if clientCertFilePath != nil || clientKeyFilePath != nil {
+		if clientCertFilePath == nil || clientKeyFilePath == nil {
+			return nil, errors.New("client certificate and key must be set together")
+		}
+		clientCert, err := tls.LoadX509KeyPair(*clientCertFilePath, *clientKeyFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+	return &http.Client{
+		Timeout: time.Minute,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/api/sign/v1/signv1connect"
+)
+
+func TestRemoteNorthfootUpstreamTLS(t *testing.T) {
+	upstream := newTestServer(t)
+	issuer := newTestSigner(1)
+	allow := true
+	issuer.AllowCaIssuance = &allow
+	createTestSigner(t, upstream, issuer)
+
+	dir := t.TempDir()
+	writePEM := func(name, blockType string, der []byte) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "edge"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, &x509.Certificate{Subject: pkix.Name{CommonName: "edge"}}, clientKey.Public(), clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCert, err := x509.ParseCertificate(clientDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientKeyDER, err := x509.MarshalPKCS8PrivateKey(clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCertPath := writePEM("client.crt", "CERTIFICATE", clientDER)
+	clientKeyPath := writePEM("client.key", "PRIVATE KEY", clientKeyDER)
+
+	mux := http.NewServeMux()
+	mux.Handle(signv1connect.NewSignServiceHandler(upstream))
+	hs := httptest.NewUnstartedServer(mux)
+	hs.Config.ErrorLog = log.New(io.Discard, "", 0)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	hs.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	hs.StartTLS()
+	defer hs.Close()
+	caPath := writePEM("upstream.crt", "CERTIFICATE", hs.Certificate().Raw)
+
+	for _, tt := range []struct {
+		name       string
+		caFile     *string
+		clientCert *string
+		clientKey  *string
+		wantErr    bool
+	}{
+		{"pinned CA and client certificate", &caPath, &clientCertPath, &clientKeyPath, false},
+		{"untrusted upstream", nil, &clientCertPath, &clientKeyPath, true},
+		{"no client certificate", &caPath, nil, nil, true},
+		{"client certificate without key", &caPath, &clientCertPath, nil, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			si, err := newRemoteNorthfootSigner(context.Background(), nil, &mgmtv1.RemoteNorthfootConfig{
+				Endpoint:           hs.URL,
+				SignerId:           1,
+				Key:                mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC,
+				CaFilePath:         tt.caFile,
+				ClientCertFilePath: tt.clientCert,
+				ClientKeyFilePath:  tt.clientKey,
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Error("created the signer")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !si.TrustBundle()[0].IsCA {
+				t.Error("upstream did not issue an intermediate CA")
+			}
+		})
+	}
+}
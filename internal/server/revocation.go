@@ -0,0 +1,306 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+)
+
+const (
+	// CRLs are regenerated after crlRefreshInterval but remain valid for
+	// crlValidity, so relying parties can tolerate a missed refresh.
+	crlRefreshInterval = time.Hour
+	crlValidity        = 24 * time.Hour
+)
+
+var oidExtensionReasonCode = asn1.ObjectIdentifier{2, 5, 29, 21}
+
+type signedCRL struct {
+	der       []byte
+	refreshAt time.Time
+}
+
+// caKey identifies one of the CAs of a signer.
+type caKey struct {
+	signerID int64
+	// hex serial of the CA certificate
+	serial string
+}
+
+// dropCRLs forgets the CRLs of every CA of a signer, so they are generated
+// again when next requested.
+func (s *Server) dropCRLs(signerID int64) {
+	s.crlLock.Lock()
+	defer s.crlLock.Unlock()
+	s.crlEpoch++
+	for key := range s.crls {
+		if key.signerID == signerID {
+			delete(s.crls, key)
+		}
+	}
+}
+
+func (s *Server) RevokeCertificate(ctx context.Context, req *connect.Request[mgmtv1.RevokeCertificateRequest]) (*connect.Response[emptypb.Empty], error) {
+	serial, err := parseSerial(req.Msg.Serial)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if _, ok := mgmtv1.RevocationReason_name[int32(req.Msg.Reason)]; !ok {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown revocation reason %d", req.Msg.Reason))
+	}
+	var signerID int64
+	err = s.db.QueryRowContext(ctx, "SELECT signer_id FROM certificates WHERE serial = ?", serialString(serial)).Scan(&signerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("certificate with serial "+req.Msg.Serial+" not found"))
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	result, err := s.db.ExecContext(ctx, "INSERT OR IGNORE INTO revocations (serial, revoked_at, reason) VALUES (?, ?, ?)", serialString(serial), time.Now().Unix(), int32(req.Msg.Reason))
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return nil, connect.NewError(connect.CodeAlreadyExists, errors.New("certificate with serial "+req.Msg.Serial+" is already revoked"))
+	}
+	s.log.Info("revoked certificate", zap.String("serial", serialString(serial)), zap.Int64("signer_id", signerID), zap.Stringer("reason", req.Msg.Reason))
+	s.dropCRLs(signerID)
+	return &connect.Response[emptypb.Empty]{}, nil
+}
+
+func (s *Server) GetCRL(ctx context.Context, req *connect.Request[signv1.GetCRLRequest]) (*connect.Response[signv1.GetCRLResponse], error) {
+	crl, err := s.getCRL(ctx, req.Msg.SignerId, req.Msg.GetCaSerial())
+	if errors.Is(err, errSignerNotFound) || errors.Is(err, errCANotFound) {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(&signv1.GetCRLResponse{
+		Crl: crl,
+	}), nil
+}
+
+// CRLHandler serves the DER encoded CRL of the current CA of each signer at
+// /crl/{id}.crl, and of each CA in its trust bundle at
+// /crl/{id}/{CA serial}.crl.
+func (s *Server) CRLHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		parts := strings.SplitN(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/crl/"), ".crl"), "/", 2)
+		id, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		serial := ""
+		if len(parts) == 2 {
+			serial = parts[1]
+		}
+		crl, err := s.getCRL(r.Context(), id, serial)
+		if errors.Is(err, errSignerNotFound) || errors.Is(err, errCANotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			s.log.Error("failed to generate CRL", zap.Int64("signer_id", id), zap.Error(err))
+			http.Error(w, "failed to generate CRL", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pkix-crl")
+		w.Write(crl)
+	})
+}
+
+// crlDistributionPoints returns the URLs of the CRL of ca, the CA of a
+// signer that a certificate is issued by.
+func (s *Server) crlDistributionPoints(signerID int64, ca *x509.Certificate) []string {
+	if s.baseURL == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s/crl/%d/%s.crl", s.baseURL, signerID, serialString(ca.SerialNumber))}
+}
+
+// getCRL returns the CRL of the CA of a signer with the given serial, or of
+// its current CA if serial is empty, generating it if it is missing or due
+// for a refresh.
+func (s *Server) getCRL(ctx context.Context, signerID int64, serial string) ([]byte, error) {
+	signer, err := s.getSigner(ctx, signerID)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := caSigner(signer.signer, serial)
+	if err != nil {
+		return nil, err
+	}
+	key := caKey{signerID: signerID, serial: serialString(ca.TrustBundle()[0].SerialNumber)}
+	s.crlLock.Lock()
+	crl, found := s.crls[key]
+	epoch := s.crlEpoch
+	s.crlLock.Unlock()
+	if found && time.Now().Before(crl.refreshAt) {
+		return crl.der, nil
+	}
+	if crl, err = s.generateCRL(ctx, signerID, ca); err != nil {
+		return nil, err
+	}
+	s.storeCRL(key, crl, epoch)
+	return crl.der, nil
+}
+
+// storeCRL caches a CRL generated at epoch, unless CRLs have been dropped
+// since, in which case it may be missing a revocation.
+func (s *Server) storeCRL(key caKey, crl *signedCRL, epoch uint64) {
+	s.crlLock.Lock()
+	defer s.crlLock.Unlock()
+	if s.crlEpoch == epoch {
+		s.crls[key] = crl
+	}
+}
+
+// generateCRL signs a CRL of the revoked certificates issued by one of the
+// CAs of a signer with that CA.
+func (s *Server) generateCRL(ctx context.Context, signerID int64, ca signer) (*signedCRL, error) {
+	revoked, err := s.revokedCertificates(ctx, signerID, ca.TrustBundle()[0])
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	der, err := ca.CRL(revoked, big.NewInt(now.UnixNano()), now.Add(crlValidity))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign CRL: %w", err)
+	}
+	return &signedCRL{der: der, refreshAt: now.Add(crlRefreshInterval)}, nil
+}
+
+// refreshCRLs periodically regenerates every CRL that has been requested, so
+// that they never lapse while the server is running, until it is closed. CRLs
+// of CAs that have left their signer's trust bundle are dropped.
+func (s *Server) refreshCRLs() {
+	ticker := time.NewTicker(crlRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// CRLs are generated without holding the lock, as loading a signer
+		// may wait for a remote upstream
+		s.crlLock.Lock()
+		keys := make([]caKey, 0, len(s.crls))
+		for key := range s.crls {
+			keys = append(keys, key)
+		}
+		epoch := s.crlEpoch
+		s.crlLock.Unlock()
+		for _, key := range keys {
+			crl, err := s.refreshCRL(s.ctx, key)
+			if errors.Is(err, errSignerNotFound) || errors.Is(err, errCANotFound) {
+				s.crlLock.Lock()
+				delete(s.crls, key)
+				s.crlLock.Unlock()
+				continue
+			}
+			if err != nil {
+				s.log.Error("failed to refresh CRL", zap.Int64("signer_id", key.signerID), zap.String("ca_serial", key.serial), zap.Error(err))
+				continue
+			}
+			s.storeCRL(key, crl, epoch)
+		}
+	}
+}
+
+func (s *Server) refreshCRL(ctx context.Context, key caKey) (*signedCRL, error) {
+	signer, err := s.getSigner(ctx, key.signerID)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := caSigner(signer.signer, key.serial)
+	if err != nil {
+		return nil, err
+	}
+	return s.generateCRL(ctx, key.signerID, ca)
+}
+
+// revokedCertificates returns the unexpired revoked certificates of a signer
+// issued by issuer.
+func (s *Server) revokedCertificates(ctx context.Context, signerID int64, issuer *x509.Certificate) ([]pkix.RevokedCertificate, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT r.serial, r.revoked_at, r.reason, c.issuer_serial, c.cert FROM revocations r
+		JOIN certificates c ON c.serial = r.serial
+		WHERE c.signer_id = ? AND c.not_after > ? AND c.issuer_serial IN (?, '')`,
+		signerID, time.Now().Unix(), serialString(issuer.SerialNumber))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var revoked []pkix.RevokedCertificate
+	for rows.Next() {
+		var (
+			serial       string
+			revokedAt    int64
+			reason       int32
+			issuerSerial string
+			raw          []byte
+		)
+		if err := rows.Scan(&serial, &revokedAt, &reason, &issuerSerial, &raw); err != nil {
+			return nil, err
+		}
+		if !issuedBy(issuerSerial, raw, issuer) {
+			continue
+		}
+		n, err := parseSerial(serial)
+		if err != nil {
+			return nil, err
+		}
+		entry := pkix.RevokedCertificate{
+			SerialNumber:   n,
+			RevocationTime: time.Unix(revokedAt, 0).UTC(),
+		}
+		if reason != int32(mgmtv1.RevocationReason_REVOCATION_REASON_UNSPECIFIED) {
+			value, err := asn1.Marshal(asn1.Enumerated(reason))
+			if err != nil {
+				return nil, err
+			}
+			entry.Extensions = []pkix.Extension{{Id: oidExtensionReasonCode, Value: value}}
+		}
+		revoked = append(revoked, entry)
+	}
+	return revoked, rows.Err()
+}
@@ -0,0 +1,155 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+)
+
+func TestRevokeCertificate(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	createTestSigner(t, s, newTestSigner(1))
+	leaf, _ := issueTestLeaf(t, s, 1)
+
+	tests := []struct {
+		name     string
+		serial   string
+		reason   mgmtv1.RevocationReason
+		wantCode connect.Code
+	}{
+		{"revoke", serialString(leaf.SerialNumber), mgmtv1.RevocationReason_REVOCATION_REASON_SUPERSEDED, 0},
+		{"already revoked", serialString(leaf.SerialNumber), mgmtv1.RevocationReason_REVOCATION_REASON_UNSPECIFIED, connect.CodeAlreadyExists},
+		{"unknown serial", serialString(big.NewInt(42)), mgmtv1.RevocationReason_REVOCATION_REASON_UNSPECIFIED, connect.CodeNotFound},
+		{"malformed serial", "not-hex", mgmtv1.RevocationReason_REVOCATION_REASON_UNSPECIFIED, connect.CodeInvalidArgument},
+		{"unknown reason", serialString(leaf.SerialNumber), mgmtv1.RevocationReason(99), connect.CodeInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.RevokeCertificate(ctx, connect.NewRequest(&mgmtv1.RevokeCertificateRequest{Serial: tt.serial, Reason: tt.reason}))
+			if tt.wantCode == 0 && err != nil || tt.wantCode != 0 && connect.CodeOf(err) != tt.wantCode {
+				t.Errorf("RevokeCertificate() error = %v, want %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestCRLHandler(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	createTestSigner(t, s, newTestSigner(1))
+	oldLeaf, oldCA := issueTestLeaf(t, s, 1)
+	if _, err := s.RotateSigner(ctx, connect.NewRequest(&mgmtv1.RotateSignerRequest{Id: 1, Overlap: durationpb.New(time.Hour)})); err != nil {
+		t.Fatal(err)
+	}
+	newLeaf, newCA := issueTestLeaf(t, s, 1)
+	goodLeaf, _ := issueTestLeaf(t, s, 1)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.CRLHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+	// CRLs are cached before the revocations, which must replace them
+	get("/crl/1.crl")
+	get("/crl/1/" + serialString(oldCA.SerialNumber) + ".crl")
+	for _, leaf := range []*x509.Certificate{oldLeaf, newLeaf} {
+		if _, err := s.RevokeCertificate(ctx, connect.NewRequest(&mgmtv1.RevokeCertificateRequest{Serial: serialString(leaf.SerialNumber)})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		ca         *x509.Certificate
+		wantStatus int
+		revoked    []*x509.Certificate
+		notRevoked []*x509.Certificate
+	}{
+		{"current CA", "/crl/1.crl", newCA, http.StatusOK, []*x509.Certificate{newLeaf}, []*x509.Certificate{oldLeaf, goodLeaf}},
+		{"current CA by serial", "/crl/1/" + serialString(newCA.SerialNumber) + ".crl", newCA, http.StatusOK, []*x509.Certificate{newLeaf}, []*x509.Certificate{oldLeaf, goodLeaf}},
+		{"retired CA", "/crl/1/" + serialString(oldCA.SerialNumber) + ".crl", oldCA, http.StatusOK, []*x509.Certificate{oldLeaf}, []*x509.Certificate{newLeaf}},
+		{"unknown CA", "/crl/1/" + serialString(big.NewInt(42)) + ".crl", nil, http.StatusNotFound, nil, nil},
+		{"unknown signer", "/crl/9.crl", nil, http.StatusNotFound, nil, nil},
+		{"malformed signer", "/crl/x.crl", nil, http.StatusNotFound, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(tt.path)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("GET %s = %d, want %d", tt.path, rec.Code, tt.wantStatus)
+			}
+			if tt.ca == nil {
+				return
+			}
+			crl, err := x509.ParseRevocationList(rec.Body.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := crl.CheckSignatureFrom(tt.ca); err != nil {
+				t.Errorf("CRL is not signed by the CA: %v", err)
+			}
+			listed := make(map[string]bool)
+			for _, entry := range crl.RevokedCertificateEntries {
+				listed[serialString(entry.SerialNumber)] = true
+			}
+			for _, leaf := range tt.revoked {
+				if !listed[serialString(leaf.SerialNumber)] {
+					t.Errorf("revoked certificate %s is missing from the CRL", serialString(leaf.SerialNumber))
+				}
+			}
+			for _, leaf := range tt.notRevoked {
+				if listed[serialString(leaf.SerialNumber)] {
+					t.Errorf("certificate %s is listed on the CRL", serialString(leaf.SerialNumber))
+				}
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	s.CRLHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/crl/1.crl", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+	resp, err := s.GetCRL(ctx, connect.NewRequest(&signv1.GetCRLRequest{SignerId: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crl, err := x509.ParseRevocationList(resp.Msg.Crl); err != nil || crl.CheckSignatureFrom(newCA) != nil {
+		t.Errorf("GetCRL() did not return the current CA's CRL: %v", err)
+	}
+	for _, req := range []*signv1.GetCRLRequest{{SignerId: 9}, {SignerId: 1, CaSerial: proto.String(serialString(big.NewInt(42)))}} {
+		if _, err := s.GetCRL(ctx, connect.NewRequest(req)); connect.CodeOf(err) != connect.CodeNotFound {
+			t.Errorf("GetCRL(%v) error = %v, want NotFound", req, err)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"go.uber.org/zap"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+)
+
+const (
+	defaultRotationOverlap = time.Hour * 24
+	rotationCheckInterval  = time.Minute
+)
+
+// rotatedSigner issues from the newest CA of a signer, while the CAs it
+// replaced stay in the trust bundle until their overlap window ends.
+type rotatedSigner struct {
+	signer
+	// the new CA issued by the previous one, if cross-signing
+	crossSigned *x509.Certificate
+	retired     []retiredCA
+}
+
+type retiredCA struct {
+	signer
+	until time.Time
+}
+
+func (r *rotatedSigner) TrustBundle() []*x509.Certificate {
+	bundle := r.signer.TrustBundle()
+	if r.crossSigned != nil {
+		bundle = append(bundle, r.crossSigned)
+	}
+	now := time.Now()
+	for _, old := range r.retired {
+		if now.Before(old.until) {
+			bundle = append(bundle, old.TrustBundle()...)
+		}
+	}
+	return bundle
+}
+
+// caSigners returns the current CA of si, followed by the retired CAs
+// that are still in its trust bundle. Each signs the CRLs and OCSP responses
+// for the leaves it issued.
+func caSigners(si signer) []signer {
+	r, ok := si.(*rotatedSigner)
+	if !ok {
+		return []signer{si}
+	}
+	cas := caSigners(r.signer)
+	now := time.Now()
+	for _, old := range r.retired {
+		if now.Before(old.until) {
+			cas = append(cas, caSigners(old.signer)...)
+		}
+	}
+	return cas
+}
+
+// caSigner returns the CA of si with the given hex serial, or its
+// current CA if serial is empty.
+func caSigner(si signer, serial string) (signer, error) {
+	cas := caSigners(si)
+	if serial == "" {
+		return cas[0], nil
+	}
+	for _, ca := range cas {
+		if serialString(ca.TrustBundle()[0].SerialNumber) == serial {
+			return ca, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", errCANotFound, serial)
+}
+
+// issuingCAs returns the CA certificates that leaves of si may be issued
+// by: its current CA, and while they are trusted its cross-signed and
+// retired CAs.
+func issuingCAs(si signer) []*x509.Certificate {
+	var cas []*x509.Certificate
+	for _, ca := range caSigners(si) {
+		cas = append(cas, ca.TrustBundle()[0])
+	}
+	if r, ok := si.(*rotatedSigner); ok && r.crossSigned != nil {
+		cas = append(cas, r.crossSigned)
+	}
+	return cas
+}
+
+// Close releases the resources of the current and retired CAs.
+func (r *rotatedSigner) Close() error {
+	var errs []error
+	if closer, ok := r.signer.(io.Closer); ok {
+		errs = append(errs, closer.Close())
+	}
+	for _, old := range r.retired {
+		if closer, ok := old.signer.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// rotatable reports whether a signer generates its own CA, and so can have
+// it replaced.
+func rotatable(config *mgmtv1.Signer) bool {
+	switch config.Type {
+	case mgmtv1.SignerType_SIGNER_TYPE_INMEM, mgmtv1.SignerType_SIGNER_TYPE_FILE:
+		return true
+	case mgmtv1.SignerType_SIGNER_TYPE_REMOTE:
+		return config.GetRemote().GetNorthfoot() != nil
+	}
+	return false
+}
+
+func (s *Server) RotateSigner(ctx context.Context, req *connect.Request[mgmtv1.RotateSignerRequest]) (*connect.Response[mgmtv1.RotateSignerResponse], error) {
+	cached, err := s.getSigner(ctx, req.Msg.Id)
+	if errors.Is(err, errSignerNotFound) {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if !rotatable(cached.config) {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, errors.New("only in memory, file and remote Northfoot signers can be rotated"))
+	}
+	overlap := defaultRotationOverlap
+	if o := cached.config.GetRotation().GetOverlap(); o != nil {
+		overlap = o.AsDuration()
+	}
+	if req.Msg.Overlap != nil {
+		overlap = req.Msg.Overlap.AsDuration()
+	}
+	crossSign := cached.config.GetRotation().GetCrossSign()
+	if req.Msg.CrossSign != nil {
+		crossSign = *req.Msg.CrossSign
+	}
+	if overlap < 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("overlap must not be negative"))
+	}
+	rotated, err := s.rotateSigner(ctx, req.Msg.Id, cached, overlap, crossSign)
+	if err != nil {
+		return nil, err
+	}
+	resp := &mgmtv1.RotateSignerResponse{Ca: rotated.TrustBundle()[0].Raw}
+	for _, cert := range rotated.TrustBundle() {
+		resp.TrustBundle = append(resp.TrustBundle, cert.Raw)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// rotateSigner replaces the CA of cached with a new one, keeping the current
+// CA in the trust bundle for overlap.
+func (s *Server) rotateSigner(ctx context.Context, signerID int64, cached *cachedSigner, overlap time.Duration, crossSign bool) (*cachedSigner, error) {
+	next, err := newSigner(ctx, s.log, s.keys, cached.config)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("error creating signer: %w", err))
+	}
+	now := time.Now()
+	current := cached.signer
+	rotated := &rotatedSigner{signer: next}
+	// CAs past their overlap are only closed once the rotation is in place
+	var expired []retiredCA
+	if previous, ok := current.(*rotatedSigner); ok {
+		current = previous.signer
+		for _, old := range previous.retired {
+			if now.Before(old.until) {
+				rotated.retired = append(rotated.retired, old)
+			} else {
+				expired = append(expired, old)
+			}
+		}
+	}
+	rotated.retired = append(rotated.retired, retiredCA{signer: current, until: now.Add(overlap)})
+	if crossSign {
+		rotated.crossSigned, err = crossSignCA(current, next)
+		if err != nil {
+			closeSigner(s.log, signerID, next)
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		}
+	}
+	constraints, err := signerNameConstraints(cached.config, next)
+	if err != nil {
+		closeSigner(s.log, signerID, next)
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	updated := &cachedSigner{config: cached.config, rules: cached.rules, constraints: constraints, signer: rotated}
+
+	// persist the new key before it issues anything
+	if err := s.syncSignerKey(ctx, signerID, cached.config, rotated); err != nil {
+		closeSigner(s.log, signerID, next)
+		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to persist rotated CA key: %w", err))
+	}
+	s.lock.Lock()
+	oldCache := s.signerCache.Load().(signerCache)
+	if winner := oldCache[signerID]; winner != cached {
+		s.lock.Unlock()
+		closeSigner(s.log, signerID, next)
+		s.restoreSignerKey(ctx, signerID, winner)
+		return nil, connect.NewError(connect.CodeAborted, errors.New("signer was modified concurrently"))
+	}
+	newCache := make(signerCache)
+	for k, v := range oldCache {
+		newCache[k] = v
+	}
+	newCache[signerID] = updated
+	s.storeSignerCache(newCache)
+	s.lock.Unlock()
+	for _, old := range expired {
+		closeSigner(s.log, signerID, old.signer)
+	}
+
+	s.log.Info("rotated signer CA",
+		zap.Int64("signer_id", signerID),
+		zap.String("serial", serialString(next.TrustBundle()[0].SerialNumber)),
+		zap.Duration("overlap", overlap),
+		zap.Bool("cross_signed", rotated.crossSigned != nil),
+	)
+	return updated, nil
+}
+
+// restoreSignerKey persists the keys of the signer that replaced a failed
+// rotation in the cache, undoing the rotation's own.
+func (s *Server) restoreSignerKey(ctx context.Context, signerID int64, winner *cachedSigner) {
+	var err error
+	if winner == nil {
+		err = s.deleteSignerKeys(ctx, signerID)
+	} else {
+		err = s.syncSignerKey(ctx, signerID, winner.config, winner.signer)
+	}
+	if err != nil {
+		s.log.Error("failed to restore signer CA key after an aborted rotation", zap.Int64("signer_id", signerID), zap.Error(err))
+	}
+}
+
+// crossSignCA issues the CA certificate of next again from the CA of
+// current, so that clients trusting current can verify leaves of next.
+// Only roots cross-sign, an intermediate's path length would not allow it.
+func crossSignCA(current, next signer) (*x509.Certificate, error) {
+	issuer, ok := current.(*inMemSigner)
+	if !ok {
+		return nil, errors.New("cross-signing requires the previous CA key to be held in memory")
+	}
+	if len(issuer.chain) != 0 || issuer.cert.CheckSignatureFrom(issuer.cert) != nil {
+		return nil, errors.New("cross-signing requires the previous CA to be a root")
+	}
+	ca := next.TrustBundle()[0]
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	template := *ca
+	template.SerialNumber = serialNumber
+	template.AuthorityKeyId = nil
+	template.SignatureAlgorithm = x509.UnknownSignatureAlgorithm
+	if issuer.cert.NotAfter.Before(template.NotAfter) {
+		template.NotAfter = issuer.cert.NotAfter
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, issuer.cert, ca.PublicKey, issuer.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cross-sign CA: %w", err)
+	}
+	return x509.ParseCertificate(der)
+}
+
+// rotateSigners periodically rotates cached signers whose CA is older than
+// their rotation interval, until the server is closed. Signers that are not
+// cached get a new CA when they are next loaded anyway.
+func (s *Server) rotateSigners() {
+	ticker := time.NewTicker(rotationCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for signerID, cached := range s.signerCache.Load().(signerCache) {
+			interval := cached.config.GetRotation().GetInterval()
+			if interval == nil || !rotatable(cached.config) {
+				continue
+			}
+			if time.Since(cached.TrustBundle()[0].NotBefore) < interval.AsDuration() {
+				continue
+			}
+			overlap := defaultRotationOverlap
+			if o := cached.config.GetRotation().GetOverlap(); o != nil {
+				overlap = o.AsDuration()
+			}
+			if _, err := s.rotateSigner(s.ctx, signerID, cached, overlap, cached.config.GetRotation().GetCrossSign()); err != nil {
+				s.log.Error("failed to rotate signer CA", zap.Int64("signer_id", signerID), zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,230 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+	"golang.org/x/crypto/ocsp"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+)
+
+func TestRevocationDuringRotationOverlap(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, WithBaseURL("https://northfoot.example"))
+	createTestSigner(t, s, newTestSigner(1))
+	issue := func() (*x509.Certificate, *x509.Certificate) {
+		t.Helper()
+		resp, err := s.Sign(ctx, connect.NewRequest(&signv1.SignRequest{
+			SignerId: 1,
+			Csr:      newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "leaf"}}),
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaf, err := x509.ParseCertificate(resp.Msg.Cert)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cached, err := s.getSigner(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return leaf, cached.TrustBundle()[0]
+	}
+
+	oldLeaf, oldCA := issue()
+	if _, err := s.RotateSigner(ctx, connect.NewRequest(&mgmtv1.RotateSignerRequest{Id: 1, Overlap: durationpb.New(time.Hour)})); err != nil {
+		t.Fatal(err)
+	}
+	newLeaf, newCA := issue()
+	if oldCA.Equal(newCA) {
+		t.Fatal("signer was not rotated")
+	}
+	if _, err := s.RevokeCertificate(ctx, connect.NewRequest(&mgmtv1.RevokeCertificateRequest{Serial: serialString(oldLeaf.SerialNumber)})); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		leaf   *x509.Certificate
+		ca     *x509.Certificate
+		status int
+	}{
+		{"retired CA", oldLeaf, oldCA, ocsp.Revoked},
+		{"current CA", newLeaf, newCA, ocsp.Good},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.leaf.CRLDistributionPoints) != 1 {
+				t.Fatalf("leaf has CRL distribution points %v", tt.leaf.CRLDistributionPoints)
+			}
+			rec := httptest.NewRecorder()
+			s.CRLHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.leaf.CRLDistributionPoints[0], nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("GET %s = %d", tt.leaf.CRLDistributionPoints[0], rec.Code)
+			}
+			crl, err := x509.ParseRevocationList(rec.Body.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := crl.CheckSignatureFrom(tt.ca); err != nil {
+				t.Errorf("CRL is not signed by the leaf's CA: %v", err)
+			}
+			revoked := false
+			for _, entry := range crl.RevokedCertificateEntries {
+				revoked = revoked || entry.SerialNumber.Cmp(tt.leaf.SerialNumber) == 0
+			}
+			if revoked != (tt.status == ocsp.Revoked) {
+				t.Errorf("leaf listed on CRL = %v, want %v", revoked, tt.status == ocsp.Revoked)
+			}
+
+			req, err := ocsp.CreateRequest(tt.leaf, tt.ca, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := ocsp.ParseRequest(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			der, err := s.ocspResponse(ctx, 1, parsed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := ocsp.ParseResponseForCert(der, tt.leaf, tt.ca)
+			if err != nil {
+				t.Fatalf("OCSP response is not signed by the leaf's CA: %v", err)
+			}
+			if resp.Status != tt.status {
+				t.Errorf("OCSP status = %d, want %d", resp.Status, tt.status)
+			}
+		})
+	}
+
+	// the current CA's CRL stays at its original URL
+	crl, err := s.GetCRL(ctx, connect.NewRequest(&signv1.GetCRLRequest{SignerId: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := x509.ParseRevocationList(crl.Msg.Crl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parsed.CheckSignatureFrom(newCA); err != nil {
+		t.Errorf("default CRL is not signed by the current CA: %v", err)
+	}
+}
+
+func TestRotateSignerCrossSign(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	createTestSigner(t, s, newTestSigner(1))
+	createTestSigner(t, s, newTestFileSigner(t, 2))
+	crossSign := true
+
+	tests := []struct {
+		name     string
+		id       int64
+		wantCode connect.Code
+	}{
+		{"root CA", 1, 0},
+		{"intermediate CA", 2, connect.CodeFailedPrecondition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, err := s.getSigner(ctx, tt.id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			previous := before.TrustBundle()[0]
+			resp, err := s.RotateSigner(ctx, connect.NewRequest(&mgmtv1.RotateSignerRequest{Id: tt.id, CrossSign: &crossSign}))
+			if tt.wantCode != 0 {
+				if connect.CodeOf(err) != tt.wantCode {
+					t.Fatalf("RotateSigner() error = %v, want %v", err, tt.wantCode)
+				}
+				if after, _ := s.getSigner(ctx, tt.id); !after.TrustBundle()[0].Equal(previous) {
+					t.Error("failed rotation replaced the CA")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Msg.TrustBundle) != 3 {
+				t.Fatalf("trust bundle has %d certificates, want the new, cross-signed and previous CA", len(resp.Msg.TrustBundle))
+			}
+			crossSigned, err := x509.ParseCertificate(resp.Msg.TrustBundle[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := crossSigned.CheckSignatureFrom(previous); err != nil {
+				t.Errorf("cross-signed CA is not issued by the previous CA: %v", err)
+			}
+		})
+	}
+
+	config := newTestFileSigner(t, 3)
+	config.Rotation = &mgmtv1.RotationConfig{CrossSign: true}
+	_, err := s.CreateSigner(ctx, connect.NewRequest(&mgmtv1.CreateSignerRequest{Signer: config}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("creating a cross-signing file signer returned %v, want InvalidArgument", err)
+	}
+}
+
+func TestRotateSignerPersistFailure(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, WithKeyEncryptionKey(bytes.Repeat([]byte{1}, KeyEncryptionKeySize)))
+	config := newTestSigner(1)
+	persist := true
+	config.PersistCaKey = &persist
+	createTestSigner(t, s, config)
+	storedCerts := func() []byte {
+		var certs []byte
+		if err := s.db.QueryRow("SELECT certs FROM signer_keys WHERE signer_id = 1").Scan(&certs); err != nil {
+			t.Fatal(err)
+		}
+		return certs
+	}
+	before := storedCerts()
+	ca := s.signerCache.Load().(signerCache)[1].TrustBundle()[0]
+
+	// persisting the retired CA fails, and with it the whole rotation
+	if _, err := s.db.Exec("DROP TABLE signer_retired_keys"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := s.RotateSigner(ctx, connect.NewRequest(&mgmtv1.RotateSignerRequest{Id: 1}))
+	if connect.CodeOf(err) != connect.CodeInternal {
+		t.Fatalf("RotateSigner() error = %v, want Internal", err)
+	}
+	if !s.signerCache.Load().(signerCache)[1].TrustBundle()[0].Equal(ca) {
+		t.Error("CA whose key was not persisted was cached")
+	}
+	if !bytes.Equal(storedCerts(), before) {
+		t.Error("persisted CA changed")
+	}
+}
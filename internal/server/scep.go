@@ -0,0 +1,435 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/subtle"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mozilla.org/pkcs7"
+	"go.uber.org/zap"
+
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+	"github.com/jakexks/northfoot/internal/authn"
+	"github.com/jakexks/northfoot/internal/server/profile"
+)
+
+const (
+	scepRAValidity     = 30 * 24 * time.Hour
+	maxSCEPRequestSize = 1 << 16
+	// scepRAIdentity is recorded as the requester of the RA certificates
+	// that Northfoot issues to itself to decrypt SCEP requests.
+	scepRAIdentity = "northfoot-scep-ra"
+	// scepChallengeIdentity is the identity of clients that enroll with a
+	// signer's challenge password.
+	scepChallengeIdentity = "scep-challenge-password"
+
+	scepCapabilities = "POSTPKIOperation\nRenewal\nSHA-256\nAES\nSCEPStandard\n"
+
+	scepMessageCertRep    = "3"
+	scepMessageRenewalReq = "17"
+	scepMessagePKCSReq    = "19"
+
+	scepStatusSuccess = "0"
+	scepStatusFailure = "2"
+
+	scepFailBadAlg          = "0"
+	scepFailBadMessageCheck = "1"
+	scepFailBadRequest      = "2"
+)
+
+var (
+	oidSCEPMessageType    = asn1.ObjectIdentifier{2, 16, 840, 1, 113733, 1, 9, 2}
+	oidSCEPPKIStatus      = asn1.ObjectIdentifier{2, 16, 840, 1, 113733, 1, 9, 3}
+	oidSCEPFailInfo       = asn1.ObjectIdentifier{2, 16, 840, 1, 113733, 1, 9, 4}
+	oidSCEPSenderNonce    = asn1.ObjectIdentifier{2, 16, 840, 1, 113733, 1, 9, 5}
+	oidSCEPRecipientNonce = asn1.ObjectIdentifier{2, 16, 840, 1, 113733, 1, 9, 6}
+	oidSCEPTransactionID  = asn1.ObjectIdentifier{2, 16, 840, 1, 113733, 1, 9, 7}
+	oidChallengePassword  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 7}
+)
+
+// scepRAProfile is stamped into RA certificates, which sign CertReps and
+// decrypt requests but must not authenticate TLS peers.
+var scepRAProfile = &profile.Profile{
+	Name:       "scep-ra",
+	KeyUsage:   x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+	MaxPathLen: -1,
+}
+
+// pkcs7EncryptLock guards pkcs7.ContentEncryptionAlgorithm, which pkcs7 only
+// takes as a package variable.
+var pkcs7EncryptLock sync.Mutex
+
+// scepRA is the RSA registration authority certificate and key that SCEP
+// clients encrypt their requests to, issued by issuer.
+type scepRA struct {
+	key      *rsa.PrivateKey
+	cert     *x509.Certificate
+	issuer   *x509.Certificate
+	previous *scepRA
+}
+
+// scepRequest is a verified SCEP pkiMessage.
+type scepRequest struct {
+	messageType   string
+	transactionID string
+	senderNonce   []byte
+	signer        *x509.Certificate
+	chain         []*x509.Certificate
+	csr           []byte
+}
+
+// scepFailure is a SCEP request that is answered with a failure CertRep.
+type scepFailure struct {
+	failInfo string
+	err      error
+}
+
+func (f *scepFailure) Error() string {
+	return f.err.Error()
+}
+
+// SCEPHandler serves SCEP (RFC 8894) for each signer with a challenge
+// password at /scep/{id}.
+func (s *Server) SCEPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(strings.SplitN(strings.TrimPrefix(r.URL.Path, "/scep/"), "/", 2)[0], 10, 64)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		signer, err := s.getSigner(r.Context(), id)
+		if errors.Is(err, errSignerNotFound) || err == nil && signer.config.GetScepChallengePassword() == "" {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			s.log.Error("failed to get signer", zap.Int64("signer_id", id), zap.Error(err))
+			http.Error(w, "failed to get signer", http.StatusInternalServerError)
+			return
+		}
+
+		operation := r.URL.Query().Get("operation")
+		method := http.MethodGet
+		if operation == "PKIOperation" && r.Method == http.MethodPost {
+			method = http.MethodPost
+		}
+		if r.Method != method {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		switch operation {
+		case "GetCACaps":
+			w.Header().Set("Content-Type", "text/plain")
+			io.WriteString(w, scepCapabilities)
+		case "GetCACert":
+			s.scepCACert(w, r, id, signer)
+		case "PKIOperation":
+			s.scepPKIOperation(w, r, id, signer)
+		default:
+			http.Error(w, "unsupported operation", http.StatusBadRequest)
+		}
+	})
+}
+
+func (s *Server) scepCACert(w http.ResponseWriter, r *http.Request, signerID int64, signer *cachedSigner) {
+	ra, err := s.getSCEPRA(r.Context(), signerID, signer)
+	if err != nil {
+		s.log.Error("failed to get SCEP RA", zap.Int64("signer_id", signerID), zap.Error(err))
+		http.Error(w, "failed to get RA certificate", http.StatusInternalServerError)
+		return
+	}
+	raw := ra.cert.Raw
+	for _, cert := range signer.TrustBundle() {
+		raw = append(raw, cert.Raw...)
+	}
+	p7, err := pkcs7.DegenerateCertificate(raw)
+	if err != nil {
+		s.log.Error("failed to encode PKCS#7 certificates", zap.Error(err))
+		http.Error(w, "failed to encode certificates", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-x509-ca-ra-cert")
+	w.Write(p7)
+}
+
+func (s *Server) scepPKIOperation(w http.ResponseWriter, r *http.Request, signerID int64, signer *cachedSigner) {
+	ctx := r.Context()
+	var (
+		msg []byte
+		err error
+	)
+	if r.Method == http.MethodPost {
+		msg, err = io.ReadAll(io.LimitReader(r.Body, maxSCEPRequestSize))
+	} else {
+		msg, err = base64.StdEncoding.DecodeString(r.URL.Query().Get("message"))
+	}
+	if err != nil {
+		http.Error(w, "failed to read message", http.StatusBadRequest)
+		return
+	}
+	ra, err := s.getSCEPRA(ctx, signerID, signer)
+	if err != nil {
+		s.log.Error("failed to get SCEP RA", zap.Int64("signer_id", signerID), zap.Error(err))
+		http.Error(w, "failed to get RA certificate", http.StatusInternalServerError)
+		return
+	}
+	req, err := parseSCEPRequest(msg, ra)
+	if err != nil {
+		// Without a verified request there is nobody to encrypt a reply to.
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var issued *x509.Certificate
+	identity, err := s.scepAuthenticate(ctx, signerID, signer, req)
+	if err == nil {
+		issued, _, err = s.issue(withProtocol(authn.WithIdentity(ctx, identity), "scep"), &signv1.SignRequest{SignerId: signerID, Csr: req.csr})
+		if err != nil {
+			err = &scepFailure{failInfo: scepFailBadRequest, err: err}
+		}
+	}
+	if err == nil {
+		s.log.Info("issued SCEP certificate", zap.Int64("signer_id", signerID), zap.String("identity", identity), zap.String("transaction_id", req.transactionID))
+	} else {
+		s.log.Info("rejected SCEP request", zap.Int64("signer_id", signerID), zap.String("transaction_id", req.transactionID), zap.Error(err))
+	}
+	resp, err := scepCertRep(ra, req, issued, err)
+	if err != nil {
+		s.log.Error("failed to create SCEP response", zap.Int64("signer_id", signerID), zap.Error(err))
+		http.Error(w, "failed to create response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-pki-message")
+	w.Write(resp)
+}
+
+// scepAuthenticate checks the challenge password of an enrollment request,
+// or that a renewal request is signed by a certificate from the signer.
+func (s *Server) scepAuthenticate(ctx context.Context, signerID int64, signer *cachedSigner, req *scepRequest) (string, error) {
+	switch req.messageType {
+	case scepMessagePKCSReq:
+		password, err := challengePassword(req.csr)
+		if err != nil {
+			return "", &scepFailure{failInfo: scepFailBadRequest, err: err}
+		}
+		if subtle.ConstantTimeCompare([]byte(password), []byte(signer.config.GetScepChallengePassword())) != 1 {
+			return "", &scepFailure{failInfo: scepFailBadRequest, err: errors.New("invalid challenge password")}
+		}
+		return scepChallengeIdentity, nil
+	case scepMessageRenewalReq:
+		if err := s.verifyIssuedCertificate(ctx, signerID, signer, req.signer, req.chain); err != nil {
+			return "", &scepFailure{failInfo: scepFailBadMessageCheck, err: err}
+		}
+		if err := checkReenrollCSR(req.csr, req.signer); err != nil {
+			return "", &scepFailure{failInfo: scepFailBadRequest, err: err}
+		}
+		return authn.X509Identity(req.signer), nil
+	default:
+		return "", &scepFailure{failInfo: scepFailBadRequest, err: fmt.Errorf("unsupported message type %s", req.messageType)}
+	}
+}
+
+// parseSCEPRequest verifies the signature on a pkiMessage and decrypts its
+// envelope with the RA key.
+func parseSCEPRequest(msg []byte, ra *scepRA) (*scepRequest, error) {
+	p7, err := pkcs7.Parse(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pkiMessage: %w", err)
+	}
+	// Requests are signed by a self-signed certificate for enrollment, or the
+	// certificate being renewed, which is checked later.
+	if err := p7.Verify(); err != nil {
+		return nil, fmt.Errorf("invalid pkiMessage signature: %w", err)
+	}
+	req := &scepRequest{signer: p7.GetOnlySigner()}
+	if req.signer == nil {
+		return nil, errors.New("pkiMessage must have exactly one signer")
+	}
+	for _, cert := range p7.Certificates {
+		if !cert.Equal(req.signer) {
+			req.chain = append(req.chain, cert)
+		}
+	}
+	if err := p7.UnmarshalSignedAttribute(oidSCEPMessageType, &req.messageType); err != nil {
+		return nil, fmt.Errorf("missing messageType: %w", err)
+	}
+	if err := p7.UnmarshalSignedAttribute(oidSCEPTransactionID, &req.transactionID); err != nil {
+		return nil, fmt.Errorf("missing transactionID: %w", err)
+	}
+	if err := p7.UnmarshalSignedAttribute(oidSCEPSenderNonce, &req.senderNonce); err != nil {
+		return nil, fmt.Errorf("missing senderNonce: %w", err)
+	}
+	envelope, err := pkcs7.Parse(p7.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pkcsPKIEnvelope: %w", err)
+	}
+	for ; ra != nil; ra = ra.previous {
+		if req.csr, err = envelope.Decrypt(ra.cert, ra.key); err == nil {
+			return req, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to decrypt pkcsPKIEnvelope: %w", err)
+}
+
+// scepCertRep creates a CertRep for req, signed by the RA. On success, the
+// issued certificate is encrypted to the certificate that signed the request.
+func scepCertRep(ra *scepRA, req *scepRequest, issued *x509.Certificate, failure error) ([]byte, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	attrs := []pkcs7.Attribute{
+		{Type: oidSCEPMessageType, Value: scepMessageCertRep},
+		{Type: oidSCEPTransactionID, Value: req.transactionID},
+		{Type: oidSCEPSenderNonce, Value: nonce},
+		{Type: oidSCEPRecipientNonce, Value: req.senderNonce},
+	}
+	var content []byte
+	if failure == nil {
+		degenerate, err := pkcs7.DegenerateCertificate(issued.Raw)
+		if err != nil {
+			return nil, err
+		}
+		content, err = encryptSCEP(degenerate, req.signer)
+		if err != nil {
+			failure = &scepFailure{failInfo: scepFailBadAlg, err: err}
+		}
+	}
+	if failure != nil {
+		failInfo := scepFailBadRequest
+		var f *scepFailure
+		if errors.As(failure, &f) {
+			failInfo = f.failInfo
+		}
+		content = nil
+		attrs = append(attrs,
+			pkcs7.Attribute{Type: oidSCEPPKIStatus, Value: scepStatusFailure},
+			pkcs7.Attribute{Type: oidSCEPFailInfo, Value: failInfo},
+		)
+	} else {
+		attrs = append(attrs, pkcs7.Attribute{Type: oidSCEPPKIStatus, Value: scepStatusSuccess})
+	}
+	sd, err := pkcs7.NewSignedData(content)
+	if err != nil {
+		return nil, err
+	}
+	sd.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+	if err := sd.AddSigner(ra.cert, ra.key, pkcs7.SignerInfoConfig{ExtraSignedAttributes: attrs}); err != nil {
+		return nil, err
+	}
+	return sd.Finish()
+}
+
+// encryptSCEP encrypts content to recipient with AES, as advertised by GetCACaps.
+func encryptSCEP(content []byte, recipient *x509.Certificate) ([]byte, error) {
+	pkcs7EncryptLock.Lock()
+	defer pkcs7EncryptLock.Unlock()
+	previous := pkcs7.ContentEncryptionAlgorithm
+	pkcs7.ContentEncryptionAlgorithm = pkcs7.EncryptionAlgorithmAES128CBC
+	defer func() { pkcs7.ContentEncryptionAlgorithm = previous }()
+	return pkcs7.Encrypt(content, []*x509.Certificate{recipient})
+}
+
+// challengePassword returns the challengePassword attribute of a CSR.
+func challengePassword(der []byte) (string, error) {
+	var csr struct {
+		TBS struct {
+			Version    int
+			Subject    asn1.RawValue
+			PublicKey  asn1.RawValue
+			Attributes []struct {
+				Type   asn1.ObjectIdentifier
+				Values []asn1.RawValue `asn1:"set"`
+			} `asn1:"tag:0"`
+		}
+		SignatureAlgorithm pkix.AlgorithmIdentifier
+		Signature          asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(der, &csr); err != nil {
+		return "", fmt.Errorf("failed to parse CSR: %w", err)
+	}
+	for _, attr := range csr.TBS.Attributes {
+		if !attr.Type.Equal(oidChallengePassword) || len(attr.Values) != 1 {
+			continue
+		}
+		var password string
+		if _, err := asn1.Unmarshal(attr.Values[0].FullBytes, &password); err != nil {
+			return "", fmt.Errorf("failed to parse challenge password: %w", err)
+		}
+		return password, nil
+	}
+	return "", errors.New("CSR has no challenge password")
+}
+
+// getSCEPRA returns the RA of a signer, issuing a new one if it is missing,
+// half way to expiry, or from an old CA. The previous RA is kept so requests
+// encrypted to it can still be decrypted.
+func (s *Server) getSCEPRA(ctx context.Context, signerID int64, signer *cachedSigner) (*scepRA, error) {
+	s.scepLock.Lock()
+	defer s.scepLock.Unlock()
+	issuer := signer.TrustBundle()[0]
+	current, found := s.scepRAs[signerID]
+	if found && current.issuer.Equal(issuer) && time.Until(current.cert.NotAfter) > scepRAValidity/2 {
+		return current, nil
+	}
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			Organization: []string{"Northfoot"},
+			CommonName:   "Northfoot SCEP RA",
+		},
+	}, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SCEP RA CSR: %w", err)
+	}
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := signer.Sign(ctx, csr, signOptions{durationHint: scepRAValidity, profile: scepRAProfile})
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue SCEP RA certificate: %w", err)
+	}
+	if err := s.recordCertificate(authn.WithIdentity(ctx, scepRAIdentity), signerID, issuer, csrDER, cert); err != nil {
+		return nil, err
+	}
+	ra := &scepRA{key: key, cert: cert, issuer: issuer}
+	if found && current.issuer.Equal(issuer) {
+		ra.previous = &scepRA{key: current.key, cert: current.cert, issuer: current.issuer}
+	}
+	s.scepRAs[signerID] = ra
+	return ra, nil
+}
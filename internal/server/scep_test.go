@@ -0,0 +1,343 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.mozilla.org/pkcs7"
+	"google.golang.org/protobuf/proto"
+)
+
+var oidAES128CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+
+// scepAttribute is a PKCS#10 attribute.
+type scepAttribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+// newSCEPCSR returns a DER encoded CSR for cn signed by key, with a
+// challengePassword attribute unless password is empty.
+func newSCEPCSR(t *testing.T, key *rsa.PrivateKey, cn, password string) []byte {
+	t.Helper()
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: cn}}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if password == "" {
+		return der
+	}
+	// x509 cannot encode a challengePassword, so add it and sign again
+	var csr struct {
+		TBS                asn1.RawValue
+		SignatureAlgorithm pkix.AlgorithmIdentifier
+		Signature          asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(der, &csr); err != nil {
+		t.Fatal(err)
+	}
+	var tbs struct {
+		Version    int
+		Subject    asn1.RawValue
+		PublicKey  asn1.RawValue
+		Attributes []scepAttribute `asn1:"tag:0"`
+	}
+	if _, err := asn1.Unmarshal(csr.TBS.FullBytes, &tbs); err != nil {
+		t.Fatal(err)
+	}
+	value, err := asn1.Marshal(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tbs.Attributes = []scepAttribute{{Type: oidChallengePassword, Values: []asn1.RawValue{{FullBytes: value}}}}
+	tbsDER, err := asn1.Marshal(tbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256(tbsDER)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr.TBS = asn1.RawValue{FullBytes: tbsDER}
+	csr.Signature = asn1.BitString{Bytes: signature, BitLength: len(signature) * 8}
+	der, err = asn1.Marshal(csr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+// newSCEPRequest returns a pkiMessage carrying csr encrypted to ra, signed
+// with cert and key.
+func newSCEPRequest(t *testing.T, ra *x509.Certificate, messageType string, csr []byte, cert *x509.Certificate, key *rsa.PrivateKey) []byte {
+	t.Helper()
+	envelope, err := pkcs7.Encrypt(csr, []*x509.Certificate{ra})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sd, err := pkcs7.NewSignedData(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sd.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+	if err := sd.AddSigner(cert, key, pkcs7.SignerInfoConfig{ExtraSignedAttributes: []pkcs7.Attribute{
+		{Type: oidSCEPMessageType, Value: messageType},
+		{Type: oidSCEPTransactionID, Value: "transaction"},
+		{Type: oidSCEPSenderNonce, Value: []byte("0123456789abcdef")},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := sd.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+// newSelfSignedSCEPCertificate returns the self-signed certificate that a
+// client signs its first request with.
+func newSelfSignedSCEPCertificate(t *testing.T, key *rsa.PrivateKey) *x509.Certificate {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "device"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestSCEP(t *testing.T) {
+	s := newTestServer(t)
+	signer := newTestSigner(1)
+	signer.ScepChallengePassword = proto.String("secret")
+	createTestSigner(t, s, signer)
+	ca := s.signerCache.Load().(signerCache)[1].signer.TrustBundle()[0]
+	do := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.SCEPHandler().ServeHTTP(rec, httptest.NewRequest(method, path, bytes.NewReader(body)))
+		return rec
+	}
+
+	rec := do(http.MethodGet, "/scep/1?operation=GetCACaps", nil)
+	if rec.Code != http.StatusOK || !bytes.Contains(rec.Body.Bytes(), []byte("AES")) {
+		t.Fatalf("GetCACaps = %d %q", rec.Code, rec.Body.String())
+	}
+	rec = do(http.MethodGet, "/scep/1?operation=GetCACert", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GetCACert = %d", rec.Code)
+	}
+	p7, err := pkcs7.Parse(rec.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p7.Certificates) != 2 || !p7.Certificates[1].Equal(ca) {
+		t.Fatal("GetCACert did not return the RA and CA certificates")
+	}
+	ra := p7.Certificates[0]
+	if err := ra.CheckSignatureFrom(ca); err != nil {
+		t.Errorf("RA certificate is not issued by the CA: %v", err)
+	}
+	if len(ra.ExtKeyUsage) != 0 || ra.KeyUsage&x509.KeyUsageKeyEncipherment == 0 {
+		t.Errorf("RA certificate has extended key usages %v and key usage %v, want only key encipherment and signatures", ra.ExtKeyUsage, ra.KeyUsage)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	selfSigned := newSelfSignedSCEPCertificate(t, key)
+	var issued *x509.Certificate
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		message      func() []byte
+		wantStatus   string
+		wantFailInfo string
+		check        func(t *testing.T, cert *x509.Certificate)
+	}{
+		{
+			name: "enroll",
+			message: func() []byte {
+				return newSCEPRequest(t, ra, scepMessagePKCSReq, newSCEPCSR(t, key, "device", "secret"), selfSigned, key)
+			},
+			wantStatus: scepStatusSuccess,
+			check: func(t *testing.T, cert *x509.Certificate) {
+				if err := cert.CheckSignatureFrom(ca); err != nil {
+					t.Errorf("issued certificate is not signed by the CA: %v", err)
+				}
+				issued = cert
+			},
+		},
+		{
+			name: "wrong challenge password",
+			message: func() []byte {
+				return newSCEPRequest(t, ra, scepMessagePKCSReq, newSCEPCSR(t, key, "device", "guess"), selfSigned, key)
+			},
+			wantStatus:   scepStatusFailure,
+			wantFailInfo: scepFailBadRequest,
+		},
+		{
+			name: "missing challenge password",
+			message: func() []byte {
+				return newSCEPRequest(t, ra, scepMessagePKCSReq, newSCEPCSR(t, key, "device", ""), selfSigned, key)
+			},
+			wantStatus:   scepStatusFailure,
+			wantFailInfo: scepFailBadRequest,
+		},
+		{
+			name: "renew",
+			message: func() []byte {
+				return newSCEPRequest(t, ra, scepMessageRenewalReq, newSCEPCSR(t, key, "device", ""), issued, key)
+			},
+			wantStatus: scepStatusSuccess,
+			check: func(t *testing.T, cert *x509.Certificate) {
+				if cert.Equal(issued) || cert.Subject.CommonName != "device" {
+					t.Error("renewal did not issue a new certificate for the device")
+				}
+			},
+		},
+		{
+			name: "renew with a certificate from another CA",
+			message: func() []byte {
+				return newSCEPRequest(t, ra, scepMessageRenewalReq, newSCEPCSR(t, otherKey, "device", ""), newSelfSignedSCEPCertificate(t, otherKey), otherKey)
+			},
+			wantStatus:   scepStatusFailure,
+			wantFailInfo: scepFailBadMessageCheck,
+		},
+		{
+			name: "renew with a different subject",
+			message: func() []byte {
+				return newSCEPRequest(t, ra, scepMessageRenewalReq, newSCEPCSR(t, key, "other", ""), issued, key)
+			},
+			wantStatus:   scepStatusFailure,
+			wantFailInfo: scepFailBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(http.MethodPost, "/scep/1?operation=PKIOperation", tt.message())
+			if rec.Code != http.StatusOK {
+				t.Fatalf("PKIOperation = %d %q", rec.Code, rec.Body.String())
+			}
+			resp, err := pkcs7.Parse(rec.Body.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := resp.Verify(); err != nil || !resp.GetOnlySigner().Equal(ra) {
+				t.Fatalf("CertRep is not signed by the RA: %v", err)
+			}
+			var status, failInfo string
+			if err := resp.UnmarshalSignedAttribute(oidSCEPPKIStatus, &status); err != nil {
+				t.Fatal(err)
+			}
+			if status != tt.wantStatus {
+				t.Fatalf("pkiStatus = %s, want %s", status, tt.wantStatus)
+			}
+			if status == scepStatusFailure {
+				if err := resp.UnmarshalSignedAttribute(oidSCEPFailInfo, &failInfo); err != nil || failInfo != tt.wantFailInfo {
+					t.Errorf("failInfo = %s, want %s", failInfo, tt.wantFailInfo)
+				}
+				return
+			}
+
+			var envelope struct {
+				ContentType asn1.ObjectIdentifier
+				Content     struct {
+					Version              int
+					RecipientInfos       asn1.RawValue
+					EncryptedContentInfo struct {
+						ContentType asn1.ObjectIdentifier
+						Algorithm   pkix.AlgorithmIdentifier
+					}
+				} `asn1:"explicit,tag:0"`
+			}
+			if _, err := asn1.Unmarshal(resp.Content, &envelope); err != nil {
+				t.Fatal(err)
+			}
+			if alg := envelope.Content.EncryptedContentInfo.Algorithm.Algorithm; !alg.Equal(oidAES128CBC) {
+				t.Errorf("CertRep is encrypted with %v, want AES-128-CBC", alg)
+			}
+			if pkcs7.ContentEncryptionAlgorithm != pkcs7.EncryptionAlgorithmDESCBC {
+				t.Error("pkcs7 default content encryption algorithm was changed")
+			}
+			p7, err := pkcs7.Parse(resp.Content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the CertRep is encrypted to the certificate that signed the request
+			recipient := selfSigned
+			if issued != nil {
+				recipient = issued
+			}
+			degenerate, err := p7.Decrypt(recipient, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			certs, err := pkcs7.Parse(degenerate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, certs.Certificates[0])
+		})
+	}
+
+	for _, tt := range []struct {
+		name   string
+		method string
+		path   string
+		body   []byte
+		want   int
+	}{
+		{"malformed message", http.MethodPost, "/scep/1?operation=PKIOperation", []byte("garbage"), http.StatusBadRequest},
+		{"unknown operation", http.MethodGet, "/scep/1?operation=GetNextCACert", nil, http.StatusBadRequest},
+		{"unknown signer", http.MethodGet, "/scep/9?operation=GetCACaps", nil, http.StatusNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := do(tt.method, tt.path, tt.body); rec.Code != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, rec.Code, tt.want)
+			}
+		})
+	}
+}
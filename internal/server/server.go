@@ -19,27 +19,61 @@ package server
 
 import (
 	"context"
+	"crypto/x509"
 	"database/sql"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
 	_ "modernc.org/sqlite"
 
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
 	"github.com/jakexks/northfoot/api/mgmt/v1/mgmtv1connect"
 	"github.com/jakexks/northfoot/api/sign/v1/signv1connect"
+	"github.com/jakexks/northfoot/internal/authn"
 )
 
+// signerLoadTimeout bounds how long creating a signer may take, e.g. while
+// waiting for a remote upstream.
+const signerLoadTimeout = 30 * time.Second
+
 type Server struct {
 	// options
 	datastore string
 	log       *zap.Logger
+	baseURL   string
+	basicAuth authn.BasicAuthFunc
+	// encrypts persisted CA keys
+	kek           []byte
+	kekPassphrase string
+	keyPoolSize   int
 
 	// internal
-	db          *sql.DB
+	db *sql.DB
+	// cancelled by Close to stop background work
+	ctx         context.Context
+	cancel      context.CancelFunc
+	background  sync.WaitGroup
 	signerCache atomic.Value
-	lock        sync.Mutex
+	// incremented whenever signerCache is replaced
+	signerGeneration atomic.Uint64
+	signerLoads      map[int64]*signerLoad
+	// explicitly trusted client CAs, set by TLSConfig
+	clientCAs  []*x509.Certificate
+	clientAuth atomic.Pointer[clientAuth]
+	keys       *keyPool
+	lock       sync.Mutex
+	crlLock    sync.Mutex
+	crls       map[caKey]*signedCRL
+	// incremented whenever CRLs are dropped
+	crlEpoch   uint64
+	ocspLock   sync.Mutex
+	responders map[caKey]*ocspResponder
+	scepLock   sync.Mutex
+	scepRAs    map[int64]*scepRA
 
 	// interfaces
 	signv1connect.UnimplementedSignServiceHandler
@@ -64,21 +98,140 @@ func (s *Server) Init() error {
 		s.log.Error("failed to init database", zap.String("datastore", s.datastore))
 		return err
 	}
+	if err := s.initKeyEncryptionKey(); err != nil {
+		s.log.Error("failed to derive key encryption key", zap.String("datastore", s.datastore))
+		return err
+	}
 	if err := s.initSigners(); err != nil {
 		s.log.Error("failed to init signers", zap.String("datastore", s.datastore))
 		return err
 	}
-	s.signerCache.Store(make(signerCache))
+	s.storeSignerCache(make(signerCache))
+	s.signerLoads = make(map[int64]*signerLoad)
+	s.crls = make(map[caKey]*signedCRL)
+	s.responders = make(map[caKey]*ocspResponder)
+	s.scepRAs = make(map[int64]*scepRA)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	if s.keyPoolSize > 0 {
+		s.keys = newKeyPool(s.ctx, s.log, s.keyPoolSize)
+	}
+	if err := s.loadSigners(); err != nil {
+		s.log.Error("failed to load signers", zap.String("datastore", s.datastore))
+		s.Close()
+		return err
+	}
+	s.background.Add(2)
+	go func() {
+		defer s.background.Done()
+		s.refreshCRLs()
+	}()
+	go func() {
+		defer s.background.Done()
+		s.rotateSigners()
+	}()
 	return nil
 }
 
+// Close stops the background work of the server, releases the resources of
+// its signers and closes the datastore.
+func (s *Server) Close() error {
+	s.cancel()
+	s.background.Wait()
+	s.keys.wait()
+	s.lock.Lock()
+	cache := s.signerCache.Load().(signerCache)
+	s.storeSignerCache(make(signerCache))
+	s.lock.Unlock()
+	for id, cached := range cache {
+		closeSigner(s.log, id, cached.signer)
+	}
+	return s.db.Close()
+}
+
 func (s *Server) initDB() error {
 	// ensure tables exist
 	s.log.Info("ensuring signer table exists in DB")
 	signerTable := `CREATE TABLE IF NOT EXISTS "signers" (
 		"signer"	TEXT NOT NULL COLLATE BINARY
 	);`
-	_, err := s.db.Exec(signerTable)
+	if _, err := s.db.Exec(signerTable); err != nil {
+		return err
+	}
+	s.log.Info("ensuring certificate table exists in DB")
+	certificateTable := `CREATE TABLE IF NOT EXISTS "certificates" (
+		"serial"	TEXT NOT NULL PRIMARY KEY,
+		"signer_id"	INTEGER NOT NULL,
+		"subject"	TEXT NOT NULL,
+		"sans"	TEXT NOT NULL,
+		"not_before"	INTEGER NOT NULL,
+		"not_after"	INTEGER NOT NULL,
+		"requester"	TEXT NOT NULL,
+		"csr_sha256"	TEXT NOT NULL,
+		"cert"	BLOB NOT NULL,
+		"issuer_serial"	TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX IF NOT EXISTS "certificates_signer_id" ON "certificates" ("signer_id");
+	CREATE INDEX IF NOT EXISTS "certificates_not_after" ON "certificates" ("not_after");`
+	if _, err := s.db.Exec(certificateTable); err != nil {
+		return err
+	}
+	// certificates recorded before their issuing CA was have an empty
+	// issuer_serial
+	if err := s.ensureColumn("certificates", "issuer_serial", `TEXT NOT NULL DEFAULT ''`); err != nil {
+		return err
+	}
+	s.log.Info("ensuring revocation table exists in DB")
+	revocationTable := `CREATE TABLE IF NOT EXISTS "revocations" (
+		"serial"	TEXT NOT NULL PRIMARY KEY,
+		"revoked_at"	INTEGER NOT NULL,
+		"reason"	INTEGER NOT NULL
+	);`
+	if _, err := s.db.Exec(revocationTable); err != nil {
+		return err
+	}
+	s.log.Info("ensuring role binding table exists in DB")
+	roleBindingTable := `CREATE TABLE IF NOT EXISTS "role_bindings" (
+		"identity"	TEXT NOT NULL,
+		"role"	INTEGER NOT NULL,
+		"binding"	TEXT NOT NULL COLLATE BINARY,
+		PRIMARY KEY ("identity", "role")
+	);`
+	if _, err := s.db.Exec(roleBindingTable); err != nil {
+		return err
+	}
+	s.log.Info("ensuring signer key tables exist in DB")
+	signerKeyTables := `CREATE TABLE IF NOT EXISTS "signer_keys" (
+		"signer_id"	INTEGER NOT NULL PRIMARY KEY,
+		"key"	BLOB NOT NULL,
+		"certs"	BLOB NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS "signer_retired_keys" (
+		"signer_id"	INTEGER NOT NULL,
+		"serial"	TEXT NOT NULL,
+		"key"	BLOB NOT NULL,
+		"certs"	BLOB NOT NULL,
+		"retire_at"	INTEGER NOT NULL,
+		"cross_signed"	BLOB,
+		PRIMARY KEY ("signer_id", "serial")
+	);
+	CREATE TABLE IF NOT EXISTS "key_encryption" (
+		"salt"	BLOB NOT NULL
+	);`
+	_, err := s.db.Exec(signerKeyTables)
+	return err
+}
+
+// ensureColumn adds a column to a table created before the column existed.
+func (s *Server) ensureColumn(table, column, definition string) error {
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	s.log.Info("adding column to table in DB", zap.String("table", table), zap.String("column", column))
+	_, err := s.db.Exec(fmt.Sprintf(`ALTER TABLE %q ADD COLUMN %q %s`, table, column, definition))
 	return err
 }
 
@@ -106,8 +259,49 @@ func (s *Server) initSigners() error {
 	return result.Err()
 }
 
+// loadSigners creates every signer in the datastore up front, so that no
+// request waits for a CA key to be generated. Signers that fail to load are
+// logged and retried on their first request.
+func (s *Server) loadSigners() error {
+	rows, err := s.db.Query(`SELECT signer FROM signers`)
+	if err != nil {
+		return err
+	}
+	var signers []*mgmtv1.Signer
+	for rows.Next() {
+		raw := ""
+		if err := rows.Scan(&raw); err != nil {
+			rows.Close()
+			return err
+		}
+		signer := &mgmtv1.Signer{}
+		if err := protojson.Unmarshal([]byte(raw), signer); err != nil {
+			rows.Close()
+			return err
+		}
+		signers = append(signers, signer)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	for _, signer := range signers {
+		s.keys.warm(signer)
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			if _, err := s.populateSigner(s.ctx, id); err != nil {
+				s.log.Error("failed to load signer", zap.Int64("signer_id", id), zap.Error(err))
+			}
+		}(signer.GetId())
+	}
+	wg.Wait()
+	return nil
+}
+
 func NewServer(options ...ServerOption) (*Server, error) {
-	s := &Server{}
+	s := &Server{keyPoolSize: defaultKeyPoolSize}
 	for _, option := range options {
 		err := option(s)
 		if err != nil {
@@ -0,0 +1,519 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"database/sql"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/internal/util"
+)
+
+// newTestServer returns an initialised server backed by a temporary
+// datastore, closed when the test ends.
+func newTestServer(t *testing.T, options ...ServerOption) *Server {
+	t.Helper()
+	options = append([]ServerOption{
+		WithLogger(zap.NewNop()),
+		WithDatastore(filepath.Join(t.TempDir(), "northfoot.db")),
+		WithKeyPoolSize(0),
+	}, options...)
+	s, err := NewServer(options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// newTestSigner returns the config of an in memory EC signer.
+func newTestSigner(id int64) *mgmtv1.Signer {
+	return &mgmtv1.Signer{
+		Id:   &id,
+		Type: mgmtv1.SignerType_SIGNER_TYPE_INMEM,
+		SignerConfig: &mgmtv1.Signer_InMem{
+			InMem: &mgmtv1.SignerInMemConfig{
+				Key: mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC,
+			},
+		},
+	}
+}
+
+// newTestCA returns a new self-signed CA and its key.
+func newTestCA(t *testing.T) (*x509.Certificate, crypto.Signer) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := caOptions(nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := util.GenerateSelfSignedCA(key, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+// newTestFileSigner writes a new root CA to the filesystem and returns the
+// config of a file signer whose parent it is.
+func newTestFileSigner(t *testing.T, id int64) *mgmtv1.Signer {
+	t.Helper()
+	cert, key := newTestCA(t)
+	certPath, keyPath := writeTestPEM(t, t.TempDir(), key, cert)
+	return &mgmtv1.Signer{
+		Id:   &id,
+		Type: mgmtv1.SignerType_SIGNER_TYPE_FILE,
+		SignerConfig: &mgmtv1.Signer_File{
+			File: &mgmtv1.SignerFileConfig{
+				TlsCertFilePath: certPath,
+				TlsKeyFilePath:  keyPath,
+				Key:             mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC,
+			},
+		},
+	}
+}
+
+// writeTestPEM writes the certificates and key of a file signer's parent to
+// dir, returning the paths of the certificate and key files.
+func writeTestPEM(t *testing.T, dir string, key crypto.Signer, certs ...*x509.Certificate) (string, string) {
+	t.Helper()
+	var certPEM []byte
+	for _, cert := range certs {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPath, keyPath := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	if err := os.WriteFile(certPath, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certPath, keyPath
+}
+
+// createTestSigner stores signer in the datastore of s.
+func createTestSigner(t *testing.T, s *Server, signer *mgmtv1.Signer) {
+	t.Helper()
+	if _, err := s.CreateSigner(context.Background(), connect.NewRequest(&mgmtv1.CreateSignerRequest{Signer: signer})); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestCSR returns a DER encoded CSR for template, signed by a new key.
+func newTestCSR(t *testing.T, template *x509.CertificateRequest) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return csr
+}
+
+func TestDeleteSigner(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	for id := int64(1); id <= 3; id++ {
+		createTestSigner(t, s, newTestSigner(id))
+	}
+
+	if _, err := s.DeleteSigner(ctx, connect.NewRequest(&mgmtv1.DeleteSignerRequest{Id: 2})); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.ListSigners(ctx, connect.NewRequest(&emptypb.Empty{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, signer := range resp.Msg.Signers.GetSigners() {
+		ids = append(ids, signer.GetId())
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Errorf("signers after deleting 2 = %v, want [1 3]", ids)
+	}
+	if _, found := s.signerCache.Load().(signerCache)[2]; found {
+		t.Error("deleted signer is still cached")
+	}
+
+	_, err = s.DeleteSigner(ctx, connect.NewRequest(&mgmtv1.DeleteSignerRequest{Id: 2}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("deleting a missing signer returned %v, want NotFound", err)
+	}
+}
+
+func TestInitMigratesCertificates(t *testing.T) {
+	datastore := filepath.Join(t.TempDir(), "northfoot.db")
+	db, err := sql.Open("sqlite", datastore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`CREATE TABLE "certificates" (
+		"serial"	TEXT NOT NULL PRIMARY KEY,
+		"signer_id"	INTEGER NOT NULL,
+		"subject"	TEXT NOT NULL,
+		"sans"	TEXT NOT NULL,
+		"not_before"	INTEGER NOT NULL,
+		"not_after"	INTEGER NOT NULL,
+		"requester"	TEXT NOT NULL,
+		"csr_sha256"	TEXT NOT NULL,
+		"cert"	BLOB NOT NULL
+	);
+	INSERT INTO certificates VALUES ('01', 1, '', '', 0, 0, '', '', x'00');`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	s := newTestServer(t, WithDatastore(datastore))
+	var issuerSerial string
+	if err := s.db.QueryRow(`SELECT issuer_serial FROM certificates WHERE serial = '01'`).Scan(&issuerSerial); err != nil {
+		t.Fatal(err)
+	}
+	if issuerSerial != "" {
+		t.Errorf("issuer serial of an existing certificate = %q, want empty", issuerSerial)
+	}
+}
+
+func TestUpdateSignerFieldMask(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	for _, tt := range []struct {
+		name        string
+		paths       []string
+		update      *mgmtv1.Signer
+		allowNewKey bool
+		wantCode    connect.Code
+		check       func(t *testing.T, signer *mgmtv1.Signer)
+		wantNewKey  bool
+	}{
+		{
+			name:   "set field",
+			paths:  []string{"name"},
+			update: &mgmtv1.Signer{Name: proto.String("renamed")},
+			check: func(t *testing.T, signer *mgmtv1.Signer) {
+				if signer.GetName() != "renamed" {
+					t.Errorf("name = %q, want renamed", signer.GetName())
+				}
+			},
+		},
+		{
+			name:   "fields outside the mask are kept",
+			paths:  []string{"name"},
+			update: &mgmtv1.Signer{Name: proto.String("renamed"), Description: proto.String("ignored")},
+			check: func(t *testing.T, signer *mgmtv1.Signer) {
+				if signer.GetDescription() != "original" {
+					t.Errorf("description = %q, want original", signer.GetDescription())
+				}
+			},
+		},
+		{
+			name:   "unset field in the mask is cleared",
+			paths:  []string{"description"},
+			update: &mgmtv1.Signer{},
+			check: func(t *testing.T, signer *mgmtv1.Signer) {
+				if signer.Description != nil {
+					t.Errorf("description = %q, want it cleared", signer.GetDescription())
+				}
+			},
+		},
+		{
+			name:   "nested path keeps sibling fields",
+			paths:  []string{"policy.allowed_dns_names"},
+			update: &mgmtv1.Signer{Policy: &mgmtv1.IssuancePolicy{AllowedDnsNames: []string{"*.edge.example.com"}}},
+			check: func(t *testing.T, signer *mgmtv1.Signer) {
+				policy := signer.GetPolicy()
+				if len(policy.GetAllowedDnsNames()) != 1 || policy.GetAllowedDnsNames()[0] != "*.edge.example.com" {
+					t.Errorf("allowed DNS names = %v, want [*.edge.example.com]", policy.GetAllowedDnsNames())
+				}
+				if len(policy.GetDeniedDnsNames()) != 1 || policy.GetDeniedDnsNames()[0] != "admin.example.com" {
+					t.Errorf("denied DNS names = %v, want [admin.example.com]", policy.GetDeniedDnsNames())
+				}
+			},
+		},
+		{
+			name:     "missing mask",
+			update:   &mgmtv1.Signer{Name: proto.String("renamed")},
+			wantCode: connect.CodeInvalidArgument,
+		},
+		{
+			name:     "unknown path",
+			paths:    []string{"nickname"},
+			update:   &mgmtv1.Signer{},
+			wantCode: connect.CodeInvalidArgument,
+		},
+		{
+			name:     "id path",
+			paths:    []string{"id"},
+			update:   &mgmtv1.Signer{},
+			wantCode: connect.CodeInvalidArgument,
+		},
+		{
+			name:     "version path",
+			paths:    []string{"version"},
+			update:   &mgmtv1.Signer{Version: proto.Int64(7)},
+			wantCode: connect.CodeInvalidArgument,
+		},
+		{
+			name:     "key change without allow_new_key",
+			paths:    []string{"in_mem.key"},
+			update:   &mgmtv1.Signer{SignerConfig: &mgmtv1.Signer_InMem{InMem: &mgmtv1.SignerInMemConfig{Key: mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_ED25519}}},
+			wantCode: connect.CodeFailedPrecondition,
+		},
+		{
+			name:        "key change with allow_new_key",
+			paths:       []string{"in_mem.key"},
+			update:      &mgmtv1.Signer{SignerConfig: &mgmtv1.Signer_InMem{InMem: &mgmtv1.SignerInMemConfig{Key: mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_ED25519}}},
+			allowNewKey: true,
+			wantNewKey:  true,
+			check: func(t *testing.T, signer *mgmtv1.Signer) {
+				if key := signer.GetInMem().GetKey(); key != mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_ED25519 {
+					t.Errorf("key type = %v, want Ed25519", key)
+				}
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			id := int64(len(s.signerCache.Load().(signerCache)) + 1)
+			signer := newTestSigner(id)
+			signer.Name = proto.String("original")
+			signer.Description = proto.String("original")
+			signer.Policy = &mgmtv1.IssuancePolicy{
+				AllowedDnsNames: []string{"*.example.com"},
+				DeniedDnsNames:  []string{"admin.example.com"},
+			}
+			createTestSigner(t, s, signer)
+			before := s.signerCache.Load().(signerCache)[id].signer.TrustBundle()[0]
+
+			tt.update.Id = proto.Int64(id)
+			req := &mgmtv1.UpdateSignerRequest{Signer: tt.update, AllowNewKey: tt.allowNewKey}
+			if tt.paths != nil {
+				req.UpdateMask = &fieldmaskpb.FieldMask{Paths: tt.paths}
+			}
+			resp, err := s.UpdateSigner(ctx, connect.NewRequest(req))
+			if tt.wantCode != 0 {
+				if connect.CodeOf(err) != tt.wantCode {
+					t.Fatalf("UpdateSigner() error = %v, want %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Msg.Signer.GetVersion() != 2 {
+				t.Errorf("version = %d, want 2", resp.Msg.Signer.GetVersion())
+			}
+			if resp.Msg.NewKey != tt.wantNewKey {
+				t.Errorf("new key = %v, want %v", resp.Msg.NewKey, tt.wantNewKey)
+			}
+			got, err := s.GetSigner(ctx, connect.NewRequest(&mgmtv1.GetSignerRequest{Id: id}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, got.Msg.Signer)
+			after := s.signerCache.Load().(signerCache)[id].signer.TrustBundle()[0]
+			if after.Equal(before) == tt.wantNewKey {
+				t.Errorf("CA replaced = %v, want %v", !after.Equal(before), tt.wantNewKey)
+			}
+		})
+	}
+}
+
+func TestUpdateSignerVersion(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	createTestSigner(t, s, newTestSigner(1))
+	update := func(name string, version *int64) error {
+		_, err := s.UpdateSigner(ctx, connect.NewRequest(&mgmtv1.UpdateSignerRequest{
+			Signer:     &mgmtv1.Signer{Id: proto.Int64(1), Name: proto.String(name)},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+			Version:    version,
+		}))
+		return err
+	}
+
+	if err := update("first", proto.Int64(1)); err != nil {
+		t.Fatalf("update at the current version: %v", err)
+	}
+	if err := update("lost", proto.Int64(1)); connect.CodeOf(err) != connect.CodeAborted {
+		t.Errorf("update at a stale version returned %v, want Aborted", err)
+	}
+	if err := update("second", nil); err != nil {
+		t.Fatalf("update without a version: %v", err)
+	}
+	if err := update("third", proto.Int64(3)); err != nil {
+		t.Fatalf("update at the current version: %v", err)
+	}
+	got, err := s.GetSigner(ctx, connect.NewRequest(&mgmtv1.GetSignerRequest{Id: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Msg.Signer.GetName() != "third" || got.Msg.Signer.GetVersion() != 4 {
+		t.Errorf("signer = %q at version %d, want third at version 4", got.Msg.Signer.GetName(), got.Msg.Signer.GetVersion())
+	}
+	_, err = s.UpdateSigner(ctx, connect.NewRequest(&mgmtv1.UpdateSignerRequest{
+		Signer:     &mgmtv1.Signer{Id: proto.Int64(2)},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+	}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("updating a missing signer returned %v, want NotFound", err)
+	}
+}
+
+// newTestRemoteSigner returns the config of a remote Northfoot signer with an
+// API token and a SCEP challenge password.
+func newTestRemoteSigner(id int64, endpoint string) *mgmtv1.Signer {
+	return &mgmtv1.Signer{
+		Id:                    &id,
+		Type:                  mgmtv1.SignerType_SIGNER_TYPE_REMOTE,
+		ScepChallengePassword: proto.String("challenge"),
+		SignerConfig: &mgmtv1.Signer_Remote{Remote: &mgmtv1.SignerRemoteConfig{
+			RemoteType: mgmtv1.RemoteType_REMOTE_TYPE_NORTHFOOT,
+			RemoteConfig: &mgmtv1.SignerRemoteConfig_Northfoot{Northfoot: &mgmtv1.RemoteNorthfootConfig{
+				Endpoint: endpoint,
+				Token:    proto.String("token"),
+				SignerId: 1,
+				Key:      mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC,
+			}},
+		}},
+	}
+}
+
+func TestUpdateSignerKeepsSecrets(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t)
+	// nothing listens on the endpoint, the signer is only stored
+	createTestSigner(t, s, newTestRemoteSigner(1, "https://127.0.0.1:1"))
+
+	got, err := s.GetSigner(ctx, connect.NewRequest(&mgmtv1.GetSignerRequest{Id: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Msg.Signer.ScepChallengePassword != nil || got.Msg.Signer.GetRemote().GetNorthfoot().Token != nil {
+		t.Fatal("GetSigner() returned a secret")
+	}
+	got.Msg.Signer.Name = proto.String("renamed")
+	resp, err := s.UpdateSigner(ctx, connect.NewRequest(&mgmtv1.UpdateSignerRequest{
+		Signer:     got.Msg.Signer,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name", "remote", "scep_challenge_password"}},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Msg.NewKey {
+		t.Error("writing back the redacted signer replaced its CA")
+	}
+	if resp.Msg.Signer.ScepChallengePassword != nil || resp.Msg.Signer.GetRemote().GetNorthfoot().Token != nil {
+		t.Error("UpdateSigner() returned a secret")
+	}
+
+	var raw string
+	if err := s.db.QueryRow("SELECT signer FROM signers").Scan(&raw); err != nil {
+		t.Fatal(err)
+	}
+	stored := &mgmtv1.Signer{}
+	if err := protojson.Unmarshal([]byte(raw), stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.GetName() != "renamed" {
+		t.Errorf("stored name = %q, want renamed", stored.GetName())
+	}
+	if stored.GetScepChallengePassword() != "challenge" {
+		t.Errorf("stored SCEP challenge password = %q, want challenge", stored.GetScepChallengePassword())
+	}
+	if token := stored.GetRemote().GetNorthfoot().GetToken(); token != "token" {
+		t.Errorf("stored remote token = %q, want token", token)
+	}
+}
+
+func TestRestoreSecrets(t *testing.T) {
+	hsmSigner := func(library, label, pin string) *mgmtv1.Signer {
+		hsm := &mgmtv1.SignerHSMConfig{HsmLibraryPath: library, HsmTokenLabel: proto.String(label)}
+		if pin != "" {
+			hsm.HsmTokenPin = proto.String(pin)
+		}
+		return &mgmtv1.Signer{Type: mgmtv1.SignerType_SIGNER_TYPE_HSM, SignerConfig: &mgmtv1.Signer_Hsm{Hsm: hsm}}
+	}
+	remoteSigner := func(endpoint, token, password string) *mgmtv1.Signer {
+		signer := redactSigner(newTestRemoteSigner(1, endpoint))
+		if token != "" {
+			signer.GetRemote().GetNorthfoot().Token = proto.String(token)
+		}
+		if password != "" {
+			signer.ScepChallengePassword = proto.String(password)
+		}
+		return signer
+	}
+	secrets := func(signer *mgmtv1.Signer) [3]string {
+		return [3]string{signer.GetHsm().GetHsmTokenPin(), signer.GetRemote().GetNorthfoot().GetToken(), signer.GetScepChallengePassword()}
+	}
+
+	tests := []struct {
+		name     string
+		current  *mgmtv1.Signer
+		updated  *mgmtv1.Signer
+		wantPIN  string
+		wantTok  string
+		wantPass string
+	}{
+		{"redacted PIN is kept", hsmSigner("/lib/pkcs11.so", "northfoot", "1234"), hsmSigner("/lib/pkcs11.so", "northfoot", ""), "1234", "", ""},
+		{"new PIN replaces it", hsmSigner("/lib/pkcs11.so", "northfoot", "1234"), hsmSigner("/lib/pkcs11.so", "northfoot", "5678"), "5678", "", ""},
+		{"PIN is not kept for another token", hsmSigner("/lib/pkcs11.so", "northfoot", "1234"), hsmSigner("/lib/pkcs11.so", "other", ""), "", "", ""},
+		{"PIN is not kept for another module", hsmSigner("/lib/pkcs11.so", "northfoot", "1234"), hsmSigner("/lib/other.so", "northfoot", ""), "", "", ""},
+		{"signer is no longer an HSM", hsmSigner("/lib/pkcs11.so", "northfoot", "1234"), newTestSigner(1), "", "", ""},
+		{"redacted token and password are kept", newTestRemoteSigner(1, "https://ca.example.com"), remoteSigner("https://ca.example.com", "", ""), "", "token", "challenge"},
+		{"new token and password replace them", newTestRemoteSigner(1, "https://ca.example.com"), remoteSigner("https://ca.example.com", "new", "new"), "", "new", "new"},
+		{"token is not kept for another endpoint", newTestRemoteSigner(1, "https://ca.example.com"), remoteSigner("https://other.example.com", "", ""), "", "", "challenge"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreSecrets(tt.updated, tt.current)
+			if got, want := secrets(tt.updated), [3]string{tt.wantPIN, tt.wantTok, tt.wantPass}; got != want {
+				t.Errorf("PIN, token and password = %q, want %q", got, want)
+			}
+		})
+	}
+}
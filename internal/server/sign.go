@@ -20,9 +20,13 @@ package server
 import (
 	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"fmt"
 	"math/big"
@@ -30,46 +34,215 @@ import (
 	"time"
 
 	"github.com/bufbuild/connect-go"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ocsp"
 	"google.golang.org/protobuf/encoding/protojson"
 
 	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
 	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+	"github.com/jakexks/northfoot/internal/authn"
+	"github.com/jakexks/northfoot/internal/server/expr"
+	"github.com/jakexks/northfoot/internal/server/profile"
 	"github.com/jakexks/northfoot/internal/server/validation"
 	"github.com/jakexks/northfoot/internal/util"
 )
 
 type signer interface {
-	Sign(ctx context.Context, csr *x509.CertificateRequest, durationHint time.Duration) (*x509.Certificate, error)
+	Sign(ctx context.Context, csr *x509.CertificateRequest, opts signOptions) (*x509.Certificate, error)
 	TrustBundle() []*x509.Certificate
+	CRL(revoked []pkix.RevokedCertificate, number *big.Int, nextUpdate time.Time) ([]byte, error)
+	OCSPResponse(template ocsp.Response) ([]byte, error)
 }
 
-type signerCache map[int64]signer
+// signOptions controls how a signer issues a single certificate.
+type signOptions struct {
+	durationHint time.Duration
+	// extensions to issue with, profile.Default if nil
+	profile *profile.Profile
+	// URLs stamped into the CRL distribution points extension
+	crlDistributionPoints []string
+	// URLs stamped into the authority information access extension
+	ocspServers []string
+}
 
-func (s *Server) Sign(ctx context.Context, req *connect.Request[signv1.SignRequest]) (*connect.Response[signv1.SignResponse], error) {
-	cache := s.signerCache.Load().(signerCache)
-	signer, found := cache[req.Msg.SignerId]
-	if !found {
-		si, err := s.loadSignerFromDataStore(ctx, req.Msg.SignerId)
-		if err != nil {
-			return nil, err
-		}
-		signer = si
+// defaultLifetime is the lifetime of certificates issued without a duration
+// hint.
+const defaultLifetime = time.Hour
+
+var (
+	errSignerNotFound = errors.New("signer not found")
+	errCANotFound     = errors.New("signer has no trusted CA with serial")
+)
+
+type protocolKey struct{}
+
+// withProtocol records the enrollment protocol a request arrived over, for
+// issuance rules. Requests without one came from Sign.
+func withProtocol(ctx context.Context, protocol string) context.Context {
+	return context.WithValue(ctx, protocolKey{}, protocol)
+}
+
+func protocolFromContext(ctx context.Context) string {
+	if protocol, ok := ctx.Value(protocolKey{}).(string); ok {
+		return protocol
+	}
+	return "sign"
+}
+
+// cachedSigner pairs a signer with the configuration it was created from.
+type cachedSigner struct {
+	config *mgmtv1.Signer
+	// compiled from config's CEL rules
+	rules []*expr.Rule
+	// from the signer's CA chain and configuration
+	constraints []util.NameConstraints
+	signer
+}
+
+// resolveProfile returns the profile req asks for, falling back to the
+// signer's default.
+func (c *cachedSigner) resolveProfile(req *signv1.SignRequest) (*profile.Profile, error) {
+	name := req.GetProfile()
+	if name == "" && req.Ca {
+		name = profile.IntermediateCA
 	}
-	csr, err := x509.ParseCertificateRequest(req.Msg.Csr)
+	if name == "" {
+		name = c.config.GetDefaultProfile()
+	}
+	if name == "" {
+		return profile.Default, nil
+	}
+	p, err := profile.Lookup(name, c.config.Profiles)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		return nil, err
+	}
+	if req.Ca && !p.CA {
+		return nil, fmt.Errorf("profile %q does not issue CA certificates", name)
 	}
-	cert, err := signer.Sign(ctx, csr, req.Msg.DurationHint.AsDuration())
+	return p, nil
+}
+
+type signerCache map[int64]*cachedSigner
+
+// storeSignerCache replaces the signer cache. Callers must hold s.lock.
+func (s *Server) storeSignerCache(cache signerCache) {
+	s.signerCache.Store(cache)
+	s.signerGeneration.Add(1)
+}
+
+func (s *Server) Sign(ctx context.Context, req *connect.Request[signv1.SignRequest]) (*connect.Response[signv1.SignResponse], error) {
+	cert, _, err := s.issue(ctx, req.Msg)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, err
 	}
 	return connect.NewResponse(&signv1.SignResponse{
 		Cert: cert.Raw,
 	}), nil
 }
 
-func (s *Server) loadSignerFromDataStore(ctx context.Context, signerID int64) (signer, error) {
-	var signer signer
+// issue is the issuance path shared by every enrollment protocol. It signs
+// and records a certificate for the CSR in req, returning the certificate
+// and the signer that issued it.
+func (s *Server) issue(ctx context.Context, req *signv1.SignRequest) (*x509.Certificate, *cachedSigner, error) {
+	signer, err := s.getSigner(ctx, req.SignerId)
+	if errors.Is(err, errSignerNotFound) {
+		return nil, nil, connect.NewError(connect.CodeNotFound, err)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := signer.resolveProfile(req)
+	if err != nil {
+		return nil, nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if p.CA && !signer.config.GetAllowCaIssuance() {
+		return nil, nil, connect.NewError(connect.CodePermissionDenied, errors.New("signer does not allow CA issuance"))
+	}
+	csr, err := x509.ParseCertificateRequest(req.Csr)
+	if err != nil {
+		return nil, nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	identity, _ := authn.IdentityFromContext(ctx)
+	lifetime, err := checkPolicy(signer.config.Policy, identity, csr, req.DurationHint.AsDuration())
+	if err != nil {
+		return nil, nil, connect.NewError(connect.CodePermissionDenied, err)
+	}
+	if err := checkNameConstraints(signer.constraints, csr); err != nil {
+		return nil, nil, connect.NewError(connect.CodePermissionDenied, err)
+	}
+	if err := s.checkRules(ctx, signer, req, p, csr, identity, lifetime); err != nil {
+		return nil, nil, err
+	}
+	cert, err := signer.Sign(ctx, csr, signOptions{
+		durationHint:          lifetime,
+		profile:               p,
+		crlDistributionPoints: s.crlDistributionPoints(req.SignerId, signer.TrustBundle()[0]),
+		ocspServers:           s.ocspServers(req.SignerId),
+	})
+	if err != nil {
+		return nil, nil, connect.NewError(connect.CodeInternal, err)
+	}
+	if err := s.recordCertificate(ctx, req.SignerId, signer.TrustBundle()[0], req.Csr, cert); err != nil {
+		s.log.Error("failed to record issued certificate", zap.Int64("signer_id", req.SignerId), zap.Error(err))
+		return nil, nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return cert, signer, nil
+}
+
+// getSigner returns the cached signer with the given ID, creating it from
+// the datastore on a cache miss.
+func (s *Server) getSigner(ctx context.Context, signerID int64) (*cachedSigner, error) {
+	cache := s.signerCache.Load().(signerCache)
+	if signer, found := cache[signerID]; found {
+		return signer, nil
+	}
+	return s.populateSigner(ctx, signerID)
+}
+
+// signerLoad is an in-flight load of a signer from the datastore.
+type signerLoad struct {
+	done   chan struct{}
+	signer *cachedSigner
+	err    error
+}
+
+// populateSigner loads a signer from the datastore into the cache. Concurrent
+// callers for the same signer share a single load, so that they all get
+// certificates from the same CA rather than each creating their own.
+func (s *Server) populateSigner(ctx context.Context, signerID int64) (*cachedSigner, error) {
+	s.lock.Lock()
+	if signer, found := s.signerCache.Load().(signerCache)[signerID]; found {
+		s.lock.Unlock()
+		return signer, nil
+	}
+	load, loading := s.signerLoads[signerID]
+	if !loading {
+		load = &signerLoad{done: make(chan struct{})}
+		s.signerLoads[signerID] = load
+	}
+	s.lock.Unlock()
+
+	if loading {
+		select {
+		case <-load.done:
+			return load.signer, load.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	// the load is shared, so it must not be cancelled with the first caller
+	loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), signerLoadTimeout)
+	load.signer, load.err = s.loadSignerFromDataStore(loadCtx, signerID)
+	cancel()
+	s.lock.Lock()
+	delete(s.signerLoads, signerID)
+	s.lock.Unlock()
+	close(load.done)
+	return load.signer, load.err
+}
+
+func (s *Server) loadSignerFromDataStore(ctx context.Context, signerID int64) (*cachedSigner, error) {
+	var signer *cachedSigner
 	q, err := s.db.PrepareContext(ctx, "SELECT signers.signer FROM signers, json_each(signers.signer) WHERE json_each.key = 'id' AND json_each.value = ?")
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
@@ -91,37 +264,49 @@ func (s *Server) loadSignerFromDataStore(ctx context.Context, signerID int64) (s
 		if err := protojson.Unmarshal([]byte(signerJSON), signerPB); err != nil {
 			return nil, fmt.Errorf("error unmarshalling signer: %w", err)
 		}
-		si, err := newSigner(signerPB)
+		// release the read so that creating the signer can persist its key
+		rows.Close()
+		rules, err := compileRules(signerPB.GetPolicy().GetCelRules())
+		if err != nil {
+			return nil, fmt.Errorf("error compiling signer rules: %w", err)
+		}
+		si, err := s.createSigner(ctx, signerID, signerPB)
 		if err != nil {
 			return nil, fmt.Errorf("error creating signer: %w", err)
 		}
+		constraints, err := signerNameConstraints(signerPB, si)
+		if err != nil {
+			return nil, fmt.Errorf("error reading signer name constraints: %w", err)
+		}
+		cached := &cachedSigner{config: signerPB, rules: rules, constraints: constraints, signer: si}
 		s.lock.Lock()
 		oldCache := s.signerCache.Load().(signerCache)
+		if existing, found := oldCache[signerID]; found && existing.config.GetVersion() > signerPB.GetVersion() {
+			// the signer was updated while this one was being created
+			s.lock.Unlock()
+			closeSigner(s.log, signerID, si)
+			return existing, nil
+		}
 		newCache := make(signerCache)
 		for k, v := range oldCache {
 			newCache[k] = v
 		}
-		newCache[signerID] = si
-		s.signerCache.Store(newCache)
+		newCache[signerID] = cached
+		s.storeSignerCache(newCache)
 		s.lock.Unlock()
-		signer = si
+		signer = cached
 		break
 	}
 	if resultCount == 0 {
-		return nil, fmt.Errorf("signer not found")
+		return nil, errSignerNotFound
 	}
 	return signer, nil
 }
 
 func (s *Server) TrustBundle(ctx context.Context, req *connect.Request[signv1.TrustBundleRequest]) (*connect.Response[signv1.TrustBundleResponse], error) {
-	cache := s.signerCache.Load().(signerCache)
-	signer, found := cache[req.Msg.SignerId]
-	if !found {
-		si, err := s.loadSignerFromDataStore(ctx, req.Msg.SignerId)
-		if err != nil {
-			return nil, connect.NewError(connect.CodeInternal, err)
-		}
-		signer = si
+	signer, err := s.getSigner(ctx, req.Msg.SignerId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 	certs := signer.TrustBundle()
 	var resp [][]byte
@@ -133,7 +318,7 @@ func (s *Server) TrustBundle(ctx context.Context, req *connect.Request[signv1.Tr
 	}), nil
 }
 
-func newSigner(s *mgmtv1.Signer) (signer, error) {
+func newSigner(ctx context.Context, log *zap.Logger, keys *keyPool, s *mgmtv1.Signer) (signer, error) {
 	if s == nil {
 		return nil, validation.ErrNilSigner
 	}
@@ -141,49 +326,104 @@ func newSigner(s *mgmtv1.Signer) (signer, error) {
 	case mgmtv1.SignerType_SIGNER_TYPE_UNSPECIFIED:
 		return nil, validation.ErrMissingType
 	case mgmtv1.SignerType_SIGNER_TYPE_INMEM:
-		return newInMemSigner(s.SignerConfig.(*mgmtv1.Signer_InMem))
+		if s.GetInMem() == nil {
+			return nil, validation.ErrNilConfig
+		}
+		return newInMemSigner(keys, s.GetInMem())
+	case mgmtv1.SignerType_SIGNER_TYPE_FILE:
+		if s.GetFile() == nil {
+			return nil, validation.ErrNilConfig
+		}
+		return newFileSigner(keys, s.GetFile())
+	case mgmtv1.SignerType_SIGNER_TYPE_HSM:
+		if s.GetHsm() == nil {
+			return nil, validation.ErrNilConfig
+		}
+		return newHSMSigner(s.GetHsm())
+	case mgmtv1.SignerType_SIGNER_TYPE_REMOTE:
+		if s.GetRemote() == nil {
+			return nil, validation.ErrNilConfig
+		}
+		return newRemoteSigner(ctx, log, keys, s.GetRemote())
 	default:
 		return nil, errors.New("signer type not implemented")
 	}
 }
 
-func newInMemSigner(config *mgmtv1.Signer_InMem) (signer, error) {
-	switch config.InMem.Key {
+func newInMemSigner(keys *keyPool, config *mgmtv1.SignerInMemConfig) (signer, error) {
+	opts, err := caOptions(config.Ca, false)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keys.generate(config.Key, config.KeySize)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := util.GenerateSelfSignedCA(key, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &inMemSigner{
+		key:  key,
+		cert: cert,
+	}, nil
+}
+
+// default sizes of keys whose config does not set one
+const (
+	defaultRSAKeySize = 2048
+	defaultECKeySize  = 256
+)
+
+func generatePrivateKey(keyType mgmtv1.PrivateKeyType, keySize *int64) (crypto.Signer, error) {
+	switch keyType {
 	case mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_UNSPECIFIED:
 		return nil, validation.ErrMissingKeyType
 	case mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_RSA:
-		bits := 2048
-		if config.InMem.KeySize != nil {
-			bits = int(*config.InMem.KeySize)
+		bits := defaultRSAKeySize
+		if keySize != nil {
+			bits = int(*keySize)
 		}
-		key, err := rsa.GenerateKey(rand.Reader, bits)
+		return rsa.GenerateKey(rand.Reader, bits)
+	case mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC:
+		curve, err := ellipticCurve(keySize)
 		if err != nil {
 			return nil, err
 		}
-		cert, err := util.GenerateSelfSignedCA(key)
-		if err != nil {
-			return nil, err
-		}
-		return &inMemSigner{
-			key:         key,
-			cert:        cert,
-			keyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
-			extKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
-		}, nil
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	case mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_ED25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
 	default:
 		return nil, errors.New("key type not implemented")
 	}
 }
 
-type inMemSigner struct {
-	key  crypto.PrivateKey
-	cert *x509.Certificate
+func ellipticCurve(keySize *int64) (elliptic.Curve, error) {
+	if keySize == nil {
+		return elliptic.P256(), nil
+	}
+	switch *keySize {
+	case 256:
+		return elliptic.P256(), nil
+	case 384:
+		return elliptic.P384(), nil
+	case 521:
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("unsupported EC key size %d, must be one of 256, 384 or 521", *keySize)
+	}
+}
 
-	keyUsage    x509.KeyUsage
-	extKeyUsage []x509.ExtKeyUsage
+// inMemSigner issues leaf certificates from a CA whose key is held in memory.
+// chain holds the issuers of cert, if cert is not self-signed.
+type inMemSigner struct {
+	key   crypto.Signer
+	cert  *x509.Certificate
+	chain []*x509.Certificate
 }
 
-func (i *inMemSigner) Sign(ctx context.Context, csr *x509.CertificateRequest, durationHint time.Duration) (*x509.Certificate, error) {
+func (i *inMemSigner) Sign(ctx context.Context, csr *x509.CertificateRequest, opts signOptions) (*x509.Certificate, error) {
 	if csr == nil {
 		return nil, errors.New("csr is nil")
 	}
@@ -194,8 +434,22 @@ func (i *inMemSigner) Sign(ctx context.Context, csr *x509.CertificateRequest, du
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate serial number: %w", err)
 	}
+	durationHint := opts.durationHint
 	if durationHint == 0 {
-		durationHint = time.Hour
+		durationHint = defaultLifetime
+	}
+	notAfter := time.Now().Add(durationHint)
+	if i.cert.NotAfter.Before(notAfter) {
+		notAfter = i.cert.NotAfter
+	}
+	p := opts.profile
+	if p == nil {
+		p = profile.Default
+	}
+	keyUsage := p.KeyUsage
+	if csr.PublicKeyAlgorithm != x509.RSA {
+		// key encipherment is only meaningful for RSA key exchange
+		keyUsage &^= x509.KeyUsageKeyEncipherment
 	}
 	template := &x509.Certificate{
 		Version:               2,
@@ -207,13 +461,32 @@ func (i *inMemSigner) Sign(ctx context.Context, csr *x509.CertificateRequest, du
 		Subject:               csr.Subject,
 		RawSubject:            csr.RawSubject,
 		NotBefore:             time.Now(),
-		NotAfter:              time.Now().Add(durationHint),
-		KeyUsage:              i.keyUsage,
-		ExtKeyUsage:           i.extKeyUsage,
+		NotAfter:              notAfter,
+		KeyUsage:              keyUsage,
+		ExtKeyUsage:           p.ExtKeyUsage,
+		UnknownExtKeyUsage:    p.UnknownExtKeyUsage,
+		ExtraExtensions:       p.Extensions,
 		DNSNames:              csr.DNSNames,
 		IPAddresses:           csr.IPAddresses,
 		EmailAddresses:        csr.EmailAddresses,
 		URIs:                  csr.URIs,
+		CRLDistributionPoints: opts.crlDistributionPoints,
+		OCSPServer:            opts.ocspServers,
+	}
+	if p.CA {
+		if i.cert.MaxPathLenZero {
+			return nil, errors.New("signer CA has a path length of zero and cannot issue intermediates")
+		}
+		if i.cert.MaxPathLen > 0 && (p.MaxPathLen < 0 || p.MaxPathLen >= i.cert.MaxPathLen) {
+			return nil, fmt.Errorf("signer CA has a path length of %d and can only issue intermediates with a shorter one", i.cert.MaxPathLen)
+		}
+		template.IsCA = true
+		template.MaxPathLen = p.MaxPathLen
+		template.MaxPathLenZero = p.MaxPathLen == 0
+		// name constraints only narrow what the intermediate can issue for
+		if ext, ok := util.RequestedNameConstraints(csr); ok {
+			template.ExtraExtensions = append(append([]pkix.Extension{}, p.Extensions...), ext)
+		}
 	}
 	der, err := x509.CreateCertificate(rand.Reader, template, i.cert, csr.PublicKey, i.key)
 	if err != nil {
@@ -223,5 +496,18 @@ func (i *inMemSigner) Sign(ctx context.Context, csr *x509.CertificateRequest, du
 }
 
 func (i *inMemSigner) TrustBundle() []*x509.Certificate {
-	return []*x509.Certificate{i.cert}
+	return append([]*x509.Certificate{i.cert}, i.chain...)
+}
+
+func (i *inMemSigner) CRL(revoked []pkix.RevokedCertificate, number *big.Int, nextUpdate time.Time) ([]byte, error) {
+	return x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		RevokedCertificates: revoked,
+		Number:              number,
+		ThisUpdate:          time.Now(),
+		NextUpdate:          nextUpdate,
+	}, i.cert, i.key)
+}
+
+func (i *inMemSigner) OCSPResponse(template ocsp.Response) ([]byte, error) {
+	return ocsp.CreateResponse(i.cert, i.cert, template, i.key)
 }
@@ -0,0 +1,303 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/proto"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+	"github.com/jakexks/northfoot/api/sign/v1/signv1connect"
+	"github.com/jakexks/northfoot/internal/server/profile"
+)
+
+func TestGetSignerConcurrentLoad(t *testing.T) {
+	upstream := newTestServer(t)
+	issuer := newTestSigner(1)
+	allow := true
+	issuer.AllowCaIssuance = &allow
+	createTestSigner(t, upstream, issuer)
+	// every load of the remote signer below asks the upstream for a CA
+	var loads atomic.Int64
+	path, handler := signv1connect.NewSignServiceHandler(upstream)
+	mux := http.NewServeMux()
+	mux.Handle(path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api.sign.v1.SignService/Sign" {
+			loads.Add(1)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	hs := httptest.NewServer(mux)
+	defer hs.Close()
+
+	s := newTestServer(t)
+	id := int64(7)
+	createTestSigner(t, s, &mgmtv1.Signer{
+		Id:   &id,
+		Type: mgmtv1.SignerType_SIGNER_TYPE_REMOTE,
+		SignerConfig: &mgmtv1.Signer_Remote{Remote: &mgmtv1.SignerRemoteConfig{
+			RemoteType: mgmtv1.RemoteType_REMOTE_TYPE_NORTHFOOT,
+			RemoteConfig: &mgmtv1.SignerRemoteConfig_Northfoot{Northfoot: &mgmtv1.RemoteNorthfootConfig{
+				Endpoint: hs.URL,
+				SignerId: 1,
+				Key:      mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC,
+			}},
+		}},
+	})
+
+	for round := 0; round < 3; round++ {
+		s.lock.Lock()
+		s.storeSignerCache(make(signerCache))
+		s.lock.Unlock()
+		loads.Store(0)
+
+		signers := make([]*cachedSigner, 20)
+		var wg sync.WaitGroup
+		for i := range signers {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				signer, err := s.getSigner(context.Background(), id)
+				if err != nil {
+					t.Error(err)
+				}
+				signers[i] = signer
+			}(i)
+		}
+		wg.Wait()
+
+		if n := loads.Load(); n != 1 {
+			t.Errorf("round %d: signer was loaded %d times, want 1", round, n)
+		}
+		ca := signers[0].TrustBundle()[0]
+		for i, signer := range signers {
+			if signer != signers[0] || !signer.TrustBundle()[0].Equal(ca) {
+				t.Fatalf("round %d: caller %d got a different signer", round, i)
+			}
+		}
+		if cached := s.signerCache.Load().(signerCache)[id]; cached != signers[0] {
+			t.Errorf("round %d: cached signer differs from the one returned", round)
+		}
+		if len(s.signerLoads) != 0 {
+			t.Errorf("round %d: %d loads left in flight", round, len(s.signerLoads))
+		}
+	}
+}
+
+func TestSignProfiles(t *testing.T) {
+	s := newTestServer(t)
+	der, err := asn1.Marshal("northfoot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oidCustom := asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 2}
+	signer := newTestSigner(1)
+	signer.DefaultProfile = proto.String(profile.Client)
+	signer.Profiles = []*mgmtv1.Profile{{
+		Name:       "custom",
+		KeyUsages:  []mgmtv1.KeyUsage{mgmtv1.KeyUsage_KEY_USAGE_DIGITAL_SIGNATURE},
+		Extensions: []*mgmtv1.Extension{{Oid: oidCustom.String(), Value: der}},
+	}}
+	createTestSigner(t, s, signer)
+
+	for _, tt := range []struct {
+		name    string
+		profile string
+		ca      bool
+		wantEKU []x509.ExtKeyUsage
+		wantExt asn1.ObjectIdentifier
+		code    connect.Code
+	}{
+		{name: "signer default", wantEKU: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}},
+		{name: "server", profile: profile.Server, wantEKU: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}},
+		{name: "code signing", profile: profile.CodeSigning, wantEKU: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}},
+		{name: "OCSP signing", profile: profile.OCSPSigning, wantEKU: []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning}, wantExt: asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 5}},
+		{name: "custom extension", profile: "custom", wantExt: oidCustom},
+		{name: "unknown profile", profile: "unknown", code: connect.CodeInvalidArgument},
+		{name: "CA request with a leaf profile", profile: profile.Server, ca: true, code: connect.CodeInvalidArgument},
+		{name: "CA issuance not allowed", profile: profile.IntermediateCA, code: connect.CodePermissionDenied},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &signv1.SignRequest{
+				SignerId: 1,
+				Csr:      newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "leaf"}}),
+				Ca:       tt.ca,
+			}
+			if tt.profile != "" {
+				req.Profile = proto.String(tt.profile)
+			}
+			resp, err := s.Sign(context.Background(), connect.NewRequest(req))
+			if tt.code != 0 {
+				if connect.CodeOf(err) != tt.code {
+					t.Fatalf("Sign() = %v, want %s", err, tt.code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			cert, err := x509.ParseCertificate(resp.Msg.Cert)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cert.IsCA || !reflect.DeepEqual(cert.ExtKeyUsage, tt.wantEKU) {
+				t.Errorf("certificate CA = %v, extended key usages %v, want %v", cert.IsCA, cert.ExtKeyUsage, tt.wantEKU)
+			}
+			if tt.wantExt != nil && !slices.ContainsFunc(cert.Extensions, func(ext pkix.Extension) bool { return ext.Id.Equal(tt.wantExt) }) {
+				t.Errorf("certificate is missing extension %s", tt.wantExt)
+			}
+		})
+	}
+
+	signer = newTestSigner(2)
+	signer.Profiles = []*mgmtv1.Profile{{Name: "basic-constraints", Extensions: []*mgmtv1.Extension{{Oid: "2.5.29.19", Value: der}}}}
+	if _, err := s.CreateSigner(context.Background(), connect.NewRequest(&mgmtv1.CreateSignerRequest{Signer: signer})); connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("CreateSigner() with a 2.5.29 extension = %v, want InvalidArgument", err)
+	}
+}
+
+func TestSignIntermediatePathLength(t *testing.T) {
+	s := newTestServer(t)
+	allow := true
+	for id, pathLen := range map[int64]*int32{1: nil, 2: proto.Int32(0), 3: proto.Int32(2)} {
+		signer := newTestSigner(id)
+		signer.AllowCaIssuance = &allow
+		signer.GetInMem().Ca = &mgmtv1.CAConfig{MaxPathLen: pathLen}
+		signer.Profiles = []*mgmtv1.Profile{
+			{Name: "path-1", Ca: true, MaxPathLen: proto.Int32(1)},
+			{Name: "path-2", Ca: true, MaxPathLen: proto.Int32(2)},
+			{Name: "unconstrained", Ca: true},
+		}
+		createTestSigner(t, s, signer)
+	}
+
+	for _, tt := range []struct {
+		name        string
+		signerID    int64
+		profile     string
+		wantErr     bool
+		wantPathLen int
+	}{
+		{name: "unconstrained root", signerID: 1, profile: profile.IntermediateCA, wantPathLen: 0},
+		{name: "unconstrained root, unconstrained intermediate", signerID: 1, profile: "unconstrained", wantPathLen: -1},
+		{name: "path length zero", signerID: 2, profile: profile.IntermediateCA, wantErr: true},
+		{name: "shorter path length", signerID: 3, profile: "path-1", wantPathLen: 1},
+		{name: "equal path length", signerID: 3, profile: "path-2", wantErr: true},
+		{name: "unconstrained below a constrained CA", signerID: 3, profile: "unconstrained", wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.Sign(context.Background(), connect.NewRequest(&signv1.SignRequest{
+				SignerId: tt.signerID,
+				Csr:      newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "intermediate"}}),
+				Ca:       true,
+				Profile:  proto.String(tt.profile),
+			}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Sign() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			cert, err := x509.ParseCertificate(resp.Msg.Cert)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cert.IsCA || cert.MaxPathLen != tt.wantPathLen || cert.MaxPathLenZero != (tt.wantPathLen == 0) {
+				t.Errorf("intermediate CA = %v, path length %d (zero %v), want %d", cert.IsCA, cert.MaxPathLen, cert.MaxPathLenZero, tt.wantPathLen)
+			}
+		})
+	}
+}
+
+func TestInMemSignerKeyTypes(t *testing.T) {
+	s := newTestServer(t)
+	size := func(n int64) *int64 { return &n }
+	for i, tt := range []struct {
+		name     string
+		keyType  mgmtv1.PrivateKeyType
+		keySize  *int64
+		wantAlgo x509.PublicKeyAlgorithm
+		wantBits int
+		// the config is refused by CreateSigner, or when it first issues
+		wantInvalid bool
+		wantSignErr bool
+	}{
+		{name: "EC default", keyType: mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC, wantAlgo: x509.ECDSA, wantBits: 256},
+		{name: "EC 384", keyType: mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC, keySize: size(384), wantAlgo: x509.ECDSA, wantBits: 384},
+		{name: "EC 521", keyType: mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC, keySize: size(521), wantAlgo: x509.ECDSA, wantBits: 521},
+		{name: "Ed25519", keyType: mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_ED25519, wantAlgo: x509.Ed25519},
+		{name: "RSA default", keyType: mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_RSA, wantAlgo: x509.RSA, wantBits: 2048},
+		{name: "unsupported EC size", keyType: mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC, keySize: size(255), wantSignErr: true},
+		{name: "missing key type", keyType: mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_UNSPECIFIED, wantInvalid: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			id := int64(i + 1)
+			signer := newTestSigner(id)
+			signer.GetInMem().Key = tt.keyType
+			signer.GetInMem().KeySize = tt.keySize
+			_, err := s.CreateSigner(context.Background(), connect.NewRequest(&mgmtv1.CreateSignerRequest{Signer: signer}))
+			if (connect.CodeOf(err) == connect.CodeInvalidArgument) != tt.wantInvalid {
+				t.Fatalf("CreateSigner() error = %v, wantInvalid %v", err, tt.wantInvalid)
+			}
+			if err != nil {
+				return
+			}
+			if tt.wantSignErr {
+				if _, err := s.Sign(context.Background(), connect.NewRequest(&signv1.SignRequest{
+					SignerId: id,
+					Csr:      newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "leaf"}}),
+				})); err == nil {
+					t.Error("Sign() succeeded with an unsupported key")
+				}
+				return
+			}
+			leaf, ca := issueTestLeaf(t, s, id)
+			if ca.PublicKeyAlgorithm != tt.wantAlgo {
+				t.Errorf("CA key algorithm = %s, want %s", ca.PublicKeyAlgorithm, tt.wantAlgo)
+			}
+			var bits int
+			switch key := ca.PublicKey.(type) {
+			case *ecdsa.PublicKey:
+				bits = key.Curve.Params().BitSize
+			case *rsa.PublicKey:
+				bits = key.N.BitLen()
+			}
+			if bits != tt.wantBits {
+				t.Errorf("CA key size = %d, want %d", bits, tt.wantBits)
+			}
+			if err := leaf.CheckSignatureFrom(ca); err != nil {
+				t.Errorf("leaf is not signed by the CA: %v", err)
+			}
+		})
+	}
+}
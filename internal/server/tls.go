@@ -0,0 +1,154 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+)
+
+// clientAuthRefreshInterval bounds how long a retired CA stays trusted for
+// client authentication after it leaves its signer's trust bundle.
+const clientAuthRefreshInterval = time.Minute
+
+// TLSConfig returns a TLS configuration serving cert that verifies client
+// certificates, when presented, against clientCAs and the trust bundles of
+// every loaded signer, so that workloads can authenticate with certificates
+// Northfoot issued them. Revoked client certificates are rejected. Callers
+// are only identified by certificates that TrustedClientChain accepts.
+func (s *Server) TLSConfig(cert tls.Certificate, clientCAs []*x509.Certificate) *tls.Config {
+	s.clientCAs = clientCAs
+	base := &tls.Config{
+		Certificates:     []tls.Certificate{cert},
+		ClientAuth:       tls.VerifyClientCertIfGiven,
+		NextProtos:       []string{"h2", "http/1.1"},
+		MinVersion:       tls.VersionTLS12,
+		VerifyConnection: s.verifyClientNotRevoked,
+	}
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		config := base.Clone()
+		config.GetConfigForClient = nil
+		config.ClientCAs = s.currentClientAuth().pool
+		return config, nil
+	}
+	return base
+}
+
+// clientAuth is the client certificate trust derived from the loaded
+// signers.
+type clientAuth struct {
+	generation uint64
+	built      time.Time
+	// verifies the handshake of every client certificate Northfoot issued,
+	// which EST needs even for signers that are not trusted for the API
+	pool *x509.CertPool
+	// the trust domain of the signer each issuing CA belongs to, by raw
+	// certificate, or "" if the signer is not trusted for client
+	// authentication
+	trustDomains map[string]string
+}
+
+// currentClientAuth returns the client certificate trust for the current
+// signers, rebuilding it only when they have changed.
+func (s *Server) currentClientAuth() *clientAuth {
+	generation := s.signerGeneration.Load()
+	if current := s.clientAuth.Load(); current != nil && current.generation == generation && time.Since(current.built) < clientAuthRefreshInterval {
+		return current
+	}
+	auth := &clientAuth{
+		generation:   generation,
+		built:        time.Now(),
+		pool:         x509.NewCertPool(),
+		trustDomains: make(map[string]string),
+	}
+	for _, cert := range s.clientCAs {
+		auth.pool.AddCert(cert)
+	}
+	for _, signer := range s.signerCache.Load().(signerCache) {
+		trustDomain := ""
+		if signer.config.GetTrustForClientAuth() {
+			trustDomain = signerCAConfig(signer.config).GetTrustDomain()
+		}
+		for _, cert := range signer.TrustBundle() {
+			auth.pool.AddCert(cert)
+		}
+		for _, ca := range issuingCAs(signer.signer) {
+			auth.trustDomains[string(ca.Raw)] = trustDomain
+		}
+	}
+	s.clientAuth.Store(auth)
+	return auth
+}
+
+// TrustedClientChain accepts verified client certificate chains issued by a
+// signer trusted for client authentication, when the certificate is an
+// X.509-SVID in the signer's trust domain, and other chains that end at an
+// explicitly trusted client CA. Anyone able to get a certificate from other
+// signers could otherwise claim any identity.
+func (s *Server) TrustedClientChain(chain []*x509.Certificate) error {
+	issuer := chain[len(chain)-1]
+	if len(chain) > 1 {
+		issuer = chain[1]
+	}
+	trustDomain, fromSigner := s.currentClientAuth().trustDomains[string(issuer.Raw)]
+	if !fromSigner {
+		for _, ca := range s.clientCAs {
+			if ca.Equal(chain[len(chain)-1]) {
+				return nil
+			}
+		}
+		return errors.New("client certificate is not issued by a trusted CA")
+	}
+	if trustDomain == "" {
+		return errors.New("client certificate is issued by a signer that is not trusted for client authentication")
+	}
+	id, err := x509svid.IDFromCert(chain[0])
+	if err != nil {
+		return fmt.Errorf("client certificate is not an X.509-SVID: %w", err)
+	}
+	if id.TrustDomain().String() != trustDomain {
+		return fmt.Errorf("client certificate SPIFFE ID %s is outside of trust domain %s", id, trustDomain)
+	}
+	return nil
+}
+
+func (s *Server) verifyClientNotRevoked(state tls.ConnectionState) error {
+	if len(state.VerifiedChains) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	serial := serialString(state.VerifiedChains[0][0].SerialNumber)
+	var revokedAt int64
+	err := s.db.QueryRowContext(ctx, "SELECT revoked_at FROM revocations WHERE serial = ?", serial).Scan(&revokedAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to check revocation of client certificate: %w", err)
+	default:
+		return fmt.Errorf("client certificate %s is revoked", serial)
+	}
+}
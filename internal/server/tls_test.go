@@ -0,0 +1,139 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/connect-go"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	signv1 "github.com/jakexks/northfoot/api/sign/v1"
+	"github.com/jakexks/northfoot/internal/util"
+)
+
+// issueTestChain issues a certificate for uri from a signer and returns it
+// with the signer's CA.
+func issueTestChain(t *testing.T, s *Server, signerID int64, uri string) []*x509.Certificate {
+	t.Helper()
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.Sign(context.Background(), connect.NewRequest(&signv1.SignRequest{
+		SignerId: signerID,
+		Csr:      newTestCSR(t, &x509.CertificateRequest{URIs: []*url.URL{u}}),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(resp.Msg.Cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := s.getSigner(context.Background(), signerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []*x509.Certificate{leaf, cached.TrustBundle()[0]}
+}
+
+func TestTrustedClientChain(t *testing.T) {
+	s := newTestServer(t)
+	trusted := newTestSigner(1)
+	trustDomain := "edge"
+	trusted.GetInMem().Ca = &mgmtv1.CAConfig{TrustDomain: &trustDomain}
+	allow := true
+	trusted.TrustForClientAuth = &allow
+	createTestSigner(t, s, trusted)
+	createTestSigner(t, s, newTestSigner(2))
+
+	tests := []struct {
+		name     string
+		signerID int64
+		uri      string
+		wantErr  bool
+	}{
+		{"SVID in trust domain", 1, "spiffe://edge/ns/a/sa/b", false},
+		{"SVID outside trust domain", 1, "spiffe://admin/ns/a/sa/b", true},
+		{"not an SVID", 1, "https://edge.example", true},
+		{"signer not trusted for client auth", 2, "spiffe://edge/ns/a/sa/b", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.TrustedClientChain(issueTestChain(t, s, tt.signerID, tt.uri))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TrustedClientChain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("explicit client CA", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ca, err := util.GenerateSelfSignedCA(key, util.CAOptions{Subject: pkix.Name{CommonName: "client CA"}, Validity: time.Hour, MaxPathLen: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "client"},
+			NotAfter:     time.Now().Add(time.Hour),
+		}, ca, key.Public(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaf, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		chain := []*x509.Certificate{leaf, ca}
+		if err := s.TrustedClientChain(chain); err == nil {
+			t.Error("TrustedClientChain() accepted a chain to an unknown CA")
+		}
+		s.clientCAs = []*x509.Certificate{ca}
+		defer func() { s.clientCAs = nil }()
+		if err := s.TrustedClientChain(chain); err != nil {
+			t.Errorf("TrustedClientChain() error = %v for a chain to an explicit client CA", err)
+		}
+	})
+}
+
+func TestClientAuthCached(t *testing.T) {
+	s := newTestServer(t)
+	createTestSigner(t, s, newTestSigner(1))
+	auth := s.currentClientAuth()
+	if s.currentClientAuth() != auth {
+		t.Error("client CAs were rebuilt without the signers changing")
+	}
+	createTestSigner(t, s, newTestSigner(2))
+	if s.currentClientAuth() == auth {
+		t.Error("client CAs were not rebuilt after a signer was added")
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package validation
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/internal/server/profile"
+)
+
+var (
+	ErrInvalidCAValidity    = errors.New("signer CA validity must be positive")
+	ErrInvalidCAPathLen     = errors.New("signer CA max path length must not be negative")
+	ErrInvalidTrustDomain   = errors.New("signer CA trust domain is invalid")
+	ErrInvalidCAIPAddress   = errors.New("signer CA IP address is invalid")
+	ErrInvalidCAExtKeyUsage = errors.New("signer CA extended key usage is invalid")
+	ErrInvalidConstraint    = errors.New("signer CA name constraint is invalid")
+)
+
+// caConfig returns the problems with the CA configuration of a signer, if any.
+func caConfig(c *mgmtv1.CAConfig) []string {
+	if c == nil {
+		return nil
+	}
+	var errs []string
+	if c.Validity != nil && c.Validity.AsDuration() <= 0 {
+		errs = append(errs, ErrInvalidCAValidity.Error())
+	}
+	if c.MaxPathLen != nil && *c.MaxPathLen < 0 {
+		errs = append(errs, ErrInvalidCAPathLen.Error())
+	}
+	if c.TrustDomain != nil {
+		if _, err := spiffeid.TrustDomainFromString(*c.TrustDomain); err != nil {
+			errs = append(errs, ErrInvalidTrustDomain.Error()+": "+*c.TrustDomain)
+		}
+	}
+	for _, ip := range c.IpAddresses {
+		if net.ParseIP(ip) == nil {
+			errs = append(errs, ErrInvalidCAIPAddress.Error()+": "+ip)
+		}
+	}
+	for _, eku := range c.ExtKeyUsages {
+		if _, ok := profile.ExtKeyUsage(eku); !ok {
+			errs = append(errs, ErrInvalidCAExtKeyUsage.Error()+": "+eku.String())
+		}
+	}
+	return append(errs, nameConstraints(c.NameConstraints)...)
+}
+
+// caTrustDomain returns the trust domain of the CA a signer generates, if any.
+func caTrustDomain(s *mgmtv1.Signer) string {
+	switch {
+	case s.GetInMem() != nil:
+		return s.GetInMem().GetCa().GetTrustDomain()
+	case s.GetFile() != nil:
+		return s.GetFile().GetCa().GetTrustDomain()
+	case s.GetHsm() != nil:
+		return s.GetHsm().GetCa().GetTrustDomain()
+	case s.GetRemote().GetNorthfoot() != nil:
+		return s.GetRemote().GetNorthfoot().GetCa().GetTrustDomain()
+	}
+	return ""
+}
+
+// nameConstraints returns the problems with a CA's name constraints, if any.
+func nameConstraints(n *mgmtv1.NameConstraints) []string {
+	if n == nil {
+		return nil
+	}
+	var errs []string
+	for _, list := range [][]string{n.PermittedDnsDomains, n.ExcludedDnsDomains, n.PermittedUriDomains, n.ExcludedUriDomains} {
+		for _, d := range list {
+			if d == "" || d == "." || strings.ContainsAny(d, "*/:@") {
+				errs = append(errs, ErrInvalidConstraint.Error()+": "+d)
+			}
+		}
+	}
+	for _, list := range [][]string{n.PermittedIpRanges, n.ExcludedIpRanges} {
+		for _, r := range list {
+			if _, _, err := net.ParseCIDR(r); err != nil {
+				errs = append(errs, ErrInvalidConstraint.Error()+": "+r)
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,111 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package validation
+
+import (
+	"errors"
+	"net"
+	"path"
+	"strings"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/internal/authn"
+	"github.com/jakexks/northfoot/internal/server/expr"
+)
+
+var (
+	ErrInvalidPattern     = errors.New("signer policy pattern is invalid")
+	ErrInvalidCIDR        = errors.New("signer policy IP range is not valid CIDR notation")
+	ErrInvalidLifetime    = errors.New("signer policy lifetimes must be positive, with the default no longer than the maximum")
+	ErrInvalidSubjectRule = errors.New("signer policy subject rule field is missing")
+	ErrInvalidTemplate    = errors.New("signer policy identity rule template is invalid")
+	ErrInvalidRuleName    = errors.New("signer policy CEL rule names must be set and unique")
+)
+
+// policy returns the problems with an issuance policy, if any.
+func policy(p *mgmtv1.IssuancePolicy) []string {
+	if p == nil {
+		return nil
+	}
+	var errs []string
+	domains := append(append(append(append([]string{}, p.AllowedDnsNames...), p.DeniedDnsNames...), p.AllowedEmailDomains...), p.DeniedEmailDomains...)
+	for _, domain := range domains {
+		if domain == "" || strings.Contains(strings.TrimPrefix(domain, "*."), "*") {
+			errs = append(errs, ErrInvalidPattern.Error()+": "+domain)
+		}
+	}
+	globs := append(append([]string{}, p.AllowedUris...), p.DeniedUris...)
+	for _, rule := range p.SubjectRules {
+		if rule.Field == mgmtv1.SubjectField_SUBJECT_FIELD_UNSPECIFIED {
+			errs = append(errs, ErrInvalidSubjectRule.Error())
+		}
+		globs = append(globs, rule.AllowedValues...)
+	}
+	for _, glob := range globs {
+		if _, err := path.Match(glob, ""); err != nil || glob == "" {
+			errs = append(errs, ErrInvalidPattern.Error()+": "+glob)
+		}
+	}
+	for _, cidr := range append(append([]string{}, p.AllowedIpRanges...), p.DeniedIpRanges...) {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errs = append(errs, ErrInvalidCIDR.Error()+": "+cidr)
+		}
+	}
+	if (p.MaxLifetime != nil && p.MaxLifetime.AsDuration() <= 0) ||
+		(p.DefaultLifetime != nil && p.DefaultLifetime.AsDuration() <= 0) ||
+		(p.MaxLifetime != nil && p.DefaultLifetime != nil && p.DefaultLifetime.AsDuration() > p.MaxLifetime.AsDuration()) {
+		errs = append(errs, ErrInvalidLifetime.Error())
+	}
+	for _, rule := range p.IdentityRules {
+		errs = append(errs, identityRule(rule)...)
+	}
+	names := make(map[string]bool)
+	for _, rule := range p.CelRules {
+		if rule.Name == "" || names[rule.Name] {
+			errs = append(errs, ErrInvalidRuleName.Error())
+		}
+		names[rule.Name] = true
+		if _, err := expr.Compile(rule.Name, rule.Expression); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return errs
+}
+
+// identityRule checks that the templates of rule only use variables
+// captured from the identity.
+func identityRule(rule *mgmtv1.IdentityRule) []string {
+	identity, err := authn.ParseIdentityTemplate(rule.Identity)
+	if err != nil || rule.Identity == "" {
+		return []string{ErrInvalidTemplate.Error() + ": " + rule.Identity}
+	}
+	captured := make(map[string]bool)
+	for _, name := range identity.Names() {
+		captured[name] = true
+	}
+	var errs []string
+	templates := append(append(append(append([]string{}, rule.DnsNames...), rule.Uris...), rule.EmailAddresses...), rule.CommonNames...)
+	for _, template := range templates {
+		for _, name := range authn.TemplateVariables(template) {
+			if !captured[name] {
+				errs = append(errs, ErrInvalidTemplate.Error()+": "+template+" uses {"+name+"}, which "+rule.Identity+" does not capture")
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,51 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package validation
+
+import (
+	"errors"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/internal/server/profile"
+)
+
+var (
+	ErrDuplicateProfile = errors.New("signer profile names must be unique")
+	ErrMissingProfile   = errors.New("signer default profile does not exist")
+)
+
+// profiles returns the problems with a signer's profiles, if any.
+func profiles(s *mgmtv1.Signer) []string {
+	var errs []string
+	names := make(map[string]bool)
+	for _, p := range s.Profiles {
+		if names[p.Name] {
+			errs = append(errs, ErrDuplicateProfile.Error()+": "+p.Name)
+		}
+		names[p.Name] = true
+		if _, err := profile.Parse(p); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if s.DefaultProfile != nil {
+		if _, builtin := profile.Builtin(*s.DefaultProfile); !builtin && !names[*s.DefaultProfile] {
+			errs = append(errs, ErrMissingProfile.Error()+": "+*s.DefaultProfile)
+		}
+	}
+	return errs
+}
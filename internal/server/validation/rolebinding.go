@@ -0,0 +1,52 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package validation
+
+import (
+	"errors"
+	"path"
+	"strings"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+)
+
+var (
+	ErrNilRoleBinding  = errors.New("role binding is nil")
+	ErrMissingIdentity = errors.New("role binding identity is missing")
+	ErrInvalidIdentity = errors.New("role binding identity is not a valid glob")
+	ErrMissingRole     = errors.New("role binding role is missing")
+)
+
+func RoleBinding(b *mgmtv1.RoleBinding) error {
+	if b == nil {
+		return ErrNilRoleBinding
+	}
+	var errs []string
+	if b.Identity == "" {
+		errs = append(errs, ErrMissingIdentity.Error())
+	} else if _, err := path.Match(b.Identity, ""); err != nil {
+		errs = append(errs, ErrInvalidIdentity.Error())
+	}
+	if _, ok := mgmtv1.Role_name[int32(b.Role)]; !ok || b.Role == mgmtv1.Role_ROLE_UNSPECIFIED {
+		errs = append(errs, ErrMissingRole.Error())
+	}
+	if len(errs) > 0 {
+		return errors.New("role binding validation failed: " + strings.Join(errs, ", "))
+	}
+	return nil
+}
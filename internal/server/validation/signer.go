@@ -8,11 +8,27 @@ import (
 )
 
 var (
-	ErrNilSigner      = errors.New("signer is nil")
-	ErrMissingID      = errors.New("signer id is missing")
-	ErrMissingType    = errors.New("signer type is missing")
-	ErrNilConfig      = errors.New("signer config is nil")
-	ErrMissingKeyType = errors.New("signer key type is missing")
+	ErrNilSigner       = errors.New("signer is nil")
+	ErrMissingID       = errors.New("signer id is missing")
+	ErrMissingType     = errors.New("signer type is missing")
+	ErrNilConfig       = errors.New("signer config is nil")
+	ErrMissingKeyType  = errors.New("signer key type is missing")
+	ErrConfigMismatch  = errors.New("signer config does not match signer type")
+	ErrMissingPath     = errors.New("signer file path is missing")
+	ErrMissingLibrary  = errors.New("signer HSM library path is missing")
+	ErrMissingToken    = errors.New("signer HSM token serial or label is required")
+	ErrAmbiguousToken  = errors.New("signer HSM token serial and label are mutually exclusive")
+	ErrHSMKeyType      = errors.New("signer HSM key type must be RSA or EC")
+	ErrMissingRemote   = errors.New("signer remote type is missing")
+	ErrMissingURL      = errors.New("signer remote endpoint is missing")
+	ErrInsecureURL     = errors.New("signer remote URLs must use https")
+	ErrInsecureToken   = errors.New("signer remote token requires an https endpoint")
+	ErrInvalidInterval = errors.New("signer refresh interval must be positive")
+	ErrInvalidRotation = errors.New("signer rotation interval must be positive and overlap must not be negative")
+	ErrNotRotatable    = errors.New("signer rotation is only supported by in memory, file and remote Northfoot signers")
+	ErrNotCrossSigned  = errors.New("signer cross-signing is only supported by in memory signers, whose CA is a root")
+	ErrNotPersistable  = errors.New("signer CA key persistence is only supported by in memory, file and remote Northfoot signers")
+	ErrNoClientAuthTD  = errors.New("signer client authentication requires a CA trust domain")
 )
 
 func Signer(s *mgmtv1.Signer) error {
@@ -29,6 +45,117 @@ func Signer(s *mgmtv1.Signer) error {
 	if s.GetSignerConfig() == nil {
 		errs = append(errs, ErrNilConfig.Error())
 	}
+	switch s.Type {
+	case mgmtv1.SignerType_SIGNER_TYPE_INMEM:
+		if s.GetInMem() == nil {
+			errs = append(errs, ErrConfigMismatch.Error())
+			break
+		}
+		if s.GetInMem().Key == mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_UNSPECIFIED {
+			errs = append(errs, ErrMissingKeyType.Error())
+		}
+		errs = append(errs, caConfig(s.GetInMem().Ca)...)
+	case mgmtv1.SignerType_SIGNER_TYPE_FILE:
+		if s.GetFile() == nil {
+			errs = append(errs, ErrConfigMismatch.Error())
+			break
+		}
+		if s.GetFile().TlsCertFilePath == "" || s.GetFile().TlsKeyFilePath == "" {
+			errs = append(errs, ErrMissingPath.Error())
+		}
+		if s.GetFile().Key == mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_UNSPECIFIED {
+			errs = append(errs, ErrMissingKeyType.Error())
+		}
+		errs = append(errs, caConfig(s.GetFile().Ca)...)
+	case mgmtv1.SignerType_SIGNER_TYPE_HSM:
+		hsm := s.GetHsm()
+		if hsm == nil {
+			errs = append(errs, ErrConfigMismatch.Error())
+			break
+		}
+		if hsm.HsmLibraryPath == "" {
+			errs = append(errs, ErrMissingLibrary.Error())
+		}
+		if hsm.GetHsmTokenSerial() == "" && hsm.GetHsmTokenLabel() == "" {
+			errs = append(errs, ErrMissingToken.Error())
+		}
+		if hsm.GetHsmTokenSerial() != "" && hsm.GetHsmTokenLabel() != "" {
+			errs = append(errs, ErrAmbiguousToken.Error())
+		}
+		switch hsm.Key {
+		case mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_UNSPECIFIED:
+			errs = append(errs, ErrMissingKeyType.Error())
+		case mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_RSA, mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_EC:
+		default:
+			errs = append(errs, ErrHSMKeyType.Error())
+		}
+		errs = append(errs, caConfig(hsm.Ca)...)
+	case mgmtv1.SignerType_SIGNER_TYPE_REMOTE:
+		remote := s.GetRemote()
+		if remote == nil {
+			errs = append(errs, ErrConfigMismatch.Error())
+			break
+		}
+		switch remote.RemoteType {
+		case mgmtv1.RemoteType_REMOTE_TYPE_UNSPECIFIED:
+			errs = append(errs, ErrMissingRemote.Error())
+		case mgmtv1.RemoteType_REMOTE_TYPE_NORTHFOOT:
+			if remote.GetNorthfoot() == nil {
+				errs = append(errs, ErrConfigMismatch.Error())
+				break
+			}
+			if remote.GetNorthfoot().Endpoint == "" {
+				errs = append(errs, ErrMissingURL.Error())
+			}
+			if remote.GetNorthfoot().Token != nil && !strings.HasPrefix(remote.GetNorthfoot().Endpoint, "https://") {
+				errs = append(errs, ErrInsecureToken.Error())
+			}
+			if (remote.GetNorthfoot().ClientCertFilePath == nil) != (remote.GetNorthfoot().ClientKeyFilePath == nil) {
+				errs = append(errs, ErrMissingPath.Error())
+			}
+			if remote.GetNorthfoot().Key == mgmtv1.PrivateKeyType_PRIVATE_KEY_TYPE_UNSPECIFIED {
+				errs = append(errs, ErrMissingKeyType.Error())
+			}
+			errs = append(errs, caConfig(remote.GetNorthfoot().Ca)...)
+		case mgmtv1.RemoteType_REMOTE_TYPE_VERBATIM_HTTPS:
+			verbatim := remote.GetVerbatimHttps()
+			if verbatim == nil {
+				errs = append(errs, ErrConfigMismatch.Error())
+				break
+			}
+			if verbatim.CertUrl == "" || verbatim.KeyUrl == "" {
+				errs = append(errs, ErrMissingURL.Error())
+			}
+			if !strings.HasPrefix(verbatim.CertUrl, "https://") || !strings.HasPrefix(verbatim.KeyUrl, "https://") {
+				errs = append(errs, ErrInsecureURL.Error())
+			}
+			if (verbatim.ClientCertFilePath == nil) != (verbatim.ClientKeyFilePath == nil) {
+				errs = append(errs, ErrMissingPath.Error())
+			}
+			if verbatim.RefreshInterval != nil && verbatim.RefreshInterval.AsDuration() <= 0 {
+				errs = append(errs, ErrInvalidInterval.Error())
+			}
+		}
+	}
+	if r := s.Rotation; r != nil {
+		if (r.Interval != nil && r.Interval.AsDuration() <= 0) || (r.Overlap != nil && r.Overlap.AsDuration() < 0) {
+			errs = append(errs, ErrInvalidRotation.Error())
+		}
+		if s.Type == mgmtv1.SignerType_SIGNER_TYPE_HSM || s.GetRemote().GetVerbatimHttps() != nil {
+			errs = append(errs, ErrNotRotatable.Error())
+		}
+		if r.CrossSign && s.Type != mgmtv1.SignerType_SIGNER_TYPE_INMEM {
+			errs = append(errs, ErrNotCrossSigned.Error())
+		}
+	}
+	if s.GetPersistCaKey() && (s.Type == mgmtv1.SignerType_SIGNER_TYPE_HSM || s.GetRemote().GetVerbatimHttps() != nil) {
+		errs = append(errs, ErrNotPersistable.Error())
+	}
+	if s.GetTrustForClientAuth() && caTrustDomain(s) == "" {
+		errs = append(errs, ErrNoClientAuthTD.Error())
+	}
+	errs = append(errs, policy(s.Policy)...)
+	errs = append(errs, profiles(s)...)
 	if len(errs) > 0 {
 		return errors.New("signer validation failed: " + strings.Join(errs, ", "))
 	}
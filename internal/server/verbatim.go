@@ -0,0 +1,178 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ocsp"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+	"github.com/jakexks/northfoot/internal/util"
+)
+
+const (
+	defaultVerbatimRefreshInterval = time.Hour
+	maxVerbatimResponseSize        = 1 << 20
+)
+
+// verbatimSigner issues leaf certificates directly from a CA cert and key
+// downloaded over HTTPS. The cert and key are re-fetched periodically so
+// that rotations are picked up without restarting the server.
+type verbatimSigner struct {
+	config  *mgmtv1.RemoteVerbatimHttpsConfig
+	client  *http.Client
+	log     *zap.Logger
+	current atomic.Value // *inMemSigner
+	stop    chan struct{}
+}
+
+func newVerbatimSigner(ctx context.Context, config *mgmtv1.RemoteVerbatimHttpsConfig, log *zap.Logger) (signer, error) {
+	client, err := newUpstreamClient(config.CaFilePath, config.ClientCertFilePath, config.ClientKeyFilePath)
+	if err != nil {
+		return nil, err
+	}
+	v := &verbatimSigner{
+		config: config,
+		client: client,
+		log:    log,
+		stop:   make(chan struct{}),
+	}
+	current, err := v.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.current.Store(current)
+	interval := defaultVerbatimRefreshInterval
+	if config.RefreshInterval != nil {
+		interval = config.RefreshInterval.AsDuration()
+	}
+	go v.refresh(interval)
+	return v, nil
+}
+
+func (v *verbatimSigner) refresh(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-v.stop:
+			return
+		case <-ticker.C:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		next, err := v.fetch(ctx)
+		cancel()
+		if err != nil {
+			v.log.Error("failed to refresh verbatim signer, continuing with previous CA", zap.String("cert_url", v.config.CertUrl), zap.Error(err))
+			continue
+		}
+		if bytes.Equal(next.cert.Raw, v.current.Load().(*inMemSigner).cert.Raw) {
+			continue
+		}
+		v.log.Info("verbatim signer CA rotated", zap.String("cert_url", v.config.CertUrl), zap.String("serial", next.cert.SerialNumber.String()))
+		v.current.Store(next)
+	}
+}
+
+func (v *verbatimSigner) fetch(ctx context.Context) (*inMemSigner, error) {
+	certPEM, err := v.get(ctx, v.config.CertUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch CA certificate: %w", err)
+	}
+	keyPEM, err := v.get(ctx, v.config.KeyUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch CA key: %w", err)
+	}
+	certs, err := util.ParseCertificatesPEM(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	key, err := util.ParsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	cert := certs[0]
+	if !cert.IsCA {
+		return nil, errors.New("fetched certificate is not a CA")
+	}
+	if cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return nil, errors.New("fetched certificate is not allowed to sign certificates")
+	}
+	match, err := util.KeyMatchesCertificate(key, cert)
+	if err != nil {
+		return nil, err
+	}
+	if !match {
+		return nil, errors.New("fetched key does not match fetched certificate")
+	}
+	return &inMemSigner{
+		key:   key,
+		cert:  cert,
+		chain: certs[1:],
+	}, nil
+}
+
+func (v *verbatimSigner) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q from %s", resp.Status, url)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxVerbatimResponseSize))
+}
+
+func (v *verbatimSigner) Sign(ctx context.Context, csr *x509.CertificateRequest, opts signOptions) (*x509.Certificate, error) {
+	return v.current.Load().(*inMemSigner).Sign(ctx, csr, opts)
+}
+
+func (v *verbatimSigner) TrustBundle() []*x509.Certificate {
+	return v.current.Load().(*inMemSigner).TrustBundle()
+}
+
+func (v *verbatimSigner) CRL(revoked []pkix.RevokedCertificate, number *big.Int, nextUpdate time.Time) ([]byte, error) {
+	return v.current.Load().(*inMemSigner).CRL(revoked, number, nextUpdate)
+}
+
+func (v *verbatimSigner) OCSPResponse(template ocsp.Response) ([]byte, error) {
+	return v.current.Load().(*inMemSigner).OCSPResponse(template)
+}
+
+// Close stops the background refresh.
+func (v *verbatimSigner) Close() error {
+	close(v.stop)
+	return nil
+}
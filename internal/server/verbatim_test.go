@@ -0,0 +1,196 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	mgmtv1 "github.com/jakexks/northfoot/api/mgmt/v1"
+)
+
+// verbatimUpstream serves a PEM certificate at /ca.crt and key at /ca.key
+// that can be replaced while it runs.
+type verbatimUpstream struct {
+	lock sync.Mutex
+	cert []byte
+	key  []byte
+}
+
+func (u *verbatimUpstream) set(t *testing.T, certs []*x509.Certificate, key crypto.Signer) {
+	t.Helper()
+	var certPEM []byte
+	for _, cert := range certs {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.cert = certPEM
+	u.key = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+func (u *verbatimUpstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	switch r.URL.Path {
+	case "/ca.crt":
+		w.Write(u.cert)
+	case "/ca.key":
+		w.Write(u.key)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestVerbatimSigner(t *testing.T) {
+	upstream := &verbatimUpstream{}
+	hs := httptest.NewUnstartedServer(upstream)
+	hs.Config.ErrorLog = log.New(io.Discard, "", 0)
+	hs.StartTLS()
+	defer hs.Close()
+	caPath := filepath.Join(t.TempDir(), "upstream.crt")
+	if err := os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: hs.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config := func() *mgmtv1.RemoteVerbatimHttpsConfig {
+		return &mgmtv1.RemoteVerbatimHttpsConfig{
+			CertUrl:    hs.URL + "/ca.crt",
+			KeyUrl:     hs.URL + "/ca.key",
+			CaFilePath: &caPath,
+		}
+	}
+
+	ca, caKey := newTestCA(t)
+	_, otherKey := newTestCA(t)
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, &x509.Certificate{Subject: pkix.Name{CommonName: "leaf"}}, leafKey.Public(), leafKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(leafDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		certs   []*x509.Certificate
+		key     crypto.Signer
+		config  func(c *mgmtv1.RemoteVerbatimHttpsConfig)
+		wantErr bool
+	}{
+		{name: "fetches the CA", certs: []*x509.Certificate{ca}, key: caKey},
+		{name: "key does not match the certificate", certs: []*x509.Certificate{ca}, key: otherKey, wantErr: true},
+		{name: "certificate is not a CA", certs: []*x509.Certificate{leaf}, key: leafKey, wantErr: true},
+		{name: "upstream is not trusted", certs: []*x509.Certificate{ca}, key: caKey, config: func(c *mgmtv1.RemoteVerbatimHttpsConfig) { c.CaFilePath = nil }, wantErr: true},
+		{name: "key is missing", certs: []*x509.Certificate{ca}, key: caKey, config: func(c *mgmtv1.RemoteVerbatimHttpsConfig) { c.KeyUrl = hs.URL + "/missing.key" }, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream.set(t, tt.certs, tt.key)
+			c := config()
+			if tt.config != nil {
+				tt.config(c)
+			}
+			si, err := newVerbatimSigner(context.Background(), c, zap.NewNop())
+			if tt.wantErr {
+				if err == nil {
+					si.(*verbatimSigner).Close()
+					t.Fatal("created the signer")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer si.(*verbatimSigner).Close()
+			if !si.TrustBundle()[0].Equal(ca) {
+				t.Error("trust bundle is not the fetched CA")
+			}
+			csr, err := x509.ParseCertificateRequest(newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "leaf.example.com"}}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			issued, err := si.Sign(context.Background(), csr, signOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := issued.CheckSignatureFrom(ca); err != nil {
+				t.Errorf("leaf is not signed by the fetched CA: %v", err)
+			}
+		})
+	}
+
+	t.Run("refresh picks up a rotated CA", func(t *testing.T) {
+		upstream.set(t, []*x509.Certificate{ca}, caKey)
+		c := config()
+		c.RefreshInterval = durationpb.New(10 * time.Millisecond)
+		si, err := newVerbatimSigner(context.Background(), c, zap.NewNop())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer si.(*verbatimSigner).Close()
+
+		// a broken upstream keeps the previous CA
+		upstream.set(t, []*x509.Certificate{ca}, otherKey)
+		time.Sleep(50 * time.Millisecond)
+		if !si.TrustBundle()[0].Equal(ca) {
+			t.Fatal("CA was replaced by a mismatched key pair")
+		}
+
+		rotated, rotatedKey := newTestCA(t)
+		upstream.set(t, []*x509.Certificate{rotated}, rotatedKey)
+		deadline := time.Now().Add(5 * time.Second)
+		for !si.TrustBundle()[0].Equal(rotated) {
+			if time.Now().After(deadline) {
+				t.Fatal("rotated CA was not picked up")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
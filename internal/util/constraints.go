@@ -0,0 +1,131 @@
+/*
+Copyright (C) 2022 Jake Sanders
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package util
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"net"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyte_asn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+var oidExtensionNameConstraints = asn1.ObjectIdentifier{2, 5, 29, 30}
+
+// NameConstraints restricts the names a CA can issue for.
+type NameConstraints struct {
+	PermittedDNSDomains []string
+	ExcludedDNSDomains  []string
+	PermittedIPRanges   []*net.IPNet
+	ExcludedIPRanges    []*net.IPNet
+	PermittedURIDomains []string
+	ExcludedURIDomains  []string
+}
+
+// Empty reports whether n constrains nothing.
+func (n NameConstraints) Empty() bool {
+	return len(n.PermittedDNSDomains) == 0 && len(n.ExcludedDNSDomains) == 0 &&
+		len(n.PermittedIPRanges) == 0 && len(n.ExcludedIPRanges) == 0 &&
+		len(n.PermittedURIDomains) == 0 && len(n.ExcludedURIDomains) == 0
+}
+
+// CertificateNameConstraints returns the name constraints of cert.
+func CertificateNameConstraints(cert *x509.Certificate) NameConstraints {
+	return NameConstraints{
+		PermittedDNSDomains: cert.PermittedDNSDomains,
+		ExcludedDNSDomains:  cert.ExcludedDNSDomains,
+		PermittedIPRanges:   cert.PermittedIPRanges,
+		ExcludedIPRanges:    cert.ExcludedIPRanges,
+		PermittedURIDomains: cert.PermittedURIDomains,
+		ExcludedURIDomains:  cert.ExcludedURIDomains,
+	}
+}
+
+// RequestedNameConstraints returns the name constraints extension requested
+// by csr, if there is one.
+func RequestedNameConstraints(csr *x509.CertificateRequest) (pkix.Extension, bool) {
+	for _, ext := range csr.Extensions {
+		if ext.Id.Equal(oidExtensionNameConstraints) {
+			return ext, true
+		}
+	}
+	return pkix.Extension{}, false
+}
+
+func (n NameConstraints) apply(template *x509.Certificate) {
+	if n.Empty() {
+		return
+	}
+	template.PermittedDNSDomainsCritical = true
+	template.PermittedDNSDomains = n.PermittedDNSDomains
+	template.ExcludedDNSDomains = n.ExcludedDNSDomains
+	template.PermittedIPRanges = n.PermittedIPRanges
+	template.ExcludedIPRanges = n.ExcludedIPRanges
+	template.PermittedURIDomains = n.PermittedURIDomains
+	template.ExcludedURIDomains = n.ExcludedURIDomains
+}
+
+// extension encodes n as a critical name constraints extension, for CSRs
+// which cannot carry them otherwise.
+func (n NameConstraints) extension() (pkix.Extension, error) {
+	subtrees := func(b *cryptobyte.Builder, tag cryptobyte_asn1.Tag, dns []string, ips []*net.IPNet, uris []string) {
+		if len(dns) == 0 && len(ips) == 0 && len(uris) == 0 {
+			return
+		}
+		b.AddASN1(tag.ContextSpecific().Constructed(), func(b *cryptobyte.Builder) {
+			for _, d := range dns {
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1(cryptobyte_asn1.Tag(2).ContextSpecific(), func(b *cryptobyte.Builder) {
+						b.AddBytes([]byte(d))
+					})
+				})
+			}
+			for _, ipNet := range ips {
+				ip := ipNet.IP
+				if ip4 := ip.To4(); ip4 != nil && len(ipNet.Mask) == net.IPv4len {
+					ip = ip4
+				}
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1(cryptobyte_asn1.Tag(7).ContextSpecific(), func(b *cryptobyte.Builder) {
+						b.AddBytes(ip)
+						b.AddBytes(ipNet.Mask)
+					})
+				})
+			}
+			for _, u := range uris {
+				b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1(cryptobyte_asn1.Tag(6).ContextSpecific(), func(b *cryptobyte.Builder) {
+						b.AddBytes([]byte(u))
+					})
+				})
+			}
+		})
+	}
+	var b cryptobyte.Builder
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		subtrees(b, 0, n.PermittedDNSDomains, n.PermittedIPRanges, n.PermittedURIDomains)
+		subtrees(b, 1, n.ExcludedDNSDomains, n.ExcludedIPRanges, n.ExcludedURIDomains)
+	})
+	value, err := b.Bytes()
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+	return pkix.Extension{Id: oidExtensionNameConstraints, Critical: true, Value: value}, nil
+}
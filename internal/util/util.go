@@ -25,6 +25,7 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"errors"
 	"fmt"
 	"math/big"
 	"net"
@@ -32,27 +33,29 @@ import (
 	"time"
 )
 
-func GenerateSelfSignedCA(key crypto.Signer) (*x509.Certificate, error) {
+// CAOptions describes a CA certificate to generate.
+type CAOptions struct {
+	Subject  pkix.Name
+	Validity time.Duration
+	// MaxPathLen constrains the CA, or is -1 for no constraint.
+	MaxPathLen  int
+	DNSNames    []string
+	IPAddresses []net.IP
+	URIs        []*url.URL
+	ExtKeyUsage []x509.ExtKeyUsage
+	NameConstraints
+}
+
+// GenerateSelfSignedCA creates a self-signed CA certificate for key.
+func GenerateSelfSignedCA(key crypto.Signer, opts CAOptions) (*x509.Certificate, error) {
 	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate serial number: %w", err)
 	}
 
-	var keyAlgo x509.PublicKeyAlgorithm
-
-	switch k := key.(type) {
-	case *rsa.PrivateKey:
-		keyAlgo = x509.RSA
-	case *ecdsa.PrivateKey:
-		keyAlgo = x509.ECDSA
-	case ed25519.PrivateKey:
-		keyAlgo = x509.Ed25519
-	default:
-		return nil, fmt.Errorf("unsupported key type: %T", k)
-	}
-	spiffeID, err := url.Parse("spiffe://northfoot/ca")
+	keyAlgo, err := publicKeyAlgorithm(key)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse spiffeID: %w", err)
+		return nil, err
 	}
 	template := &x509.Certificate{
 		Version:               2,
@@ -60,22 +63,19 @@ func GenerateSelfSignedCA(key crypto.Signer) (*x509.Certificate, error) {
 		SerialNumber:          serialNumber,
 		PublicKeyAlgorithm:    keyAlgo,
 		IsCA:                  true,
-		Subject: pkix.Name{
-			Country:            []string{"GB"},
-			Organization:       []string{"Northfoot"},
-			OrganizationalUnit: []string{"Development self-signed CA"},
-			SerialNumber:       serialNumber.String(),
-			CommonName:         "Northfoot Development Self-Signed CA",
-		},
-		NotBefore: time.Now(),
-		NotAfter:  time.Now().Add(time.Hour * 24 * 365 * 10),
+		MaxPathLen:            opts.MaxPathLen,
+		MaxPathLenZero:        opts.MaxPathLen == 0,
+		Subject:               opts.Subject,
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(opts.Validity),
 		// see http://golang.org/pkg/crypto/x509/#KeyUsage
-		KeyUsage:    x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature | x509.KeyUsageDataEncipherment,
-		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageCodeSigning, x509.ExtKeyUsageEmailProtection, x509.ExtKeyUsageOCSPSigning},
-		DNSNames:    []string{"localhost"},
-		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
-		URIs:        []*url.URL{spiffeID},
+		KeyUsage:    x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: opts.ExtKeyUsage,
+		DNSNames:    opts.DNSNames,
+		IPAddresses: opts.IPAddresses,
+		URIs:        opts.URIs,
 	}
+	opts.NameConstraints.apply(template)
 
 	cert, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
 	if err != nil {
@@ -83,3 +83,85 @@ func GenerateSelfSignedCA(key crypto.Signer) (*x509.Certificate, error) {
 	}
 	return x509.ParseCertificate(cert)
 }
+
+// CreateCACertificateRequest creates a DER encoded CSR for an intermediate CA
+// whose private key is key, with the subject, SANs and name constraints of
+// opts.
+func CreateCACertificateRequest(key crypto.Signer, opts CAOptions) ([]byte, error) {
+	template := &x509.CertificateRequest{
+		Subject:     opts.Subject,
+		DNSNames:    opts.DNSNames,
+		IPAddresses: opts.IPAddresses,
+		URIs:        opts.URIs,
+	}
+	if !opts.NameConstraints.Empty() {
+		ext, err := opts.NameConstraints.extension()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode name constraints: %w", err)
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, ext)
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate request: %w", err)
+	}
+	return csr, nil
+}
+
+// SignIntermediateCA signs csr as an intermediate CA using parent and
+// parentKey, with the subject and SANs of csr and the validity, path length,
+// extended key usages and name constraints of opts. The validity of the intermediate never
+// exceeds the parent's.
+func SignIntermediateCA(csr *x509.CertificateRequest, parent *x509.Certificate, parentKey crypto.Signer, opts CAOptions) (*x509.Certificate, error) {
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("CSR has invalid signature: %w", err)
+	}
+	if !parent.IsCA {
+		return nil, errors.New("parent certificate is not a CA")
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	notAfter := time.Now().Add(opts.Validity)
+	if parent.NotAfter.Before(notAfter) {
+		notAfter = parent.NotAfter
+	}
+	template := &x509.Certificate{
+		Version:               2,
+		BasicConstraintsValid: true,
+		SerialNumber:          serialNumber,
+		PublicKeyAlgorithm:    csr.PublicKeyAlgorithm,
+		PublicKey:             csr.PublicKey,
+		IsCA:                  true,
+		MaxPathLen:            opts.MaxPathLen,
+		MaxPathLenZero:        opts.MaxPathLen == 0,
+		Subject:               csr.Subject,
+		NotBefore:             time.Now(),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           opts.ExtKeyUsage,
+		DNSNames:              csr.DNSNames,
+		IPAddresses:           csr.IPAddresses,
+		URIs:                  csr.URIs,
+	}
+	opts.NameConstraints.apply(template)
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, csr.PublicKey, parentKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	return x509.ParseCertificate(der)
+}
+
+func publicKeyAlgorithm(key crypto.Signer) (x509.PublicKeyAlgorithm, error) {
+	switch k := key.Public().(type) {
+	case *rsa.PublicKey:
+		return x509.RSA, nil
+	case *ecdsa.PublicKey:
+		return x509.ECDSA, nil
+	case ed25519.PublicKey:
+		return x509.Ed25519, nil
+	default:
+		return x509.UnknownPublicKeyAlgorithm, fmt.Errorf("unsupported key type: %T", k)
+	}
+}